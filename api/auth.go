@@ -0,0 +1,64 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gunh0/openstack-security-hub/auth"
+)
+
+// viewerRoutes only read results and are open to viewers; every other route requires an operator
+// since it connects to the hosts or the OpenStack APIs, or changes the waivers or targets
+var viewerRoutes = map[string]bool{
+	"GET /api/v1/scans":                 true,
+	"GET /api/v1/scans/:id":             true,
+	"GET /api/v1/scans/:id/events":      true,
+	"GET /api/v1/scans/:id/diff/:other": true,
+	"GET /api/v1/waivers":               true,
+	"GET /api/v1/targets":               true,
+	"GET /api/v1/targets/:name":         true,
+	"GET /api/v1/benchmark":             true,
+}
+
+// requiredRole returns the role needed to call the route of c
+func requiredRole(c *gin.Context) auth.Role {
+	if viewerRoutes[c.Request.Method+" "+c.FullPath()] {
+		return auth.Viewer
+	}
+	return auth.Operator
+}
+
+// authenticate rejects the requests without a valid token for the role of their route.
+// The token is read from the Authorization bearer header or from X-Auth-Token, as with OpenStack.
+func authenticate(authenticator *auth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader("X-Auth-Token")
+		if bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+			token = strings.TrimSpace(bearer)
+		}
+
+		principal, err := authenticator.Authenticate(token)
+		if err != nil {
+			status := http.StatusUnauthorized
+			if !errors.Is(err, auth.ErrUnauthorized) {
+				status = http.StatusBadGateway
+			}
+			c.Header("WWW-Authenticate", `Bearer realm="openstack-security-hub"`)
+			c.AbortWithStatusJSON(status, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		if required := requiredRole(c); !principal.Role.Allows(required) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "this request requires the " + string(required) + " role",
+			})
+			return
+		}
+		c.Set("principal", principal)
+		c.Next()
+	}
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gunh0/openstack-security-hub/docs"
+	"github.com/gunh0/openstack-security-hub/scan"
+	"github.com/swaggo/swag"
+)
+
+// DocsInstance is the name of the Swagger document served with the check rationales
+const DocsInstance = "checks"
+
+func init() {
+	swag.Register(DocsInstance, checkDocs{docs.SwaggerInfo})
+}
+
+// checkDocs is the generated Swagger document with the rationale of each check as the description
+// of its route, so that the rationales are only written once, in the scan package
+type checkDocs struct {
+	spec *swag.Spec
+}
+
+// ReadDoc returns the generated document with the check descriptions filled in
+func (d checkDocs) ReadDoc() string {
+	doc := d.spec.ReadDoc()
+
+	var document map[string]interface{}
+	if err := json.Unmarshal([]byte(doc), &document); err != nil {
+		return doc
+	}
+	paths, _ := document["paths"].(map[string]interface{})
+	for path, item := range paths {
+		id, ok := strings.CutPrefix(path, "/check/")
+		if !ok {
+			continue
+		}
+		operation, _ := item.(map[string]interface{})["get"].(map[string]interface{})
+		if rationale := checkRationale(id); operation != nil && rationale != "" {
+			operation["description"] = rationale
+		}
+	}
+
+	content, err := json.MarshalIndent(document, "", "    ")
+	if err != nil {
+		return doc
+	}
+	return string(content)
+}
+
+// checkRationale returns the rationale of the check id, or of the group of checks starting with id
+// when they share one, e.g. identity-01
+func checkRationale(id string) string {
+	if check, ok := scan.Lookup(id); ok {
+		return check.Rationale()
+	}
+
+	rationale := ""
+	for _, check := range scan.Checks() {
+		if !strings.HasPrefix(check.ID, id+"-") {
+			continue
+		}
+		if rationale != "" && check.Rationale() != rationale {
+			return ""
+		}
+		rationale = check.Rationale()
+	}
+	return rationale
+}
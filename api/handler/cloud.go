@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/checklist/cloud"
+	"github.com/gunh0/openstack-security-hub/openstack"
+	"github.com/gunh0/openstack-security-hub/scan"
+	"github.com/gunh0/openstack-security-hub/util"
+)
+
+// RegisterCloudRoutes registers all cloud check routes
+func RegisterCloudRoutes(router *gin.RouterGroup) {
+	router.GET("/check/cloud", handleCloud)
+	router.GET("/check/cloud-01", checkCloud01)
+	router.GET("/check/cloud-02", checkCloud02)
+	router.GET("/check/cloud-03", checkCloud03)
+	router.GET("/check/cloud-04", checkCloud04)
+	router.GET("/check/cloud-05", checkCloud05)
+	router.GET("/check/cloud-06", checkCloud06)
+}
+
+// cloudChecker authenticates to the OpenStack APIs and returns a checker applying the profile query
+// parameter and stopping each check after CHECK_TIMEOUT or at the request deadline
+func cloudChecker(c *gin.Context) (*scan.CloudChecker, error) {
+	timeout, err := scan.CheckTimeoutFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	profile, err := queryProfile(c)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := util.GetOpenStackClient()
+	if err != nil {
+		return nil, err
+	}
+	return scan.NewCloudChecker(c.Request.Context(), client, timeout, profile), nil
+}
+
+// @Summary     Run all cloud checks
+// @Description Runs every cloud-level check through the OpenStack APIs instead of SSH. Credentials are taken from clouds.yaml (OS_CLOUD) or the OS_* environment variables.
+// @Tags        Cloud API
+// @Produce     json
+// @Success     200 {array}  checklist.CheckResult
+// @Param       profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/cloud [get]
+func handleCloud(c *gin.Context) {
+	checker, err := cloudChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	checks := []struct {
+		name string
+		fn   func(*openstack.Client) checklist.CheckResult
+	}{
+		{"Cloud-01", cloud.CheckCloud01},
+		{"Cloud-02", cloud.CheckCloud02},
+		{"Cloud-03", cloud.CheckCloud03},
+		{"Cloud-04", cloud.CheckCloud04},
+		{"Cloud-05", cloud.CheckCloud05},
+		{"Cloud-06", cloud.CheckCloud06},
+	}
+
+	var results []map[string]checklist.CheckResult
+	for _, check := range checks {
+		result := checker.Run(check.name, check.fn)
+		results = append(results, map[string]checklist.CheckResult{check.name: result})
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// @Summary     Is the admin role limited to a few projects per user?
+// @Tags        Cloud API
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param       profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/cloud-01 [get]
+func checkCloud01(c *gin.Context) {
+	checker, err := cloudChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to authenticate to OpenStack API",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("cloud-01", cloud.CheckCloud01)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Is multi-factor authentication enforced for administrators?
+// @Tags        Cloud API
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param       profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/cloud-02 [get]
+func checkCloud02(c *gin.Context) {
+	checker, err := cloudChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to authenticate to OpenStack API",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("cloud-02", cloud.CheckCloud02)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Do security groups block SSH/RDP from 0.0.0.0/0?
+// @Tags        Cloud API
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param       profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/cloud-03 [get]
+func checkCloud03(c *gin.Context) {
+	checker, err := cloudChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to authenticate to OpenStack API",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("cloud-03", cloud.CheckCloud03)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Are public images published by the cloud administrators only?
+// @Tags        Cloud API
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param       profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/cloud-04 [get]
+func checkCloud04(c *gin.Context) {
+	checker, err := cloudChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to authenticate to OpenStack API",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("cloud-04", cloud.CheckCloud04)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Are all volumes encrypted?
+// @Tags        Cloud API
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param       profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/cloud-05 [get]
+func checkCloud05(c *gin.Context) {
+	checker, err := cloudChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to authenticate to OpenStack API",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("cloud-05", cloud.CheckCloud05)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Are instances with floating IPs protected by security groups?
+// @Tags        Cloud API
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param       profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/cloud-06 [get]
+func checkCloud06(c *gin.Context) {
+	checker, err := cloudChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to authenticate to OpenStack API",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("cloud-06", cloud.CheckCloud06)
+	c.JSON(http.StatusOK, result)
+}
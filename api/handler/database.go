@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/checklist/database"
+	"golang.org/x/crypto/ssh"
+)
+
+// RegisterDatabaseRoutes registers all database check routes
+func RegisterDatabaseRoutes(router *gin.RouterGroup) {
+	router.GET("/check/database", handleDatabase)
+	router.GET("/check/database-01", checkDatabase01)
+	router.GET("/check/database-02", checkDatabase02)
+	router.GET("/check/database-03", checkDatabase03)
+	router.GET("/check/database-04", checkDatabase04)
+	router.GET("/check/database-05", checkDatabase05)
+	router.GET("/check/database-06", checkDatabase06)
+	router.GET("/check/database-07", checkDatabase07)
+}
+
+// @Summary     Run all database checks
+// @Description Runs every database (MariaDB/MySQL) check. Each service's [database] connection string carries a plaintext password, so both the client connections and the database server must be hardened.
+// @Tags        Database
+// @Produce     json
+// @Success     200 {array}  checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/database [get]
+func handleDatabase(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	checks := []struct {
+		name string
+		fn   func(*ssh.Client) checklist.CheckResult
+	}{
+		{"Database-01", database.CheckDatabase01},
+		{"Database-02", database.CheckDatabase02},
+		{"Database-03", database.CheckDatabase03},
+		{"Database-04", database.CheckDatabase04},
+		{"Database-05", database.CheckDatabase05},
+		{"Database-06", database.CheckDatabase06},
+		{"Database-07", database.CheckDatabase07},
+	}
+
+	var results []map[string]checklist.CheckResult
+	for _, check := range checks {
+		result := checker.Run(check.name, check.fn)
+		results = append(results, map[string]checklist.CheckResult{check.name: result})
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// @Summary     Is TLS used for every service's database connection?
+// @Tags        Database
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/database-01 [get]
+func checkDatabase01(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("database-01", database.CheckDatabase01)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Do services avoid connecting to the database as root?
+// @Tags        Database
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/database-02 [get]
+func checkDatabase02(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("database-02", database.CheckDatabase02)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Does each service use its own database credentials?
+// @Tags        Database
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/database-03 [get]
+func checkDatabase03(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("database-03", database.CheckDatabase03)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Are MySQL option files (my.cnf) protected from other users?
+// @Tags        Database
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/database-04 [get]
+func checkDatabase04(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("database-04", database.CheckDatabase04)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Is the database server bind-address restricted?
+// @Tags        Database
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/database-05 [get]
+func checkDatabase05(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("database-05", database.CheckDatabase05)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Is require_secure_transport enabled on the database server?
+// @Tags        Database
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/database-06 [get]
+func checkDatabase06(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("database-06", database.CheckDatabase06)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Are anonymous database users removed?
+// @Tags        Database
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/database-07 [get]
+func checkDatabase07(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("database-07", database.CheckDatabase07)
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/checklist/hypervisor"
+	"golang.org/x/crypto/ssh"
+)
+
+// RegisterHypervisorRoutes registers all hypervisor check routes
+func RegisterHypervisorRoutes(router *gin.RouterGroup) {
+	router.GET("/check/hypervisor", handleHypervisor)
+	router.GET("/check/hypervisor-01", checkHypervisor01)
+	router.GET("/check/hypervisor-02", checkHypervisor02)
+	router.GET("/check/hypervisor-03", checkHypervisor03)
+	router.GET("/check/hypervisor-04", checkHypervisor04)
+	router.GET("/check/hypervisor-05", checkHypervisor05)
+	router.GET("/check/hypervisor-06", checkHypervisor06)
+}
+
+// @Summary     Run all hypervisor checks
+// @Description Runs every hypervisor (libvirt/QEMU) check on a compute node. The hypervisor is the isolation boundary between tenants, so its management interfaces and guest confinement must be hardened.
+// @Tags        Hypervisor
+// @Produce     json
+// @Success     200 {array}  checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/hypervisor [get]
+func handleHypervisor(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	checks := []struct {
+		name string
+		fn   func(*ssh.Client) checklist.CheckResult
+	}{
+		{"Hypervisor-01", hypervisor.CheckHypervisor01},
+		{"Hypervisor-02", hypervisor.CheckHypervisor02},
+		{"Hypervisor-03", hypervisor.CheckHypervisor03},
+		{"Hypervisor-04", hypervisor.CheckHypervisor04},
+		{"Hypervisor-05", hypervisor.CheckHypervisor05},
+		{"Hypervisor-06", hypervisor.CheckHypervisor06},
+	}
+
+	var results []map[string]checklist.CheckResult
+	for _, check := range checks {
+		result := checker.Run(check.name, check.fn)
+		results = append(results, map[string]checklist.CheckResult{check.name: result})
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// @Summary     Is remote access to libvirtd restricted to authenticated TLS connections?
+// @Tags        Hypervisor
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/hypervisor-01 [get]
+func checkHypervisor01(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("hypervisor-01", hypervisor.CheckHypervisor01)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Is a QEMU security_driver (SELinux/AppArmor) enabled?
+// @Tags        Hypervisor
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/hypervisor-02 [get]
+func checkHypervisor02(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("hypervisor-02", hypervisor.CheckHypervisor02)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Are running QEMU processes confined by sVirt?
+// @Tags        Hypervisor
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/hypervisor-03 [get]
+func checkHypervisor03(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("hypervisor-03", hypervisor.CheckHypervisor03)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Are strict permissions set for /var/lib/nova/instances?
+// @Tags        Hypervisor
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/hypervisor-04 [get]
+func checkHypervisor04(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("hypervisor-04", hypervisor.CheckHypervisor04)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Is Kernel Samepage Merging (KSM) disabled?
+// @Tags        Hypervisor
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/hypervisor-05 [get]
+func checkHypervisor05(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("hypervisor-05", hypervisor.CheckHypervisor05)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Is TLS enabled for VNC/SPICE consoles?
+// @Tags        Hypervisor
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/hypervisor-06 [get]
+func checkHypervisor06(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("hypervisor-06", hypervisor.CheckHypervisor06)
+	c.JSON(http.StatusOK, result)
+}
@@ -6,7 +6,6 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gunh0/openstack-security-hub/checklist"
 	"github.com/gunh0/openstack-security-hub/checklist/identity"
-	"github.com/gunh0/openstack-security-hub/util"
 	"golang.org/x/crypto/ssh"
 )
 
@@ -25,20 +24,21 @@ func RegisterIdentityRoutes(router *gin.RouterGroup) {
 }
 
 // @Summary     Is user/group ownership of config files set to keystone?
-// @Description Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user and group ownership of such critical configuration files must be set to that component owner. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly.
 // @Tags        Identity
 // @Produce     json
 // @Success     200 {array}  checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
 // @Router      /check/identity-01 [get]
 func handleIdentity01(c *gin.Context) {
-	client, err := util.GetSSHClient()
+	checker, err := hostChecker(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(connectStatus(err), gin.H{
 			"error": err.Error(),
 		})
 		return
 	}
-	defer client.Close()
+	defer checker.Close()
 
 	checks := []struct {
 		name string
@@ -56,7 +56,7 @@ func handleIdentity01(c *gin.Context) {
 
 	var results []map[string]checklist.CheckResult
 	for _, check := range checks {
-		result := check.fn(client)
+		result := checker.Run(check.name, check.fn)
 		results = append(results, map[string]checklist.CheckResult{check.name: result})
 	}
 
@@ -64,199 +64,208 @@ func handleIdentity01(c *gin.Context) {
 }
 
 // @Summary     Is user/group ownership of config files set to keystone? (/etc/keystone/keystone.conf)
-// @Description Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user and group ownership of such critical configuration files must be set to that component owner. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly.
 // @Tags        Identity
 // @Produce     json
 // @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
 // @Router      /check/identity-01-01 [get]
 func checkIdentity0101(c *gin.Context) {
-	client, err := util.GetSSHClient()
+	checker, err := hostChecker(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(connectStatus(err), gin.H{
 			"status":  "error",
 			"message": "Failed to connect to server",
 			"error":   err.Error(),
 		})
 		return
 	}
-	defer client.Close()
+	defer checker.Close()
 
-	result := identity.CheckIdentity0101(client)
+	result := checker.Run("identity-01-01", identity.CheckIdentity0101)
 	c.JSON(http.StatusOK, result)
 }
 
 // @Summary     Is user/group ownership of config files set to keystone? (/etc/keystone/keystone-paste.ini)
-// @Description Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user and group ownership of such critical configuration files must be set to that component owner. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly.
 // @Tags        Identity
 // @Produce     json
 // @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
 // @Router      /check/identity-01-02 [get]
 func checkIdentity0102(c *gin.Context) {
-	client, err := util.GetSSHClient()
+	checker, err := hostChecker(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(connectStatus(err), gin.H{
 			"status":  "error",
 			"message": "Failed to connect to server",
 			"error":   err.Error(),
 		})
 		return
 	}
-	defer client.Close()
+	defer checker.Close()
 
-	result := identity.CheckIdentity0102(client)
+	result := checker.Run("identity-01-02", identity.CheckIdentity0102)
 	c.JSON(http.StatusOK, result)
 }
 
 // @Summary     Is user/group ownership of config files set to keystone? (/etc/keystone/policy.json)
-// @Description Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user and group ownership of such critical configuration files must be set to that component owner. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly.
 // @Tags        Identity
 // @Produce     json
 // @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
 // @Router      /check/identity-01-03 [get]
 func checkIdentity0103(c *gin.Context) {
-	client, err := util.GetSSHClient()
+	checker, err := hostChecker(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(connectStatus(err), gin.H{
 			"status":  "error",
 			"message": "Failed to connect to server",
 			"error":   err.Error(),
 		})
 		return
 	}
-	defer client.Close()
+	defer checker.Close()
 
-	result := identity.CheckIdentity0103(client)
+	result := checker.Run("identity-01-03", identity.CheckIdentity0103)
 	c.JSON(http.StatusOK, result)
 }
 
 // @Summary     Is user/group ownership of config files set to keystone? (/etc/keystone/logging.conf)
-// @Description Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user and group ownership of such critical configuration files must be set to that component owner. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly.
 // @Tags        Identity
 // @Produce     json
 // @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
 // @Router      /check/identity-01-04 [get]
 func checkIdentity0104(c *gin.Context) {
-	client, err := util.GetSSHClient()
+	checker, err := hostChecker(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(connectStatus(err), gin.H{
 			"status":  "error",
 			"message": "Failed to connect to server",
 			"error":   err.Error(),
 		})
 		return
 	}
-	defer client.Close()
+	defer checker.Close()
 
-	result := identity.CheckIdentity0104(client)
+	result := checker.Run("identity-01-04", identity.CheckIdentity0104)
 	c.JSON(http.StatusOK, result)
 }
 
 // @Summary     Is user/group ownership of config files set to keystone? (/etc/keystone/ssl/certs/signing_cert.pem)
-// @Description Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user and group ownership of such critical configuration files must be set to that component owner. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly.
 // @Tags        Identity
 // @Produce     json
 // @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
 // @Router      /check/identity-01-05 [get]
 func checkIdentity0105(c *gin.Context) {
-	client, err := util.GetSSHClient()
+	checker, err := hostChecker(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(connectStatus(err), gin.H{
 			"status":  "error",
 			"message": "Failed to connect to server",
 			"error":   err.Error(),
 		})
 		return
 	}
-	defer client.Close()
+	defer checker.Close()
 
-	result := identity.CheckIdentity0105(client)
+	result := checker.Run("identity-01-05", identity.CheckIdentity0105)
 	c.JSON(http.StatusOK, result)
 }
 
 // @Summary     Is user/group ownership of config files set to keystone? (/etc/keystone/ssl/private/signing_key.pem)
-// @Description Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user and group ownership of such critical configuration files must be set to that component owner. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly.
 // @Tags        Identity
 // @Produce     json
 // @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
 // @Router      /check/identity-01-06 [get]
 func checkIdentity0106(c *gin.Context) {
-	client, err := util.GetSSHClient()
+	checker, err := hostChecker(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(connectStatus(err), gin.H{
 			"status":  "error",
 			"message": "Failed to connect to server",
 			"error":   err.Error(),
 		})
 		return
 	}
-	defer client.Close()
+	defer checker.Close()
 
-	result := identity.CheckIdentity0106(client)
+	result := checker.Run("identity-01-06", identity.CheckIdentity0106)
 	c.JSON(http.StatusOK, result)
 }
 
 // @Summary     Is user/group ownership of config files set to keystone? (/etc/keystone/ssl/certs/ca.pem)
-// @Description Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user and group ownership of such critical configuration files must be set to that component owner. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly.
 // @Tags        Identity
 // @Produce     json
 // @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
 // @Router      /check/identity-01-07 [get]
 func checkIdentity0107(c *gin.Context) {
-	client, err := util.GetSSHClient()
+	checker, err := hostChecker(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(connectStatus(err), gin.H{
 			"status":  "error",
 			"message": "Failed to connect to server",
 			"error":   err.Error(),
 		})
 		return
 	}
-	defer client.Close()
+	defer checker.Close()
 
-	result := identity.CheckIdentity0107(client)
+	result := checker.Run("identity-01-07", identity.CheckIdentity0107)
 	c.JSON(http.StatusOK, result)
 }
 
 // @Summary     Is user/group ownership of config files set to keystone? (/etc/keystone)
-// @Description Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user and group ownership of such critical configuration files must be set to that component owner. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly.
 // @Tags        Identity
 // @Produce     json
 // @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
 // @Router      /check/identity-01-08 [get]
 func checkIdentity0108(c *gin.Context) {
-	client, err := util.GetSSHClient()
+	checker, err := hostChecker(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(connectStatus(err), gin.H{
 			"status":  "error",
 			"message": "Failed to connect to server",
 			"error":   err.Error(),
 		})
 		return
 	}
-	defer client.Close()
+	defer checker.Close()
 
-	result := identity.CheckIdentity0108(client)
+	result := checker.Run("identity-01-08", identity.CheckIdentity0108)
 	c.JSON(http.StatusOK, result)
 }
 
 // @Summary     Are strict permissions set for Identity configuration files? (/etc/keystone/keystone.conf)
-// @Description Similar to the previous check, it is recommended to set strict access permissions for such configuration files.
 // @Tags        Identity
 // @Produce     json
 // @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
 // @Router      /check/identity-02-01 [get]
 func checkIdentity0201(c *gin.Context) {
-	client, err := util.GetSSHClient()
+	checker, err := hostChecker(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(connectStatus(err), gin.H{
 			"status":  "error",
 			"message": "Failed to connect to server",
 			"error":   err.Error(),
 		})
 		return
 	}
-	defer client.Close()
+	defer checker.Close()
 
-	result := identity.CheckIdentity0201(client)
+	result := checker.Run("identity-02-01", identity.CheckIdentity0201)
 	c.JSON(http.StatusOK, result)
 }
@@ -0,0 +1,282 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gunh0/openstack-security-hub/history"
+	"github.com/gunh0/openstack-security-hub/jobs"
+	"github.com/gunh0/openstack-security-hub/scan"
+)
+
+var (
+	// scanJobs runs the scans submitted to POST /scans
+	scanJobs *jobs.Manager
+
+	// scanHistory records every scan, or is nil when the history is disabled
+	scanHistory *history.History
+)
+
+// RegisterScanJobRoutes opens the scan history, starts the scan worker pool and registers the scan job routes
+func RegisterScanJobRoutes(router *gin.RouterGroup) {
+	config, err := history.ConfigFromEnv()
+	if err == nil {
+		scanHistory, err = history.Open(config)
+	}
+	if err != nil {
+		log.Printf("Warning: scans are not recorded: %v", err)
+	}
+	scanJobs = jobs.NewManager(envInt("SCAN_WORKERS", 4), envInt("SCAN_QUEUE_SIZE", 100), scanHistory)
+
+	router.POST("/scans", handleSubmitScan)
+	router.GET("/scans", handleListScans)
+	router.GET("/scans/:id", handleGetScan)
+	router.DELETE("/scans/:id", handleCancelScan)
+	router.GET("/scans/:id/events", handleScanEvents)
+	router.GET("/scans/:id/diff/:other", handleDiffScans)
+}
+
+// ShutdownScanJobs stops taking scan jobs and waits for the running ones until ctx is done, then cancels them
+func ShutdownScanJobs(ctx context.Context) error {
+	if scanJobs == nil {
+		return nil
+	}
+	return scanJobs.Shutdown(ctx)
+}
+
+// CloseScanHistory closes the scan history once no scan is left to record
+func CloseScanHistory() error {
+	if scanHistory == nil {
+		return nil
+	}
+	return scanHistory.Close()
+}
+
+// envInt returns the positive integer environment variable name, or fallback
+func envInt(name string, fallback int) int {
+	if n, err := strconv.Atoi(os.Getenv(name)); err == nil && n > 0 {
+		return n
+	}
+	return fallback
+}
+
+// @Summary     Submit a scan job
+// @Description Queues a scan of the given targets and returns the job immediately. Jobs run in a pool of SCAN_WORKERS workers (default 4); up to SCAN_QUEUE_SIZE jobs (default 100) wait in the queue. Targets are names of registered targets; without targets SSH_HOST is scanned. Checks, profile, release and framework select the checks as in GET /scan.
+// @Tags        Scan
+// @Accept      json
+// @Produce     json
+// @Param       request body     jobs.Request true "Scan request"
+// @Success     202     {object} jobs.Job
+// @Failure     400     {object} map[string]string
+// @Failure     503     {object} map[string]string
+// @Router      /scans [post]
+func handleSubmitScan(c *gin.Context) {
+	var request jobs.Request
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if len(request.Targets) == 0 {
+		request.Targets = []string{os.Getenv("SSH_HOST")}
+	}
+	if err := checkTargets(request.Targets); err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	opts, err := scanOptions(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	job, err := scanJobs.Submit(request, opts)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.Header("Location", c.Request.URL.Path+"/"+job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// @Summary     List scan jobs and past scans
+// @Description Lists the queued and running scan jobs, newest first, followed by the scans recorded in the history, without their reports.
+// @Tags        Scan
+// @Produce     json
+// @Success     200 {array}  jobs.Job
+// @Failure     500 {object} map[string]string
+// @Router      /scans [get]
+func handleListScans(c *gin.Context) {
+	list, err := scanJobs.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// @Summary     Get a scan job
+// @Description Returns the status (queued, running, completed or cancelled), the progress and, once the job has finished, the reports of a scan job or of a scan recorded in the history.
+// @Tags        Scan
+// @Produce     json
+// @Param       id  path     string true "Job ID"
+// @Success     200 {object} jobs.Job
+// @Failure     404 {object} map[string]string
+// @Router      /scans/{id} [get]
+func handleGetScan(c *gin.Context) {
+	job, err := scanJobs.Get(c.Param("id"))
+	if err != nil {
+		c.JSON(jobStatusCode(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// @Summary     Cancel a scan job or delete a past scan
+// @Description Cancels a queued or running scan job. A running job stops its current check at once; that check and the remaining checks of the current target are reported as skipped and the targets scanned so far are kept. A finished scan is deleted from the history.
+// @Tags        Scan
+// @Produce     json
+// @Param       id  path     string true "Job ID"
+// @Success     202 {object} jobs.Job
+// @Success     204
+// @Failure     404 {object} map[string]string
+// @Router      /scans/{id} [delete]
+func handleCancelScan(c *gin.Context) {
+	job, err := scanJobs.Cancel(c.Param("id"))
+	if errors.Is(err, jobs.ErrFinished) {
+		err = scanJobs.Delete(c.Param("id"))
+		if err == nil {
+			c.Status(http.StatusNoContent)
+			return
+		}
+	}
+
+	if err != nil {
+		c.JSON(jobStatusCode(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+// @Summary     Compare two scans
+// @Description Reports what changed from scan id to scan other, per target: checks newly failing, newly passing or newly errored, checks added or removed, and other changes of result, details or evidence. Details and evidence are shown before and after, e.g. a file mode going from 640 to 644. Both scans must have finished.
+// @Tags        Scan
+// @Produce     json
+// @Param       id    path     string true "ID of the earlier scan"
+// @Param       other path     string true "ID of the later scan"
+// @Success     200   {object} scan.Diff
+// @Failure     404   {object} map[string]string
+// @Failure     409   {object} map[string]string
+// @Router      /scans/{id}/diff/{other} [get]
+func handleDiffScans(c *gin.Context) {
+	var reports [2][]scan.Report
+	for i, id := range []string{c.Param("id"), c.Param("other")} {
+		job, err := scanJobs.Get(id)
+		if err != nil {
+			c.JSON(jobStatusCode(err), gin.H{
+				"error": fmt.Sprintf("%s: %v", id, err),
+			})
+			return
+		}
+		if job.Status == jobs.Queued || job.Status == jobs.Running {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": fmt.Sprintf("scan job %s has not finished", id),
+			})
+			return
+		}
+		reports[i] = job.Reports
+	}
+
+	c.JSON(http.StatusOK, scan.Compare(reports[0], reports[1]))
+}
+
+// jobStatusCode maps a scan job error to an HTTP status code
+func jobStatusCode(err error) int {
+	if errors.Is(err, jobs.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// @Summary     Stream the events of a scan job
+// @Description Streams the events of a scan job as Server-Sent Events until the job finishes. Each event is named after its type: status (queued, running, then completed or cancelled, with the progress), connected, connection_failed and disconnected for hosts and the OpenStack APIs, check_started, result (with the check result), skipped and target_finished. Events already emitted are replayed first; a reconnecting client resumes after the Last-Event-ID header or the after query parameter.
+// @Tags        Scan
+// @Produce     text/event-stream
+// @Param       id    path  string true  "Job ID"
+// @Param       after query int    false "Only stream the events after this event ID"
+// @Success     200 {object} jobs.Event
+// @Failure     404 {object} map[string]string
+// @Router      /scans/{id}/events [get]
+func handleScanEvents(c *gin.Context) {
+	after := c.GetHeader("Last-Event-ID")
+	if c.Query("after") != "" {
+		after = c.Query("after")
+	}
+	last, _ := strconv.Atoi(after)
+
+	events, changed, finished, err := scanJobs.Events(c.Param("id"), last)
+	if err != nil {
+		c.JSON(jobStatusCode(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.Stream(func(w io.Writer) bool {
+		for _, event := range events {
+			if err := writeEvent(w, event); err != nil {
+				return false
+			}
+			last = event.ID
+		}
+		if finished {
+			return false
+		}
+		c.Writer.Flush()
+
+		select {
+		case <-changed:
+		case <-c.Request.Context().Done():
+			return false
+		}
+		events, changed, finished, err = scanJobs.Events(c.Param("id"), last)
+		return err == nil
+	})
+}
+
+// writeEvent writes a job event in the Server-Sent Events format
+func writeEvent(w io.Writer, event jobs.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/checklist/leakage"
+	"golang.org/x/crypto/ssh"
+)
+
+// RegisterLeakageRoutes registers all leakage check routes
+func RegisterLeakageRoutes(router *gin.RouterGroup) {
+	router.GET("/check/leakage", handleLeakage)
+	router.GET("/check/leakage-01", checkLeakage01)
+	router.GET("/check/leakage-02", checkLeakage02)
+	router.GET("/check/leakage-03", checkLeakage03)
+	router.GET("/check/leakage-04", checkLeakage04)
+}
+
+// @Summary     Run all leakage checks
+// @Description Runs every secret leakage check. Service configuration directories under /etc and logs under /var/log are scanned for plaintext secrets, stale backups and debug logging. Secrets are redacted in the reported evidence.
+// @Tags        Secret Leakage
+// @Produce     json
+// @Success     200 {array}  checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/leakage [get]
+func handleLeakage(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	checks := []struct {
+		name string
+		fn   func(*ssh.Client) checklist.CheckResult
+	}{
+		{"Leakage-01", leakage.CheckLeakage01},
+		{"Leakage-02", leakage.CheckLeakage02},
+		{"Leakage-03", leakage.CheckLeakage03},
+		{"Leakage-04", leakage.CheckLeakage04},
+	}
+
+	var results []map[string]checklist.CheckResult
+	for _, check := range checks {
+		result := checker.Run(check.name, check.fn)
+		results = append(results, map[string]checklist.CheckResult{check.name: result})
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// @Summary     Are plaintext secrets in service configuration files protected?
+// @Tags        Secret Leakage
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/leakage-01 [get]
+func checkLeakage01(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("leakage-01", leakage.CheckLeakage01)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Are service logs free of plaintext secrets?
+// @Tags        Secret Leakage
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/leakage-02 [get]
+func checkLeakage02(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("leakage-02", leakage.CheckLeakage02)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Are there no stale backup copies of configuration files?
+// @Tags        Secret Leakage
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/leakage-03 [get]
+func checkLeakage03(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("leakage-03", leakage.CheckLeakage03)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Is debug logging disabled for every service?
+// @Tags        Secret Leakage
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/leakage-04 [get]
+func checkLeakage04(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("leakage-04", leakage.CheckLeakage04)
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/checklist/messaging"
+	"golang.org/x/crypto/ssh"
+)
+
+// RegisterMessagingRoutes registers all messaging check routes
+func RegisterMessagingRoutes(router *gin.RouterGroup) {
+	router.GET("/check/messaging", handleMessaging)
+	router.GET("/check/messaging-01", checkMessaging01)
+	router.GET("/check/messaging-02", checkMessaging02)
+	router.GET("/check/messaging-03", checkMessaging03)
+	router.GET("/check/messaging-04", checkMessaging04)
+	router.GET("/check/messaging-05", checkMessaging05)
+}
+
+// @Summary     Run all messaging checks
+// @Description Runs every message bus (RabbitMQ / oslo.messaging) check. The message bus is where the credentials of all services meet, so a weakness there affects the whole cloud.
+// @Tags        Messaging
+// @Produce     json
+// @Success     200 {array}  checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/messaging [get]
+func handleMessaging(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	checks := []struct {
+		name string
+		fn   func(*ssh.Client) checklist.CheckResult
+	}{
+		{"Messaging-01", messaging.CheckMessaging01},
+		{"Messaging-02", messaging.CheckMessaging02},
+		{"Messaging-03", messaging.CheckMessaging03},
+		{"Messaging-04", messaging.CheckMessaging04},
+		{"Messaging-05", messaging.CheckMessaging05},
+	}
+
+	var results []map[string]checklist.CheckResult
+	for _, check := range checks {
+		result := checker.Run(check.name, check.fn)
+		results = append(results, map[string]checklist.CheckResult{check.name: result})
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// @Summary     Is TLS enabled for every service's transport_url?
+// @Tags        Messaging
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/messaging-01 [get]
+func checkMessaging01(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("messaging-01", messaging.CheckMessaging01)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Is the RabbitMQ guest account disabled?
+// @Tags        Messaging
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/messaging-02 [get]
+func checkMessaging02(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("messaging-02", messaging.CheckMessaging02)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Are strict ownership and permissions set for RabbitMQ configuration files?
+// @Tags        Messaging
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/messaging-03 [get]
+func checkMessaging03(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("messaging-03", messaging.CheckMessaging03)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Is the RabbitMQ management plugin restricted?
+// @Tags        Messaging
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/messaging-04 [get]
+func checkMessaging04(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("messaging-04", messaging.CheckMessaging04)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Does each service use a separate RabbitMQ virtual host?
+// @Tags        Messaging
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/messaging-05 [get]
+func checkMessaging05(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("messaging-05", messaging.CheckMessaging05)
+	c.JSON(http.StatusOK, result)
+}
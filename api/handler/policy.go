@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/checklist/policy"
+	"golang.org/x/crypto/ssh"
+)
+
+// RegisterPolicyRoutes registers all policy check routes
+func RegisterPolicyRoutes(router *gin.RouterGroup) {
+	router.GET("/check/policy", handlePolicy)
+	router.GET("/check/policy-01", checkPolicy01)
+	router.GET("/check/policy-02", checkPolicy02)
+	router.GET("/check/policy-03", checkPolicy03)
+}
+
+// @Summary     Run all policy checks
+// @Description Runs every policy analyzer check. The policy file of each service (policy.json or policy.yaml, or the file configured with [oslo_policy] policy_file) is loaded and each risky rule is reported as evidence.
+// @Tags        Policy
+// @Produce     json
+// @Success     200 {array}  checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/policy [get]
+func handlePolicy(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	checks := []struct {
+		name string
+		fn   func(*ssh.Client) checklist.CheckResult
+	}{
+		{"Policy-01", policy.CheckPolicy01},
+		{"Policy-02", policy.CheckPolicy02},
+		{"Policy-03", policy.CheckPolicy03},
+	}
+
+	var results []map[string]checklist.CheckResult
+	for _, check := range checks {
+		result := checker.Run(check.name, check.fn)
+		results = append(results, map[string]checklist.CheckResult{check.name: result})
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// @Summary     Are policy files free of overly permissive rules?
+// @Tags        Policy
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/policy-01 [get]
+func checkPolicy01(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("policy-01", policy.CheckPolicy01)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Are policy files free of deprecated rule overrides?
+// @Tags        Policy
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/policy-02 [get]
+func checkPolicy02(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("policy-02", policy.CheckPolicy02)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Are enforce_scope and enforce_new_defaults enabled in [oslo_policy]?
+// @Tags        Policy
+// @Produce     json
+// @Success     200 {object} checklist.CheckResult
+// @Param      target query string false "Name of a registered target (default SSH_HOST)"
+// @Param      profile query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement"
+// @Router      /check/policy-03 [get]
+func checkPolicy03(c *gin.Context) {
+	checker, err := hostChecker(c)
+	if err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"status":  "error",
+			"message": "Failed to connect to server",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer checker.Close()
+
+	result := checker.Run("policy-03", policy.CheckPolicy03)
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gunh0/openstack-security-hub/history"
+	"github.com/gunh0/openstack-security-hub/jobs"
+	"github.com/gunh0/openstack-security-hub/output"
+	"github.com/gunh0/openstack-security-hub/scan"
+)
+
+// RegisterScanRoutes registers the discovery, scan and benchmark routes
+func RegisterScanRoutes(router *gin.RouterGroup) {
+	router.GET("/scan", handleScan)
+	router.GET("/discover", handleDiscover)
+	router.GET("/benchmark", handleBenchmark)
+}
+
+// queryHosts returns the comma separated hosts query parameter, or SSH_HOST
+func queryHosts(c *gin.Context) []string {
+	hosts := []string{}
+	for _, host := range strings.Split(c.Query("hosts"), ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	if len(hosts) == 0 {
+		hosts = append(hosts, os.Getenv("SSH_HOST"))
+	}
+	return hosts
+}
+
+// reportExport describes a downloadable scan report format
+type reportExport struct {
+	write       func(io.Writer, []output.Record) error
+	contentType string
+	filename    string
+}
+
+// exports lists the report formats available besides JSON
+var exports = map[string]reportExport{
+	"junit": {output.WriteJUnit, "application/xml; charset=utf-8", "security-hub-junit.xml"},
+	"sarif": {output.WriteSARIF, "application/sarif+json", "security-hub.sarif"},
+	"xccdf": {output.WriteXCCDF, "application/xml; charset=utf-8", "security-hub-xccdf.xml"},
+}
+
+// profileDir returns the directory holding the profiles selectable from the API
+func profileDir() string {
+	if dir := os.Getenv("PROFILE_DIR"); dir != "" {
+		return dir
+	}
+	return "profiles"
+}
+
+// @Summary     Discover services and run the applicable checks
+// @Description Detects the installed OpenStack services, the deployment style (DevStack, Kolla, OpenStack-Ansible, packages) and the release of each host, then runs only the checks that apply to it. The detected or given release selects the checks and expected values of that release. Checks that do not apply are reported as skipped with a reason. The OpenStack API checks run once when credentials are configured.
+// @Tags        Scan
+// @Produce     json,xml,application/sarif+json
+// @Param       hosts        query string false "Comma separated names of registered targets (default SSH_HOST)"
+// @Param       no_discovery query bool   false "Run every host check without service discovery"
+// @Param       release      query string false "OpenStack release (e.g. 2024.1 or caracal) overriding the detected one"
+// @Param       profile      query string false "Name of a profile in PROFILE_DIR (default profiles) overriding expected values, enablement and severity"
+// @Param       checks       query string false "Comma separated check IDs or glob patterns (e.g. identity-01-*) limiting the scan"
+// @Param       framework    query string false "Compliance framework limiting the scan to its mapped checks and tagging results with its controls" Enums(cis, nist-800-53, iso-27001, pci-dss)
+// @Param       format       query string false "Report format: json (default), junit for a JUnit XML export, sarif for a SARIF 2.1.0 log, xccdf for XCCDF 1.2 TestResults or controls for a per control rollup (requires framework)" Enums(json, junit, sarif, xccdf, controls)
+// @Success     200 {array}  scan.Report
+// @Header      200 {string} X-Scan-Id "ID of the scan recorded in the history, served by GET /scans/{id}"
+// @Router      /scan [get]
+func handleScan(c *gin.Context) {
+	var checks []string
+	if c.Query("checks") != "" {
+		checks = strings.Split(c.Query("checks"), ",")
+	}
+	opts, err := scanOptions(jobs.Request{
+		Checks:      checks,
+		Profile:     c.Query("profile"),
+		Release:     c.Query("release"),
+		Framework:   c.Query("framework"),
+		NoDiscovery: c.Query("no_discovery") == "true",
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	export, ok := exports[format]
+	if format != "json" && format != "controls" && !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "format must be json, junit, sarif, xccdf or controls",
+		})
+		return
+	}
+	if format == "controls" && opts.Framework == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "format controls requires a framework",
+		})
+		return
+	}
+
+	hosts := queryHosts(c)
+	if err := checkTargets(hosts); err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	startedAt := time.Now().UTC().Format(time.RFC3339)
+	reports := scan.Run(c.Request.Context(), hosts, opts)
+	if scanHistory != nil {
+		id := history.NewID()
+		err := scanHistory.Save(history.Scan{
+			ID:         id,
+			Status:     "completed",
+			Source:     "api",
+			Targets:    hosts,
+			Checks:     checks,
+			Profile:    c.Query("profile"),
+			Release:    opts.Release,
+			Framework:  opts.Framework,
+			StartedAt:  startedAt,
+			FinishedAt: time.Now().UTC().Format(time.RFC3339),
+			Reports:    reports,
+		})
+		if err != nil {
+			log.Printf("[ERROR] Failed to record scan in the history: %v", err)
+		} else {
+			c.Header("X-Scan-Id", id)
+		}
+	}
+	if format == "json" {
+		c.JSON(http.StatusOK, reports)
+		return
+	}
+	if format == "controls" {
+		c.JSON(http.StatusOK, output.Controls(output.Records(reports)))
+		return
+	}
+
+	var buffer bytes.Buffer
+	if err := export.write(&buffer, output.Records(reports)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.filename))
+	c.Data(http.StatusOK, export.contentType, buffer.Bytes())
+}
+
+// scanOptions validates the checks, profile, release and framework of a scan request
+func scanOptions(request jobs.Request) (scan.Options, error) {
+	opts := scan.Options{NoDiscovery: request.NoDiscovery, Checks: request.Checks}
+	if err := scan.ValidateChecks(request.Checks); err != nil {
+		return opts, err
+	}
+	if request.Release != "" {
+		release, err := scan.NormalizeRelease(request.Release)
+		if err != nil {
+			return opts, err
+		}
+		opts.Release = release
+	}
+	if request.Profile != "" {
+		profile, err := scan.LoadNamedProfile(profileDir(), request.Profile)
+		if err != nil {
+			return opts, err
+		}
+		opts.Profile = profile
+	}
+	if request.Framework != "" {
+		framework, err := scan.LookupFramework(request.Framework)
+		if err != nil {
+			return opts, err
+		}
+		opts.Framework = framework.ID
+	}
+	waivers, err := loadWaivers()
+	if err != nil {
+		return opts, err
+	}
+	opts.Waivers = waivers
+	opts.Connect = connectTarget
+	if opts.CheckTimeout, err = scan.CheckTimeoutFromEnv(); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// @Summary     XCCDF benchmark of the checklist
+// @Description Returns the checklist as an XCCDF 1.2 benchmark with one rule per check (IDs like xccdf_com.github.gunh0_rule_identity-01-01), grouped by service, with descriptions, rationales and fixtexts. Scan results are exported as TestResults of this benchmark with GET /scan?format=xccdf.
+// @Tags        Scan
+// @Produce     xml
+// @Success     200 {string} string "XCCDF benchmark"
+// @Router      /benchmark [get]
+func handleBenchmark(c *gin.Context) {
+	var buffer bytes.Buffer
+	if err := output.WriteXCCDF(&buffer, nil); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", buffer.Bytes())
+}
+
+// @Summary     Discover services on hosts
+// @Description Detects the installed OpenStack services (by package, systemd unit, container or /etc/<service> presence), the deployment style and the release of each host without running any check.
+// @Tags        Scan
+// @Produce     json
+// @Param       hosts query string false "Comma separated names of registered targets (default SSH_HOST)"
+// @Success     200 {object} map[string]scan.Discovery
+// @Router      /discover [get]
+func handleDiscover(c *gin.Context) {
+	hosts := queryHosts(c)
+	if err := checkTargets(hosts); err != nil {
+		c.JSON(connectStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	discoveries := map[string]interface{}{}
+	for _, host := range hosts {
+		client, err := connectTarget(c.Request.Context(), host)
+		if err != nil {
+			discoveries[host] = gin.H{"error": err.Error()}
+			continue
+		}
+
+		discovery, err := scan.Discover(client)
+		client.Close()
+		if err != nil {
+			discoveries[host] = gin.H{"error": err.Error()}
+			continue
+		}
+		discoveries[host] = discovery
+	}
+
+	c.JSON(http.StatusOK, discoveries)
+}
@@ -0,0 +1,352 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gunh0/openstack-security-hub/scan"
+	"github.com/gunh0/openstack-security-hub/targets"
+	"github.com/gunh0/openstack-security-hub/util"
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	// errUnknownTarget is returned for a target the API does not connect to
+	errUnknownTarget = errors.New("unknown target")
+
+	// targetStore holds the named targets, or is nil when it could not be opened
+	targetStore *targets.Store
+
+	// targetErr is why the target store could not be opened
+	targetErr error
+)
+
+// RegisterTargetRoutes opens the target store and registers the target routes
+func RegisterTargetRoutes(router *gin.RouterGroup) {
+	targetStore, targetErr = targets.OpenFromEnv()
+	if targetErr != nil {
+		log.Printf("Warning: targets are unavailable: %v", targetErr)
+	}
+
+	router.GET("/targets", handleListTargets)
+	router.GET("/targets/:name", handleGetTarget)
+	router.POST("/targets", handleCreateTarget)
+	router.PUT("/targets/:name", handlePutTarget)
+	router.DELETE("/targets/:name", handleDeleteTarget)
+}
+
+// connectTarget returns an SSH client for the named target, or for SSH_HOST
+func connectTarget(ctx context.Context, target string) (*ssh.Client, error) {
+	if err := checkTargets([]string{target}); err != nil {
+		return nil, err
+	}
+	if targetStore == nil {
+		return util.GetSSHClientForHostContext(ctx, target)
+	}
+	return targetStore.Connect(ctx, target)
+}
+
+// checkTargets returns an error unless every target is a registered target or SSH_HOST. Unlike the
+// command line, the API does not connect to other hosts, which would send them the SSH credentials.
+func checkTargets(names []string) error {
+	for _, name := range names {
+		if name == "" {
+			return fmt.Errorf("%w: no target given and SSH_HOST is not set", errUnknownTarget)
+		}
+		if name == os.Getenv("SSH_HOST") {
+			continue
+		}
+		if targetErr != nil {
+			return targetErr
+		}
+		if targetStore == nil {
+			return fmt.Errorf("%w %q: only SSH_HOST can be scanned", errUnknownTarget, name)
+		}
+		if _, err := targetStore.Get(name); errors.Is(err, targets.ErrNotFound) {
+			return fmt.Errorf("%w %q: only registered targets and SSH_HOST can be scanned", errUnknownTarget, name)
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hostChecker connects to the target query parameter, or SSH_HOST, and returns a checker applying the
+// profile query parameter and stopping each check after CHECK_TIMEOUT or at the request deadline
+func hostChecker(c *gin.Context) (*scan.Checker, error) {
+	timeout, err := scan.CheckTimeoutFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	profile, err := queryProfile(c)
+	if err != nil {
+		return nil, err
+	}
+
+	target := c.Query("target")
+	if target == "" {
+		target = os.Getenv("SSH_HOST")
+	}
+	client, err := connectTarget(c.Request.Context(), target)
+	if err != nil {
+		return nil, err
+	}
+	return scan.NewChecker(c.Request.Context(), target, client, connectTarget, timeout, profile), nil
+}
+
+// queryProfile loads the profile named by the profile query parameter from PROFILE_DIR, or returns nil
+func queryProfile(c *gin.Context) (*scan.Profile, error) {
+	name := c.Query("profile")
+	if name == "" {
+		return nil, nil
+	}
+	profile, err := scan.LoadNamedProfile(profileDir(), name)
+	if err != nil {
+		return nil, badRequest{err}
+	}
+	return profile, nil
+}
+
+// badRequest marks an error caused by the parameters of the request
+type badRequest struct {
+	error
+}
+
+// apiTargetError returns why t cannot be saved over the API, replacing stored when it is not nil. The API
+// only accepts credentials given in the request, since an operator could send the credentials of the
+// server to a host of their choice: env and key_file targets and known hosts files on the server are
+// left to the command line, and the stored secrets of a target are only kept while its address and
+// jump host are unchanged.
+func apiTargetError(t targets.Target, stored *targets.Target) error {
+	switch t.Auth.Method {
+	case targets.AuthPassword, targets.AuthPrivateKey:
+	case targets.AuthEnv, targets.AuthKeyFile, "":
+		return fmt.Errorf("%s authentication uses credentials of the server and is only available from the command line, use password or private_key", authMethod(t.Auth.Method))
+	default:
+		return fmt.Errorf("unknown auth method %q (use password or private_key)", t.Auth.Method)
+	}
+	if t.KnownHosts != "" {
+		return errors.New("known_hosts files are only available from the command line, pin the host key with host_key")
+	}
+
+	secret := t.Auth.Password
+	if t.Auth.Method == targets.AuthPrivateKey {
+		secret = t.Auth.PrivateKey
+	}
+	if secret != "" || stored == nil || stored.Auth.Method != t.Auth.Method {
+		return nil
+	}
+	if stored.Address() != t.Address() || stored.JumpHost != t.JumpHost {
+		return fmt.Errorf("the stored %s is only kept while the host, port and jump host are unchanged, give it again", authMethod(t.Auth.Method))
+	}
+	return nil
+}
+
+// authMethod returns the name of an auth method, env when it is empty
+func authMethod(method string) string {
+	if method == "" {
+		return targets.AuthEnv
+	}
+	return method
+}
+
+// connectStatus returns the HTTP status of an error connecting to the target of a request
+func connectStatus(err error) int {
+	var bad badRequest
+	if errors.Is(err, errUnknownTarget) || errors.As(err, &bad) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// targetError writes the response of a target store error
+func targetError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, targets.ErrInvalid):
+		status = http.StatusBadRequest
+	case errors.Is(err, targets.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, targets.ErrExists), errors.Is(err, targets.ErrInUse):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
+
+// storeAvailable writes a 503 response and returns false when the target store could not be opened
+func storeAvailable(c *gin.Context) bool {
+	if targetStore != nil {
+		return true
+	}
+	c.JSON(http.StatusServiceUnavailable, gin.H{
+		"error": "targets are unavailable: " + targetErr.Error(),
+	})
+	return false
+}
+
+// @Summary     List targets
+// @Description Lists the named targets of the target file TARGETS_FILE (default targets.yaml) without their secrets. A target name can be given wherever a host is expected: in the hosts of GET /scan, POST /scans and GET /discover, and in the target parameter of the check routes.
+// @Tags        Targets
+// @Produce     json
+// @Success     200 {array}  targets.Target
+// @Failure     500 {object} map[string]string
+// @Failure     503 {object} map[string]string
+// @Router      /targets [get]
+func handleListTargets(c *gin.Context) {
+	if !storeAvailable(c) {
+		return
+	}
+	list, err := targetStore.List()
+	if err != nil {
+		targetError(c, err)
+		return
+	}
+	for i := range list {
+		list[i] = list[i].Redacted()
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// @Summary     Get a target
+// @Description Returns a named target without its secrets.
+// @Tags        Targets
+// @Produce     json
+// @Param       name path     string true "Target name"
+// @Success     200  {object} targets.Target
+// @Failure     404  {object} map[string]string
+// @Failure     500  {object} map[string]string
+// @Failure     503  {object} map[string]string
+// @Router      /targets/{name} [get]
+func handleGetTarget(c *gin.Context) {
+	if !storeAvailable(c) {
+		return
+	}
+	target, err := targetStore.Get(c.Param("name"))
+	if err != nil {
+		targetError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, target.Redacted())
+}
+
+// @Summary     Add a target
+// @Description Adds a named host to scan over SSH. The auth method is password or private_key (PEM key in the request, which may be protected by a passphrase). The env and key_file methods and known_hosts files use credentials and files of the server and are only available from the command line; pin the host key with host_key instead. Secrets are encrypted in the target file with the key TARGETS_KEY or TARGETS_KEY_FILE (default targets.key) and never returned. A jump host is the name of another target to connect through.
+// @Tags        Targets
+// @Accept      json
+// @Produce     json
+// @Param       target body     targets.Target true "Target"
+// @Success     201    {object} targets.Target
+// @Failure     400    {object} map[string]string
+// @Failure     409    {object} map[string]string
+// @Failure     500    {object} map[string]string
+// @Failure     503    {object} map[string]string
+// @Router      /targets [post]
+func handleCreateTarget(c *gin.Context) {
+	if !storeAvailable(c) {
+		return
+	}
+	var target targets.Target
+	if err := c.ShouldBindJSON(&target); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := apiTargetError(target, nil); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	target, err := targetStore.Create(target)
+	if err != nil {
+		targetError(c, err)
+		return
+	}
+	c.Header("Location", c.Request.URL.Path+"/"+target.Name)
+	c.JSON(http.StatusCreated, target.Redacted())
+}
+
+// @Summary     Replace a target
+// @Description Creates or replaces the named target, with the auth methods of POST /targets. Secrets left out of the request keep their stored value as long as the auth method, the address and the jump host are unchanged.
+// @Tags        Targets
+// @Accept      json
+// @Produce     json
+// @Param       name   path     string         true "Target name"
+// @Param       target body     targets.Target true "Target"
+// @Success     200    {object} targets.Target
+// @Failure     400    {object} map[string]string
+// @Failure     500    {object} map[string]string
+// @Failure     503    {object} map[string]string
+// @Router      /targets/{name} [put]
+func handlePutTarget(c *gin.Context) {
+	if !storeAvailable(c) {
+		return
+	}
+	var target targets.Target
+	if err := c.ShouldBindJSON(&target); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if target.Name != "" && target.Name != c.Param("name") {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "the target name does not match the path",
+		})
+		return
+	}
+	target.Name = c.Param("name")
+
+	var stored *targets.Target
+	if existing, err := targetStore.Get(target.Name); err == nil {
+		stored = &existing
+	} else if !errors.Is(err, targets.ErrNotFound) {
+		targetError(c, err)
+		return
+	}
+	if err := apiTargetError(target, stored); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	target, err := targetStore.Put(target)
+	if err != nil {
+		targetError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, target.Redacted())
+}
+
+// @Summary     Delete a target
+// @Description Removes a named target and its secrets. A target used as the jump host of another target cannot be deleted.
+// @Tags        Targets
+// @Produce     json
+// @Param       name path string true "Target name"
+// @Success     204
+// @Failure     404 {object} map[string]string
+// @Failure     409 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Failure     503 {object} map[string]string
+// @Router      /targets/{name} [delete]
+func handleDeleteTarget(c *gin.Context) {
+	if !storeAvailable(c) {
+		return
+	}
+	if err := targetStore.Delete(c.Param("name")); err != nil {
+		targetError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gunh0/openstack-security-hub/scan"
+)
+
+// waiverMu serializes the reads and writes of the waiver file
+var waiverMu sync.Mutex
+
+// WaiverStatus is a waiver with whether it is active or expired
+type WaiverStatus struct {
+	scan.Waiver
+	Status string `json:"status"`
+}
+
+// RegisterWaiverRoutes registers the waiver routes
+func RegisterWaiverRoutes(router *gin.RouterGroup) {
+	router.GET("/waivers", handleListWaivers)
+	router.POST("/waivers", handleAddWaiver)
+	router.DELETE("/waivers/:id", handleDeleteWaiver)
+}
+
+// loadWaivers reads the waiver file WAIVER_FILE (default waivers.yaml)
+func loadWaivers() ([]scan.Waiver, error) {
+	waiverMu.Lock()
+	defer waiverMu.Unlock()
+	return scan.LoadWaivers(scan.WaiverFilePath())
+}
+
+// @Summary     List waivers
+// @Description Lists the waivers of the waiver file WAIVER_FILE (default waivers.yaml) with their status, active or expired. A failed check covered by an active waiver is reported as [WAIVED]; once the waiver expires the check fails again and its result names the expired waiver.
+// @Tags        Waivers
+// @Produce     json
+// @Success     200 {array}  WaiverStatus
+// @Failure     500 {object} map[string]string
+// @Router      /waivers [get]
+func handleListWaivers(c *gin.Context) {
+	waivers, err := loadWaivers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	now := time.Now()
+	list := []WaiverStatus{}
+	for _, waiver := range waivers {
+		list = append(list, WaiverStatus{Waiver: waiver, Status: waiver.Status(now)})
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// @Summary     Add a waiver
+// @Description Accepts the failure of a check (ID or glob pattern such as identity-01-*) on some hosts (hosts, host:port or glob patterns; none means every host) until the expiry date (YYYY-MM-DD, inclusive). A justification and an owner are required. Without an ID one is generated.
+// @Tags        Waivers
+// @Accept      json
+// @Produce     json
+// @Param       waiver body     scan.Waiver true "Waiver"
+// @Success     201    {object} WaiverStatus
+// @Failure     400    {object} map[string]string
+// @Failure     409    {object} map[string]string
+// @Failure     500    {object} map[string]string
+// @Router      /waivers [post]
+func handleAddWaiver(c *gin.Context) {
+	var waiver scan.Waiver
+	if err := c.ShouldBindJSON(&waiver); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if waiver.ID == "" {
+		waiver.ID = scan.NewWaiverID()
+	}
+	if err := waiver.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	waiverMu.Lock()
+	defer waiverMu.Unlock()
+
+	file := scan.WaiverFilePath()
+	waivers, err := scan.LoadWaivers(file)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	for _, w := range waivers {
+		if w.ID == waiver.ID {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "waiver " + waiver.ID + " already exists",
+			})
+			return
+		}
+	}
+	if err := scan.SaveWaivers(file, append(waivers, waiver)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Header("Location", c.Request.URL.Path+"/"+waiver.ID)
+	c.JSON(http.StatusCreated, WaiverStatus{Waiver: waiver, Status: waiver.Status(time.Now())})
+}
+
+// @Summary     Delete a waiver
+// @Description Removes a waiver from the waiver file; the checks it covered are reported as failed again by the next scans.
+// @Tags        Waivers
+// @Produce     json
+// @Param       id  path string true "Waiver ID"
+// @Success     204
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /waivers/{id} [delete]
+func handleDeleteWaiver(c *gin.Context) {
+	waiverMu.Lock()
+	defer waiverMu.Unlock()
+
+	file := scan.WaiverFilePath()
+	waivers, err := scan.LoadWaivers(file)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	for i, w := range waivers {
+		if w.ID != c.Param("id") {
+			continue
+		}
+		if err := scan.SaveWaivers(file, append(waivers[:i], waivers[i+1:]...)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.Status(http.StatusNoContent)
+		return
+	}
+	c.JSON(http.StatusNotFound, gin.H{
+		"error": "waiver not found",
+	})
+}
@@ -3,10 +3,12 @@ package api
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/gunh0/openstack-security-hub/api/handler"
+	"github.com/gunh0/openstack-security-hub/auth"
 )
 
-// RegisterRoutes registers all API routes
-func RegisterRoutes(router *gin.Engine) {
+// RegisterRoutes registers all API routes. Every route but the health check requires a token
+// unless authenticator is nil.
+func RegisterRoutes(router *gin.Engine, authenticator *auth.Authenticator) {
 	api := router.Group("/api/v1")
 
 	api.GET("/health", func(c *gin.Context) {
@@ -16,10 +18,24 @@ func RegisterRoutes(router *gin.Engine) {
 		})
 	})
 
+	if authenticator != nil {
+		api.Use(authenticate(authenticator))
+	}
+
 	// Register service-specific routes
 	handler.RegisterIdentityRoutes(api)
 	handler.RegisterDashboardRoutes(api)
 	handler.RegisterKeyManagerRoutes(api)
+	handler.RegisterMessagingRoutes(api)
+	handler.RegisterDatabaseRoutes(api)
+	handler.RegisterHypervisorRoutes(api)
+	handler.RegisterLeakageRoutes(api)
+	handler.RegisterPolicyRoutes(api)
+	handler.RegisterCloudRoutes(api)
+	handler.RegisterScanRoutes(api)
+	handler.RegisterScanJobRoutes(api)
+	handler.RegisterWaiverRoutes(api)
+	handler.RegisterTargetRoutes(api)
 }
 
 // @Summary     Health check endpoint
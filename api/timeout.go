@@ -0,0 +1,29 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamingRoutes last as long as the job they follow and have no request timeout
+var streamingRoutes = map[string]bool{
+	"GET /api/v1/scans/:id/events": true,
+}
+
+// RequestTimeout cancels the context of each request after timeout, which stops the checks it runs;
+// they are reported as [TIMEOUT]. Zero disables the timeout.
+func RequestTimeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 || streamingRoutes[c.Request.Method+" "+c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
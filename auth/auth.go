@@ -0,0 +1,243 @@
+// auth/auth.go
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnauthorized is returned for a missing, unknown or expired token
+var ErrUnauthorized = errors.New("invalid or expired API token")
+
+// Role is what an API caller is allowed to do
+type Role string
+
+const (
+	// Viewer reads scan results, history, diffs and waivers
+	Viewer Role = "viewer"
+
+	// Operator also starts scans and checks, and manages waivers and targets
+	Operator Role = "operator"
+)
+
+// ParseRole parses viewer or operator
+func ParseRole(value string) (Role, error) {
+	switch role := Role(strings.ToLower(strings.TrimSpace(value))); role {
+	case Viewer, Operator:
+		return role, nil
+	}
+	return "", fmt.Errorf("unknown role %q (use viewer or operator)", value)
+}
+
+// Allows reports whether r grants the permissions of required
+func (r Role) Allows(required Role) bool {
+	return r == Operator || r == required
+}
+
+// Principal is an authenticated API caller
+type Principal struct {
+	Name string `json:"name"`
+	Role Role   `json:"role"`
+
+	// Method is how the caller authenticated: token or keystone
+	Method string `json:"method"`
+}
+
+// Token is a static API token. The token is given in clear or as the hex SHA-256 of the token.
+type Token struct {
+	Name        string `yaml:"name"`
+	Token       string `yaml:"token,omitempty"`
+	TokenSHA256 string `yaml:"token_sha256,omitempty"`
+	Role        string `yaml:"role"`
+}
+
+// TokenFile is the YAML document holding the static API tokens
+type TokenFile struct {
+	Tokens []Token `yaml:"tokens"`
+}
+
+// Config selects how API callers authenticate
+type Config struct {
+	// Disabled turns authentication off; every caller is an operator
+	Disabled bool
+
+	// TokensFile is a YAML file of static API tokens
+	TokensFile string
+
+	// Keystone validates OpenStack tokens when its URL is set
+	Keystone KeystoneConfig
+}
+
+// KeystoneConfig validates Keystone tokens and maps their roles to API roles
+type KeystoneConfig struct {
+	URL string
+
+	// Project (ID or name in ProjectDomain) and Domain (ID or name) are the scopes whose roles are trusted.
+	// A token scoped to anything else is rejected, so that a role in an unrelated project grants nothing.
+	Project       string
+	ProjectDomain string
+	Domain        string
+
+	// OperatorRoles and ViewerRoles are the Keystone role names granting each API role in the scope
+	OperatorRoles []string
+	ViewerRoles   []string
+	CACert        string
+	Insecure      bool
+
+	// CacheTTL is how long a validated token is trusted before asking Keystone again
+	CacheTTL time.Duration
+}
+
+// ConfigFromEnv reads API_AUTH (none disables authentication), API_TOKENS_FILE and the API_KEYSTONE_* variables:
+// API_KEYSTONE_URL, API_KEYSTONE_PROJECT, API_KEYSTONE_PROJECT_DOMAIN (default Default), API_KEYSTONE_DOMAIN,
+// API_KEYSTONE_OPERATOR_ROLES (default admin), API_KEYSTONE_VIEWER_ROLES (default reader,member),
+// API_KEYSTONE_CACERT, API_KEYSTONE_INSECURE and API_KEYSTONE_CACHE_TTL (default 5m)
+func ConfigFromEnv() (Config, error) {
+	config := Config{
+		Disabled:   os.Getenv("API_AUTH") == "none",
+		TokensFile: os.Getenv("API_TOKENS_FILE"),
+		Keystone: KeystoneConfig{
+			URL:           os.Getenv("API_KEYSTONE_URL"),
+			Project:       os.Getenv("API_KEYSTONE_PROJECT"),
+			ProjectDomain: os.Getenv("API_KEYSTONE_PROJECT_DOMAIN"),
+			Domain:        os.Getenv("API_KEYSTONE_DOMAIN"),
+			OperatorRoles: envList("API_KEYSTONE_OPERATOR_ROLES", "admin"),
+			ViewerRoles:   envList("API_KEYSTONE_VIEWER_ROLES", "reader,member"),
+			CACert:        os.Getenv("API_KEYSTONE_CACERT"),
+			CacheTTL:      5 * time.Minute,
+		},
+	}
+	if config.Keystone.ProjectDomain == "" {
+		config.Keystone.ProjectDomain = "Default"
+	}
+	if value := os.Getenv("API_AUTH"); value != "" && value != "none" {
+		return config, fmt.Errorf("invalid API_AUTH %q (only none is accepted, to disable authentication)", value)
+	}
+	if value := os.Getenv("API_KEYSTONE_INSECURE"); value != "" {
+		insecure, err := strconv.ParseBool(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid API_KEYSTONE_INSECURE %q", value)
+		}
+		config.Keystone.Insecure = insecure
+	}
+	if value := os.Getenv("API_KEYSTONE_CACHE_TTL"); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid API_KEYSTONE_CACHE_TTL: %v", err)
+		}
+		config.Keystone.CacheTTL = ttl
+	}
+	return config, nil
+}
+
+// envList splits the comma separated environment variable name, or fallback
+func envList(name, fallback string) []string {
+	value := os.Getenv(name)
+	if value == "" {
+		value = fallback
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// Authenticator checks API tokens against the static tokens, then Keystone
+type Authenticator struct {
+	tokens   map[[sha256.Size]byte]Principal
+	keystone *keystone
+}
+
+// New builds the authenticator selected by config. It returns nil when authentication is disabled.
+// A config without tokens or Keystone is an error, so that the API is never left open by mistake.
+func New(config Config) (*Authenticator, error) {
+	if config.Disabled {
+		return nil, nil
+	}
+	if config.TokensFile == "" && config.Keystone.URL == "" {
+		return nil, fmt.Errorf("no API authentication configured: set API_TOKENS_FILE or API_KEYSTONE_URL, or API_AUTH=none")
+	}
+
+	a := &Authenticator{tokens: map[[sha256.Size]byte]Principal{}}
+	if config.TokensFile != "" {
+		if err := a.loadTokens(config.TokensFile); err != nil {
+			return nil, err
+		}
+	}
+	if config.Keystone.URL != "" {
+		k, err := newKeystone(config.Keystone)
+		if err != nil {
+			return nil, err
+		}
+		a.keystone = k
+	}
+	return a, nil
+}
+
+// loadTokens reads the static tokens of file, indexed by their SHA-256
+func (a *Authenticator) loadTokens(file string) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read API tokens: %v", err)
+	}
+	var tokenFile TokenFile
+	if err := yaml.Unmarshal(content, &tokenFile); err != nil {
+		return fmt.Errorf("invalid API tokens %s: %v", file, err)
+	}
+
+	for i, token := range tokenFile.Tokens {
+		if token.Name == "" {
+			token.Name = fmt.Sprintf("token-%d", i+1)
+		}
+		role, err := ParseRole(token.Role)
+		if err != nil {
+			return fmt.Errorf("invalid API tokens %s: %s: %v", file, token.Name, err)
+		}
+
+		var sum [sha256.Size]byte
+		switch {
+		case token.Token != "":
+			sum = sha256.Sum256([]byte(token.Token))
+		case token.TokenSHA256 != "":
+			decoded, err := hex.DecodeString(token.TokenSHA256)
+			if err != nil || len(decoded) != sha256.Size {
+				return fmt.Errorf("invalid API tokens %s: %s: token_sha256 must be a hex SHA-256", file, token.Name)
+			}
+			copy(sum[:], decoded)
+		default:
+			return fmt.Errorf("invalid API tokens %s: %s: token or token_sha256 is required", file, token.Name)
+		}
+		a.tokens[sum] = Principal{Name: token.Name, Role: role, Method: "token"}
+	}
+	return nil
+}
+
+// Authenticate returns the caller owning token
+func (a *Authenticator) Authenticate(token string) (Principal, error) {
+	if token == "" {
+		return Principal{}, ErrUnauthorized
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	for known, principal := range a.tokens {
+		if subtle.ConstantTimeCompare(known[:], sum[:]) == 1 {
+			return principal, nil
+		}
+	}
+	if a.keystone != nil {
+		return a.keystone.validate(token, sum)
+	}
+	return Principal{}, ErrUnauthorized
+}
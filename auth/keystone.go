@@ -0,0 +1,191 @@
+// auth/keystone.go
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gunh0/openstack-security-hub/openstack"
+)
+
+// keystone validates OpenStack tokens with GET /v3/auth/tokens and caches the results
+type keystone struct {
+	config     KeystoneConfig
+	httpClient *http.Client
+	url        string
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]cachedPrincipal
+}
+
+type cachedPrincipal struct {
+	principal Principal
+	until     time.Time
+}
+
+// validationResponse is the subset of the token validation body used to find the caller, its scope and its roles
+type validationResponse struct {
+	Token struct {
+		ExpiresAt time.Time `json:"expires_at"`
+		User      struct {
+			Name   string `json:"name"`
+			Domain struct {
+				Name string `json:"name"`
+			} `json:"domain"`
+		} `json:"user"`
+		Project *struct {
+			ID     string `json:"id"`
+			Name   string `json:"name"`
+			Domain struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"domain"`
+		} `json:"project"`
+		Domain *struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"domain"`
+		Roles []struct {
+			Name string `json:"name"`
+		} `json:"roles"`
+	} `json:"token"`
+}
+
+// inScope reports whether the token is scoped to the configured project or domain
+func (r validationResponse) inScope(config KeystoneConfig) bool {
+	if project := r.Token.Project; project != nil && config.Project != "" {
+		if project.ID == config.Project {
+			return true
+		}
+		domain := project.Domain
+		if project.Name == config.Project && (domain.Name == config.ProjectDomain || domain.ID == config.ProjectDomain) {
+			return true
+		}
+	}
+	if domain := r.Token.Domain; domain != nil && config.Domain != "" {
+		return domain.ID == config.Domain || domain.Name == config.Domain
+	}
+	return false
+}
+
+// scope describes the configured scopes for error messages
+func (config KeystoneConfig) scope() string {
+	var scopes []string
+	if config.Project != "" {
+		scopes = append(scopes, fmt.Sprintf("project %s (domain %s)", config.Project, config.ProjectDomain))
+	}
+	if config.Domain != "" {
+		scopes = append(scopes, "domain "+config.Domain)
+	}
+	return strings.Join(scopes, " or ")
+}
+
+func newKeystone(config KeystoneConfig) (*keystone, error) {
+	if config.Project == "" && config.Domain == "" {
+		return nil, fmt.Errorf("API_KEYSTONE_URL requires API_KEYSTONE_PROJECT or API_KEYSTONE_DOMAIN: Keystone roles are only trusted in that scope")
+	}
+	httpClient, err := openstack.NewHTTPClient(openstack.Config{CACert: config.CACert, Insecure: config.Insecure})
+	if err != nil {
+		return nil, err
+	}
+	httpClient.Timeout = 10 * time.Second
+
+	url := strings.TrimSuffix(config.URL, "/")
+	if !strings.HasSuffix(url, "/v3") {
+		url += "/v3"
+	}
+	return &keystone{
+		config:     config,
+		httpClient: httpClient,
+		url:        url,
+		cache:      map[[sha256.Size]byte]cachedPrincipal{},
+	}, nil
+}
+
+// validate asks Keystone about token, which authenticates its own validation, and maps its roles to an API role
+func (k *keystone) validate(token string, sum [sha256.Size]byte) (Principal, error) {
+	now := time.Now()
+	k.mu.Lock()
+	cached, ok := k.cache[sum]
+	k.mu.Unlock()
+	if ok && now.Before(cached.until) {
+		return cached.principal, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, k.url+"/auth/tokens", nil)
+	if err != nil {
+		return Principal{}, err
+	}
+	req.Header.Set("X-Auth-Token", token)
+	req.Header.Set("X-Subject-Token", token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := k.httpClient.Do(req)
+	if err != nil {
+		return Principal{}, fmt.Errorf("failed to validate token with Keystone: %v", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusForbidden:
+		return Principal{}, ErrUnauthorized
+	case resp.StatusCode != http.StatusOK:
+		return Principal{}, fmt.Errorf("failed to validate token with Keystone: %s", resp.Status)
+	}
+
+	var body validationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Principal{}, fmt.Errorf("failed to decode Keystone token: %v", err)
+	}
+
+	principal := Principal{Name: body.Token.User.Name, Method: "keystone"}
+	if domain := body.Token.User.Domain.Name; domain != "" {
+		principal.Name = domain + "/" + principal.Name
+	}
+	// The roles of a token are those of its scope, so a token scoped elsewhere grants nothing here
+	if !body.inScope(k.config) {
+		return Principal{}, fmt.Errorf("%w: the Keystone token of %s is not scoped to %s", ErrUnauthorized,
+			principal.Name, k.config.scope())
+	}
+	for _, role := range body.Token.Roles {
+		switch {
+		case contains(k.config.OperatorRoles, role.Name):
+			principal.Role = Operator
+		case contains(k.config.ViewerRoles, role.Name) && principal.Role == "":
+			principal.Role = Viewer
+		}
+	}
+	if principal.Role == "" {
+		roles := append(append([]string{}, k.config.OperatorRoles...), k.config.ViewerRoles...)
+		return Principal{}, fmt.Errorf("%w: Keystone user %s has none of the roles %s", ErrUnauthorized,
+			principal.Name, strings.Join(roles, ", "))
+	}
+
+	until := now.Add(k.config.CacheTTL)
+	if !body.Token.ExpiresAt.IsZero() && body.Token.ExpiresAt.Before(until) {
+		until = body.Token.ExpiresAt
+	}
+	k.mu.Lock()
+	for key, entry := range k.cache {
+		if !now.Before(entry.until) {
+			delete(k.cache, key)
+		}
+	}
+	k.cache[sum] = cachedPrincipal{principal: principal, until: until}
+	k.mu.Unlock()
+	return principal, nil
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,495 @@
+// checklist/cloud/cloud.go
+package cloud
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/openstack"
+)
+
+// maxAdminProjects is the number of projects a user may hold the admin role in before it is reported
+const maxAdminProjects = 3
+
+// roleAssignment is an entry of GET /v3/role_assignments?include_names
+type roleAssignment struct {
+	User struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"user"`
+	Scope struct {
+		Project struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"project"`
+		System map[string]interface{} `json:"system"`
+	} `json:"scope"`
+}
+
+// adminAssignments returns the effective admin role assignments of all users
+func adminAssignments(client *openstack.Client) ([]roleAssignment, error) {
+	var roles struct {
+		Roles []struct {
+			ID string `json:"id"`
+		} `json:"roles"`
+	}
+	if err := client.ServiceGet("identity", "/v3", "/roles?name=admin", &roles); err != nil {
+		return nil, err
+	}
+	if len(roles.Roles) == 0 {
+		return nil, fmt.Errorf("admin role not found")
+	}
+
+	var assignments struct {
+		RoleAssignments []roleAssignment `json:"role_assignments"`
+	}
+	path := fmt.Sprintf("/role_assignments?role.id=%s&effective&include_names", roles.Roles[0].ID)
+	if err := client.ServiceGet("identity", "/v3", path, &assignments); err != nil {
+		return nil, err
+	}
+
+	return assignments.RoleAssignments, nil
+}
+
+// errorResult is returned when the API could not be queried
+func errorResult(description, currentTime string, err error) checklist.CheckResult {
+	return checklist.CheckResult{
+		Description: description,
+		Result:      "[ERROR]",
+		Details:     err.Error(),
+		Timestamp:   currentTime,
+	}
+}
+
+// CheckCloud01 checks if users hold the admin role in many projects
+func CheckCloud01(client *openstack.Client) checklist.CheckResult {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+	const (
+		description = "Is the admin role limited to a few projects per user?"
+	)
+
+	assignments, err := adminAssignments(client)
+	if err != nil {
+		return errorResult(description, currentTime, err)
+	}
+
+	projects := map[string][]string{}
+	names := map[string]string{}
+	for _, a := range assignments {
+		if a.User.ID == "" || a.Scope.Project.ID == "" {
+			continue
+		}
+		names[a.User.ID] = a.User.Name
+		projects[a.User.ID] = append(projects[a.User.ID], a.Scope.Project.Name)
+	}
+
+	users := make([]string, 0, len(projects))
+	for user := range projects {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+
+	var evidence []checklist.Evidence
+	for _, user := range users {
+		if len(projects[user]) > maxAdminProjects {
+			sort.Strings(projects[user])
+			evidence = append(evidence, checklist.Evidence{
+				Location: fmt.Sprintf("identity/users/%s", user),
+				Snippet:  fmt.Sprintf("%s has admin in %d projects: %s", names[user], len(projects[user]), strings.Join(projects[user], ", ")),
+			})
+		}
+	}
+
+	if len(evidence) > 0 {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[FAIL]",
+			Details:     fmt.Sprintf("%d users hold the admin role in more than %d projects", len(evidence), maxAdminProjects),
+			Evidence:    evidence,
+			Timestamp:   currentTime,
+		}
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      "[PASS]",
+		Details:     fmt.Sprintf("No user holds the admin role in more than %d projects (%d users with admin role)", maxAdminProjects, len(users)),
+		Timestamp:   currentTime,
+	}
+}
+
+// CheckCloud02 checks if multi-factor authentication rules are enabled for administrators
+func CheckCloud02(client *openstack.Client) checklist.CheckResult {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+	const (
+		description = "Is multi-factor authentication enforced for administrators?"
+	)
+
+	assignments, err := adminAssignments(client)
+	if err != nil {
+		return errorResult(description, currentTime, err)
+	}
+
+	seen := map[string]bool{}
+	var evidence []checklist.Evidence
+	for _, a := range assignments {
+		if a.User.ID == "" || seen[a.User.ID] {
+			continue
+		}
+		seen[a.User.ID] = true
+
+		var user struct {
+			User struct {
+				Name    string `json:"name"`
+				Options struct {
+					MFAEnabled bool       `json:"multi_factor_auth_enabled"`
+					MFARules   [][]string `json:"multi_factor_auth_rules"`
+				} `json:"options"`
+			} `json:"user"`
+		}
+		if err := client.ServiceGet("identity", "/v3", "/users/"+a.User.ID, &user); err != nil {
+			return errorResult(description, currentTime, err)
+		}
+
+		switch {
+		case !user.User.Options.MFAEnabled:
+			evidence = append(evidence, checklist.Evidence{
+				Location: fmt.Sprintf("identity/users/%s", a.User.ID),
+				Snippet:  fmt.Sprintf("%s: multi_factor_auth_enabled is not set", user.User.Name),
+			})
+		case len(user.User.Options.MFARules) == 0:
+			evidence = append(evidence, checklist.Evidence{
+				Location: fmt.Sprintf("identity/users/%s", a.User.ID),
+				Snippet:  fmt.Sprintf("%s: multi_factor_auth_rules are empty", user.User.Name),
+			})
+		}
+	}
+
+	if len(seen) == 0 {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[NA]",
+			Details:     "No users with the admin role found",
+			Timestamp:   currentTime,
+		}
+	}
+
+	if len(evidence) > 0 {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[FAIL]",
+			Details:     fmt.Sprintf("%d of %d administrators do not have MFA rules enabled", len(evidence), len(seen)),
+			Evidence:    evidence,
+			Timestamp:   currentTime,
+		}
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      "[PASS]",
+		Details:     fmt.Sprintf("All %d administrators have MFA rules enabled", len(seen)),
+		Timestamp:   currentTime,
+	}
+}
+
+// CheckCloud03 checks if security groups allow SSH or RDP from anywhere
+func CheckCloud03(client *openstack.Client) checklist.CheckResult {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+	const (
+		description = "Do security groups block SSH/RDP from 0.0.0.0/0?"
+	)
+
+	var rules struct {
+		SecurityGroupRules []struct {
+			ID              string  `json:"id"`
+			SecurityGroupID string  `json:"security_group_id"`
+			ProjectID       string  `json:"project_id"`
+			Protocol        *string `json:"protocol"`
+			PortRangeMin    *int    `json:"port_range_min"`
+			PortRangeMax    *int    `json:"port_range_max"`
+			RemoteIPPrefix  *string `json:"remote_ip_prefix"`
+			RemoteGroupID   *string `json:"remote_group_id"`
+		} `json:"security_group_rules"`
+	}
+	if err := client.ServiceGet("network", "/v2.0", "/security-group-rules?direction=ingress", &rules); err != nil {
+		return errorResult(description, currentTime, err)
+	}
+
+	var evidence []checklist.Evidence
+	for _, rule := range rules.SecurityGroupRules {
+		// A rule without prefix and remote group matches any source
+		open := rule.RemoteIPPrefix == nil && rule.RemoteGroupID == nil
+		if rule.RemoteIPPrefix != nil {
+			open = *rule.RemoteIPPrefix == "0.0.0.0/0" || *rule.RemoteIPPrefix == "::/0"
+		}
+		if !open {
+			continue
+		}
+		if rule.Protocol != nil && *rule.Protocol != "tcp" && *rule.Protocol != "6" && *rule.Protocol != "any" {
+			continue
+		}
+
+		for _, port := range []int{22, 3389} {
+			if rule.PortRangeMin != nil && *rule.PortRangeMin > port {
+				continue
+			}
+			if rule.PortRangeMax != nil && *rule.PortRangeMax < port {
+				continue
+			}
+
+			ports := "all ports"
+			if rule.PortRangeMin != nil && rule.PortRangeMax != nil {
+				ports = fmt.Sprintf("ports %d-%d", *rule.PortRangeMin, *rule.PortRangeMax)
+			}
+			evidence = append(evidence, checklist.Evidence{
+				Location: fmt.Sprintf("network/security-group-rules/%s", rule.ID),
+				Snippet:  fmt.Sprintf("security group %s (project %s) allows %s from anywhere", rule.SecurityGroupID, rule.ProjectID, ports),
+			})
+			break
+		}
+	}
+
+	if len(evidence) > 0 {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[FAIL]",
+			Details:     fmt.Sprintf("%d ingress rules allow SSH or RDP from anywhere", len(evidence)),
+			Evidence:    evidence,
+			Timestamp:   currentTime,
+		}
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      "[PASS]",
+		Details:     fmt.Sprintf("None of the %d ingress rules allow SSH or RDP from anywhere", len(rules.SecurityGroupRules)),
+		Timestamp:   currentTime,
+	}
+}
+
+// CheckCloud04 checks for public images not owned by the cloud administrators
+func CheckCloud04(client *openstack.Client) checklist.CheckResult {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+	const (
+		description = "Are public images published by the cloud administrators only?"
+	)
+
+	type image struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Owner string `json:"owner"`
+	}
+
+	endpoint, err := client.Endpoint("image")
+	if err != nil {
+		return errorResult(description, currentTime, err)
+	}
+	base := strings.TrimSuffix(endpoint, "/v2")
+
+	// Glance paginates with a relative "next" link
+	var images []image
+	next := "/v2/images?visibility=public"
+	for next != "" {
+		var page struct {
+			Images []image `json:"images"`
+			Next   string  `json:"next"`
+		}
+		if err := client.Get(base+next, &page); err != nil {
+			return errorResult(description, currentTime, err)
+		}
+		images = append(images, page.Images...)
+		next = page.Next
+	}
+
+	var evidence []checklist.Evidence
+	for _, img := range images {
+		if img.Owner != client.ProjectID {
+			evidence = append(evidence, checklist.Evidence{
+				Location: fmt.Sprintf("image/images/%s", img.ID),
+				Snippet:  fmt.Sprintf("public image %q is owned by project %s", img.Name, img.Owner),
+			})
+		}
+	}
+
+	if len(evidence) > 0 {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[FAIL]",
+			Details:     fmt.Sprintf("%d of %d public images are owned by other projects than the admin project", len(evidence), len(images)),
+			Evidence:    evidence,
+			Timestamp:   currentTime,
+		}
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      "[PASS]",
+		Details:     fmt.Sprintf("All %d public images are owned by the admin project", len(images)),
+		Timestamp:   currentTime,
+	}
+}
+
+// CheckCloud05 checks for unencrypted volumes
+func CheckCloud05(client *openstack.Client) checklist.CheckResult {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+	const (
+		description = "Are all volumes encrypted?"
+	)
+
+	type volume struct {
+		ID        string `json:"id"`
+		Name      string `json:"name"`
+		Encrypted bool   `json:"encrypted"`
+		ProjectID string `json:"os-vol-tenant-attr:tenant_id"`
+	}
+
+	endpoint, err := client.Endpoint("block-storage", "volumev3", "volume")
+	if err != nil {
+		return errorResult(description, currentTime, err)
+	}
+
+	// Cinder paginates with a volumes_links "next" entry
+	var volumes []volume
+	next := endpoint + "/volumes/detail?all_tenants=1"
+	for next != "" {
+		var page struct {
+			Volumes []volume `json:"volumes"`
+			Links   []struct {
+				Rel  string `json:"rel"`
+				Href string `json:"href"`
+			} `json:"volumes_links"`
+		}
+		if err := client.Get(next, &page); err != nil {
+			return errorResult(description, currentTime, err)
+		}
+		volumes = append(volumes, page.Volumes...)
+
+		next = ""
+		for _, link := range page.Links {
+			if link.Rel == "next" {
+				next = link.Href
+			}
+		}
+	}
+
+	if len(volumes) == 0 {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[NA]",
+			Details:     "No volumes found",
+			Timestamp:   currentTime,
+		}
+	}
+
+	var evidence []checklist.Evidence
+	for _, v := range volumes {
+		if !v.Encrypted {
+			evidence = append(evidence, checklist.Evidence{
+				Location: fmt.Sprintf("volume/volumes/%s", v.ID),
+				Snippet:  fmt.Sprintf("volume %q of project %s is not encrypted", v.Name, v.ProjectID),
+			})
+		}
+	}
+
+	if len(evidence) > 0 {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[FAIL]",
+			Details:     fmt.Sprintf("%d of %d volumes are not encrypted", len(evidence), len(volumes)),
+			Evidence:    evidence,
+			Timestamp:   currentTime,
+		}
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      "[PASS]",
+		Details:     fmt.Sprintf("All %d volumes are encrypted", len(volumes)),
+		Timestamp:   currentTime,
+	}
+}
+
+// CheckCloud06 checks for floating IPs attached to instance ports without security groups
+func CheckCloud06(client *openstack.Client) checklist.CheckResult {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+	const (
+		description = "Are instances with floating IPs protected by security groups?"
+	)
+
+	var floatingIPs struct {
+		FloatingIPs []struct {
+			ID                string  `json:"id"`
+			FloatingIPAddress string  `json:"floating_ip_address"`
+			PortID            *string `json:"port_id"`
+		} `json:"floatingips"`
+	}
+	if err := client.ServiceGet("network", "/v2.0", "/floatingips", &floatingIPs); err != nil {
+		return errorResult(description, currentTime, err)
+	}
+
+	attached := 0
+	var evidence []checklist.Evidence
+	for _, fip := range floatingIPs.FloatingIPs {
+		if fip.PortID == nil || *fip.PortID == "" {
+			continue
+		}
+
+		var port struct {
+			Port struct {
+				DeviceID            string   `json:"device_id"`
+				DeviceOwner         string   `json:"device_owner"`
+				SecurityGroups      []string `json:"security_groups"`
+				PortSecurityEnabled *bool    `json:"port_security_enabled"`
+			} `json:"port"`
+		}
+		if err := client.ServiceGet("network", "/v2.0", "/ports/"+*fip.PortID, &port); err != nil {
+			return errorResult(description, currentTime, err)
+		}
+		if !strings.HasPrefix(port.Port.DeviceOwner, "compute:") {
+			continue
+		}
+		attached++
+
+		switch {
+		case port.Port.PortSecurityEnabled != nil && !*port.Port.PortSecurityEnabled:
+			evidence = append(evidence, checklist.Evidence{
+				Location: fmt.Sprintf("network/floatingips/%s", fip.ID),
+				Snippet:  fmt.Sprintf("%s -> instance %s: port security is disabled", fip.FloatingIPAddress, port.Port.DeviceID),
+			})
+		case len(port.Port.SecurityGroups) == 0:
+			evidence = append(evidence, checklist.Evidence{
+				Location: fmt.Sprintf("network/floatingips/%s", fip.ID),
+				Snippet:  fmt.Sprintf("%s -> instance %s: no security groups", fip.FloatingIPAddress, port.Port.DeviceID),
+			})
+		}
+	}
+
+	if attached == 0 {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[NA]",
+			Details:     "No floating IPs are attached to instances",
+			Timestamp:   currentTime,
+		}
+	}
+
+	if len(evidence) > 0 {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[FAIL]",
+			Details:     fmt.Sprintf("%d of %d floating IPs reach instances without security groups", len(evidence), attached),
+			Evidence:    evidence,
+			Timestamp:   currentTime,
+		}
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      "[PASS]",
+		Details:     fmt.Sprintf("All %d instances with floating IPs have security groups", attached),
+		Timestamp:   currentTime,
+	}
+}
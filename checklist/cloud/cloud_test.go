@@ -0,0 +1,258 @@
+package cloud
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/openstack"
+)
+
+// fakeCloud serves a Keystone token whose catalog points back at the server, and answers the other
+// requests with the JSON of responses keyed by path and query, or 404. {url} in a response is the server URL.
+func fakeCloud(t *testing.T, responses map[string]string) *openstack.Client {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/identity/v3/auth/tokens" {
+			if r.Method == http.MethodPost {
+				w.Header().Set("X-Subject-Token", "token")
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(strings.ReplaceAll(`{"token": {"project": {"id": "admin-project"}, "catalog": [
+					{"type": "identity", "endpoints": [{"interface": "public", "url": "{url}/identity"}]},
+					{"type": "network", "endpoints": [{"interface": "public", "url": "{url}/network"}]},
+					{"type": "image", "endpoints": [{"interface": "public", "url": "{url}/image"}]},
+					{"type": "volumev3", "endpoints": [{"interface": "public", "url": "{url}/volume/v3/admin-project"}]}
+				]}}`, "{url}", server.URL)))
+			}
+			return
+		}
+
+		body, ok := responses[r.URL.RequestURI()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(strings.ReplaceAll(body, "{url}", server.URL)))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := openstack.NewClient(openstack.Config{
+		AuthURL:   server.URL + "/identity",
+		Username:  "admin",
+		Password:  "secret",
+		Interface: "public",
+	}, server.Client())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+// adminResponses returns the identity responses of admin role assignments, one per user and project pair
+func adminResponses(assignments map[string][]string) map[string]string {
+	var entries []string
+	for user, projects := range assignments {
+		for _, project := range projects {
+			entries = append(entries, `{"user": {"id": "`+user+`", "name": "`+user+`"}, "scope": {"project": {"id": "`+project+`", "name": "`+project+`"}}}`)
+		}
+	}
+	return map[string]string{
+		"/identity/v3/roles?name=admin":                                            `{"roles": [{"id": "role-admin"}]}`,
+		"/identity/v3/role_assignments?role.id=role-admin&effective&include_names": `{"role_assignments": [` + strings.Join(entries, ",") + `]}`,
+	}
+}
+
+func with(responses map[string]string, extra map[string]string) map[string]string {
+	for k, v := range extra {
+		responses[k] = v
+	}
+	return responses
+}
+
+func TestCloudChecks(t *testing.T) {
+	tests := []struct {
+		name      string
+		check     func(*openstack.Client) checklist.CheckResult
+		responses map[string]string
+		result    string
+		evidence  int
+	}{
+		{
+			name:      "01 admin in few projects",
+			check:     CheckCloud01,
+			responses: adminResponses(map[string][]string{"alice": {"p1", "p2", "p3"}, "bob": {"p1"}}),
+			result:    "[PASS]",
+		},
+		{
+			name:      "01 admin in many projects",
+			check:     CheckCloud01,
+			responses: adminResponses(map[string][]string{"alice": {"p1", "p2", "p3", "p4"}, "bob": {"p1"}}),
+			result:    "[FAIL]",
+			evidence:  1,
+		},
+		{
+			name:  "01 admin role missing",
+			check: CheckCloud01,
+			responses: map[string]string{
+				"/identity/v3/roles?name=admin": `{"roles": []}`,
+			},
+			result: "[ERROR]",
+		},
+		{
+			name:  "02 administrators with MFA rules",
+			check: CheckCloud02,
+			responses: with(adminResponses(map[string][]string{"alice": {"p1", "p2"}}), map[string]string{
+				"/identity/v3/users/alice": `{"user": {"name": "alice", "options": {"multi_factor_auth_enabled": true, "multi_factor_auth_rules": [["password", "totp"]]}}}`,
+			}),
+			result: "[PASS]",
+		},
+		{
+			name:  "02 administrator without MFA",
+			check: CheckCloud02,
+			responses: with(adminResponses(map[string][]string{"alice": {"p1"}, "bob": {"p1"}}), map[string]string{
+				"/identity/v3/users/alice": `{"user": {"name": "alice", "options": {"multi_factor_auth_enabled": true, "multi_factor_auth_rules": [["password", "totp"]]}}}`,
+				"/identity/v3/users/bob":   `{"user": {"name": "bob", "options": {"multi_factor_auth_enabled": true}}}`,
+			}),
+			result:   "[FAIL]",
+			evidence: 1,
+		},
+		{
+			name:      "02 no administrators",
+			check:     CheckCloud02,
+			responses: adminResponses(nil),
+			result:    "[NA]",
+		},
+		{
+			name:  "03 restricted ingress",
+			check: CheckCloud03,
+			responses: map[string]string{
+				"/network/v2.0/security-group-rules?direction=ingress": `{"security_group_rules": [
+					{"id": "r1", "protocol": "tcp", "port_range_min": 22, "port_range_max": 22, "remote_ip_prefix": "10.0.0.0/8"},
+					{"id": "r2", "protocol": "tcp", "port_range_min": 443, "port_range_max": 443, "remote_ip_prefix": "0.0.0.0/0"},
+					{"id": "r3", "protocol": "udp", "port_range_min": 1, "port_range_max": 65535, "remote_ip_prefix": "::/0"},
+					{"id": "r4", "protocol": null, "remote_group_id": "default"}
+				]}`,
+			},
+			result: "[PASS]",
+		},
+		{
+			name:  "03 SSH and RDP open to anywhere",
+			check: CheckCloud03,
+			responses: map[string]string{
+				"/network/v2.0/security-group-rules?direction=ingress": `{"security_group_rules": [
+					{"id": "r1", "protocol": "tcp", "port_range_min": 22, "port_range_max": 22, "remote_ip_prefix": "0.0.0.0/0"},
+					{"id": "r2", "protocol": "tcp", "port_range_min": 3389, "port_range_max": 3389, "remote_ip_prefix": "::/0"},
+					{"id": "r3", "protocol": null, "remote_ip_prefix": null, "remote_group_id": null},
+					{"id": "r4", "protocol": "tcp", "port_range_min": 443, "port_range_max": 443, "remote_ip_prefix": "0.0.0.0/0"}
+				]}`,
+			},
+			result:   "[FAIL]",
+			evidence: 3,
+		},
+		{
+			name:  "04 public images of the admin project",
+			check: CheckCloud04,
+			responses: map[string]string{
+				"/image/v2/images?visibility=public":           `{"images": [{"id": "i1", "name": "cirros", "owner": "admin-project"}], "next": "/v2/images?visibility=public&marker=i1"}`,
+				"/image/v2/images?visibility=public&marker=i1": `{"images": [{"id": "i2", "name": "ubuntu", "owner": "admin-project"}]}`,
+			},
+			result: "[PASS]",
+		},
+		{
+			name:  "04 public image of another project on the second page",
+			check: CheckCloud04,
+			responses: map[string]string{
+				"/image/v2/images?visibility=public":           `{"images": [{"id": "i1", "name": "cirros", "owner": "admin-project"}], "next": "/v2/images?visibility=public&marker=i1"}`,
+				"/image/v2/images?visibility=public&marker=i1": `{"images": [{"id": "i2", "name": "miner", "owner": "tenant"}]}`,
+			},
+			result:   "[FAIL]",
+			evidence: 1,
+		},
+		{
+			name:  "05 encrypted volumes",
+			check: CheckCloud05,
+			responses: map[string]string{
+				"/volume/v3/admin-project/volumes/detail?all_tenants=1":           `{"volumes": [{"id": "v1", "encrypted": true}], "volumes_links": [{"rel": "next", "href": "{url}/volume/v3/admin-project/volumes/detail?all_tenants=1&marker=v1"}]}`,
+				"/volume/v3/admin-project/volumes/detail?all_tenants=1&marker=v1": `{"volumes": [{"id": "v2", "encrypted": true}]}`,
+			},
+			result: "[PASS]",
+		},
+		{
+			name:  "05 unencrypted volume on the second page",
+			check: CheckCloud05,
+			responses: map[string]string{
+				"/volume/v3/admin-project/volumes/detail?all_tenants=1":           `{"volumes": [{"id": "v1", "encrypted": true}], "volumes_links": [{"rel": "next", "href": "{url}/volume/v3/admin-project/volumes/detail?all_tenants=1&marker=v1"}]}`,
+				"/volume/v3/admin-project/volumes/detail?all_tenants=1&marker=v1": `{"volumes": [{"id": "v2", "name": "data", "encrypted": false}]}`,
+			},
+			result:   "[FAIL]",
+			evidence: 1,
+		},
+		{
+			name:  "05 no volumes",
+			check: CheckCloud05,
+			responses: map[string]string{
+				"/volume/v3/admin-project/volumes/detail?all_tenants=1": `{"volumes": []}`,
+			},
+			result: "[NA]",
+		},
+		{
+			name:  "06 instances behind security groups",
+			check: CheckCloud06,
+			responses: map[string]string{
+				"/network/v2.0/floatingips": `{"floatingips": [
+					{"id": "f1", "floating_ip_address": "203.0.113.1", "port_id": "p1"},
+					{"id": "f2", "floating_ip_address": "203.0.113.2", "port_id": "p2"},
+					{"id": "f3", "floating_ip_address": "203.0.113.3", "port_id": null}
+				]}`,
+				"/network/v2.0/ports/p1": `{"port": {"device_id": "vm1", "device_owner": "compute:nova", "security_groups": ["default"], "port_security_enabled": true}}`,
+				"/network/v2.0/ports/p2": `{"port": {"device_id": "router1", "device_owner": "network:router_gateway", "security_groups": []}}`,
+			},
+			result: "[PASS]",
+		},
+		{
+			name:  "06 instances without security groups",
+			check: CheckCloud06,
+			responses: map[string]string{
+				"/network/v2.0/floatingips": `{"floatingips": [
+					{"id": "f1", "floating_ip_address": "203.0.113.1", "port_id": "p1"},
+					{"id": "f2", "floating_ip_address": "203.0.113.2", "port_id": "p2"}
+				]}`,
+				"/network/v2.0/ports/p1": `{"port": {"device_id": "vm1", "device_owner": "compute:nova", "security_groups": []}}`,
+				"/network/v2.0/ports/p2": `{"port": {"device_id": "vm2", "device_owner": "compute:nova", "security_groups": ["default"], "port_security_enabled": false}}`,
+			},
+			result:   "[FAIL]",
+			evidence: 2,
+		},
+		{
+			name:  "06 no attached floating IPs",
+			check: CheckCloud06,
+			responses: map[string]string{
+				"/network/v2.0/floatingips": `{"floatingips": [{"id": "f1", "floating_ip_address": "203.0.113.1", "port_id": null}]}`,
+			},
+			result: "[NA]",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := test.check(fakeCloud(t, test.responses))
+			if result.Result != test.result {
+				t.Errorf("result = %s (%s), want %s", result.Result, result.Details, test.result)
+			}
+			if len(result.Evidence) != test.evidence {
+				t.Errorf("%d evidence entries, want %d: %+v", len(result.Evidence), test.evidence, result.Evidence)
+			}
+		})
+	}
+}
+
+func TestCloudCheckAPIError(t *testing.T) {
+	// The fake server has no response for the rules, so the request fails with 404
+	result := CheckCloud03(fakeCloud(t, map[string]string{}))
+	if result.Result != "[ERROR]" {
+		t.Errorf("result = %s (%s), want [ERROR]", result.Result, result.Details)
+	}
+}
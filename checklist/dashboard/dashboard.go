@@ -4,96 +4,25 @@ package dashboard
 import (
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/checklist/permission"
 	"github.com/gunh0/openstack-security-hub/util"
 	"golang.org/x/crypto/ssh"
 )
 
 // CheckDashboard01 checks if user/group ownership of config files is set to root/horizon
 func CheckDashboard01(client *ssh.Client) checklist.CheckResult {
-	currentTime := time.Now().UTC().Format(time.RFC3339)
-	const (
-		description = "Is user/group of config files set to root/horizon?"
-	)
-
-	session, err := client.NewSession()
-	if err != nil {
-		return checklist.CheckResult{
-			Description: description,
-			Result:      "[ERROR]",
-			Details:     fmt.Sprintf("Failed to create SSH session: %v", err),
-			Timestamp:   currentTime,
-		}
-	}
-	defer session.Close()
-
-	// Check file permissions and ownership
-	cmd := `
-		if [ ! -f "/etc/openstack-dashboard/local_settings.py" ]; then
-            echo "FILE_NOT_FOUND"
-            exit 0
-		fi
-
-		if [ ! -r "/etc/openstack-dashboard/local_settings.py" ]; then
-            echo "PERMISSION_DENIED"
-            exit 0
-		fi
-
-		ownership=$(stat -L -c "%U %G" /etc/openstack-dashboard/local_settings.py 2>/dev/null)
-		echo "OWNERSHIP:$ownership"
-	`
-
-	output, err := session.CombinedOutput(cmd)
-	result := strings.TrimSpace(string(output))
-
-	// Process results
-	switch {
-	case strings.Contains(result, "PERMISSION_DENIED"):
-		return checklist.CheckResult{
-			Description: description,
-			Result:      "[NA]",
-			Details:     "Cannot check local_settings.py: permission denied",
-			Timestamp:   currentTime,
-		}
-	case strings.Contains(result, "FILE_NOT_FOUND"):
-		return checklist.CheckResult{
-			Description: description,
-			Result:      "[NA]",
-			Details:     "local_settings.py not found",
-			Timestamp:   currentTime,
-		}
-	}
-
-	// Check ownership
-	if strings.Contains(result, "OWNERSHIP:") {
-		ownership := strings.TrimPrefix(result, "OWNERSHIP:")
-		ownership = strings.TrimSpace(ownership)
-
-		if ownership == "root horizon" {
-			return checklist.CheckResult{
-				Description: description,
-				Result:      "[PASS]",
-				Details:     "File ownership is correctly set to root:horizon",
-				Timestamp:   currentTime,
-			}
-		}
+	return CheckDashboard01WithParams(client, nil)
+}
 
-		return checklist.CheckResult{
-			Description: description,
-			Result:      "[FAIL]",
-			Details:     fmt.Sprintf("Current ownership is %s (expected: root horizon)", ownership),
-			Timestamp:   currentTime,
-		}
-	}
+// CheckDashboard01WithParams checks the ownership of local_settings.py against the "owner" parameter ("user group")
+func CheckDashboard01WithParams(client *ssh.Client, params checklist.Params) checklist.CheckResult {
+	expected := params.Get("owner", "root horizon")
+	description := fmt.Sprintf("Is user/group of config files set to %s?", strings.Replace(expected, " ", "/", 1))
 
-	return checklist.CheckResult{
-		Description: description,
-		Result:      "[ERROR]",
-		Details:     "Failed to determine file ownership",
-		Timestamp:   currentTime,
-	}
+	owners, groups := permission.ParseOwnership(expected)
+	return permission.CheckFile(client, "/etc/openstack-dashboard/local_settings.py", permission.AnyMode.WithOwnership(owners, groups), description)
 }
 
 // CheckDashboard04 checks if CSRF_COOKIE_SECURE parameter is set to True
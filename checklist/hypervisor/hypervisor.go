@@ -0,0 +1,601 @@
+// checklist/hypervisor/hypervisor.go
+package hypervisor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/checklist/permission"
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	errFileNotFound     = errors.New("file does not exist")
+	errPermissionDenied = errors.New("permission denied")
+)
+
+// readOptions returns the last value of each requested option in a libvirt style config file
+// (key = value, # comments). Missing options are left out of the map.
+func readOptions(client *ssh.Client, file string, options ...string) (map[string]string, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SSH session: %v", err)
+	}
+	defer session.Close()
+
+	cmd := fmt.Sprintf(`
+		if [ ! -f "%s" ]; then
+			echo "FILE_NOT_FOUND"
+			exit 0
+		fi
+
+		if [ ! -r "%s" ]; then
+			echo "PERMISSION_DENIED"
+			exit 0
+		fi
+
+		grep -E "^[ \t]*(%s)[ \t]*=" "%s" 2>/dev/null | sed 's/^/OPTION:/'
+	`, file, file, strings.Join(options, "|"), file)
+
+	output, err := session.CombinedOutput(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute command: %v", err)
+	}
+
+	result := strings.TrimSpace(string(output))
+	switch {
+	case strings.Contains(result, "FILE_NOT_FOUND"):
+		return nil, errFileNotFound
+	case strings.Contains(result, "PERMISSION_DENIED"):
+		return nil, errPermissionDenied
+	}
+
+	values := map[string]string{}
+	for _, line := range strings.Split(result, "\n") {
+		if !strings.HasPrefix(line, "OPTION:") {
+			continue
+		}
+		key, value, _ := strings.Cut(strings.TrimPrefix(line, "OPTION:"), "=")
+		value = strings.TrimSpace(value)
+		if i := strings.Index(value, "#"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+		values[strings.TrimSpace(key)] = strings.Trim(value, `"'`)
+	}
+
+	return values, nil
+}
+
+// optionErrorResult converts a readOptions error into a check result
+func optionErrorResult(description, file, currentTime string, err error) checklist.CheckResult {
+	switch err {
+	case errFileNotFound:
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[NA]",
+			Details:     fmt.Sprintf("%s not found", file),
+			Timestamp:   currentTime,
+		}
+	case errPermissionDenied:
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[NA]",
+			Details:     fmt.Sprintf("Cannot check %s: permission denied", file),
+			Timestamp:   currentTime,
+		}
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      "[ERROR]",
+		Details:     err.Error(),
+		Timestamp:   currentTime,
+	}
+}
+
+// CheckHypervisor01 checks if libvirtd only accepts authenticated TLS connections
+func CheckHypervisor01(client *ssh.Client) checklist.CheckResult {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+	const (
+		description = "Is remote access to libvirtd restricted to authenticated TLS connections?"
+		configFile  = "/etc/libvirt/libvirtd.conf"
+	)
+
+	values, err := readOptions(client, configFile, "listen_tls", "listen_tcp", "auth_tcp", "auth_tls")
+	if err != nil {
+		return optionErrorResult(description, configFile, currentTime, err)
+	}
+
+	// Since libvirt 5.6 the TCP listener is enabled through systemd socket units instead of listen_tcp
+	session, err := client.NewSession()
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     fmt.Sprintf("Failed to create SSH session: %v", err),
+			Timestamp:   currentTime,
+		}
+	}
+	defer session.Close()
+
+	output, _ := session.CombinedOutput(`systemctl is-enabled libvirtd-tcp.socket 2>/dev/null || echo "disabled"`)
+	tcpSocketEnabled := strings.HasPrefix(strings.TrimSpace(string(output)), "enabled")
+
+	var details strings.Builder
+	failed := false
+
+	if values["listen_tcp"] == "1" || tcpSocketEnabled {
+		failed = true
+		if values["auth_tcp"] == "none" {
+			details.WriteString("- Unencrypted TCP listener is enabled with auth_tcp = \"none\"\n")
+		} else {
+			details.WriteString("- Unencrypted TCP listener is enabled (listen_tcp = 1 or libvirtd-tcp.socket)\n")
+		}
+	} else {
+		details.WriteString("- TCP listener is disabled\n")
+	}
+
+	if values["listen_tls"] == "0" {
+		details.WriteString("- TLS listener is disabled\n")
+	} else if values["auth_tls"] == "none" {
+		failed = true
+		details.WriteString("- TLS listener is enabled with auth_tls = \"none\"\n")
+	} else {
+		details.WriteString("- TLS listener is enabled\n")
+	}
+
+	result := "[PASS]"
+	if failed {
+		result = "[FAIL]"
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      result,
+		Details:     details.String(),
+		Timestamp:   currentTime,
+	}
+}
+
+// CheckHypervisor02 checks if QEMU uses a mandatory access control security driver
+func CheckHypervisor02(client *ssh.Client) checklist.CheckResult {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+	const (
+		description = "Is a QEMU security_driver (SELinux/AppArmor) enabled?"
+		configFile  = "/etc/libvirt/qemu.conf"
+	)
+
+	values, err := readOptions(client, configFile, "security_driver", "security_default_confined")
+	if err != nil {
+		return optionErrorResult(description, configFile, currentTime, err)
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     fmt.Sprintf("Failed to create SSH session: %v", err),
+			Timestamp:   currentTime,
+		}
+	}
+	defer session.Close()
+
+	// Detect which MAC framework is active on the host
+	cmd := `
+		if command -v getenforce >/dev/null 2>&1 && [ "$(getenforce 2>/dev/null)" = "Enforcing" ]; then
+			echo "MAC:selinux"
+		elif [ -r /sys/module/apparmor/parameters/enabled ] && [ "$(cat /sys/module/apparmor/parameters/enabled)" = "Y" ]; then
+			echo "MAC:apparmor"
+		else
+			echo "MAC:none"
+		fi
+	`
+	output, _ := session.CombinedOutput(cmd)
+	mac := strings.TrimPrefix(strings.TrimSpace(string(output)), "MAC:")
+
+	driver := values["security_driver"]
+	switch {
+	case driver == "none":
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[FAIL]",
+			Details:     "security_driver is set to \"none\", guests are not confined by sVirt",
+			Timestamp:   currentTime,
+		}
+	case values["security_default_confined"] == "0":
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[FAIL]",
+			Details:     "security_default_confined is set to 0, guests are unconfined unless requested otherwise",
+			Timestamp:   currentTime,
+		}
+	case mac == "none":
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[FAIL]",
+			Details:     "Neither SELinux (enforcing) nor AppArmor is active on the host",
+			Timestamp:   currentTime,
+		}
+	case driver == "":
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[PASS]",
+			Details:     fmt.Sprintf("security_driver is not set, libvirt uses the active MAC framework (%s)", mac),
+			Timestamp:   currentTime,
+		}
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      "[PASS]",
+		Details:     fmt.Sprintf("security_driver is %q and %s is active", driver, mac),
+		Timestamp:   currentTime,
+	}
+}
+
+// CheckHypervisor03 checks if running QEMU processes are confined by sVirt
+func CheckHypervisor03(client *ssh.Client) checklist.CheckResult {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+	const (
+		description = "Are running QEMU processes confined by sVirt?"
+	)
+
+	session, err := client.NewSession()
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     fmt.Sprintf("Failed to create SSH session: %v", err),
+			Timestamp:   currentTime,
+		}
+	}
+	defer session.Close()
+
+	// Print "PROC:<pid> <label>" for each qemu process. The label comes last since AppArmor labels
+	// contain a space, e.g. "libvirt-<uuid> (enforce)".
+	cmd := `ps -eo pid=,comm=,label= 2>/dev/null | awk '$2 ~ /^qemu/ {pid = $1; $1 = ""; $2 = ""; sub(/^ +/, ""); print "PROC:" pid " " $0}'`
+
+	output, err := session.CombinedOutput(cmd)
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     fmt.Sprintf("Failed to execute command: %v", err),
+			Timestamp:   currentTime,
+		}
+	}
+
+	result := strings.TrimSpace(string(output))
+	if result == "" {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[NA]",
+			Details:     "No running QEMU processes found",
+			Timestamp:   currentTime,
+		}
+	}
+
+	var details strings.Builder
+	failed := false
+	count := 0
+	for _, line := range strings.Split(result, "\n") {
+		pid, label, ok := strings.Cut(strings.TrimPrefix(line, "PROC:"), " ")
+		if !ok || !strings.HasPrefix(line, "PROC:") {
+			continue
+		}
+		count++
+		label = strings.TrimSpace(label)
+
+		// SELinux: system_u:system_r:svirt_t:s0:c1,c2  AppArmor: libvirt-<uuid> (enforce)
+		// A profile in complain mode only logs, so it does not confine the process
+		if strings.Contains(label, "svirt") ||
+			(strings.HasPrefix(label, "libvirt-") && !strings.HasSuffix(label, "(complain)")) {
+			continue
+		}
+		failed = true
+		details.WriteString(fmt.Sprintf("- qemu process %s runs with label %q\n", pid, label))
+	}
+
+	if failed {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[FAIL]",
+			Details:     details.String(),
+			Timestamp:   currentTime,
+		}
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      "[PASS]",
+		Details:     fmt.Sprintf("All %d running QEMU processes are confined by sVirt", count),
+		Timestamp:   currentTime,
+	}
+}
+
+// CheckHypervisor04 checks if instance disks under /var/lib/nova/instances are protected
+func CheckHypervisor04(client *ssh.Client) checklist.CheckResult {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+	const (
+		description = "Are strict permissions set for /var/lib/nova/instances?"
+	)
+
+	session, err := client.NewSession()
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     fmt.Sprintf("Failed to create SSH session: %v", err),
+			Timestamp:   currentTime,
+		}
+	}
+	defer session.Close()
+
+	cmd := `
+		if [ ! -d /var/lib/nova/instances ]; then
+			echo "FILE_NOT_FOUND"
+			exit 0
+		fi
+
+		find /var/lib/nova/instances -maxdepth 2 \( -perm -o+w -o \( -type f -perm -o+r \) \) 2>/dev/null | head -n 20 | sed 's/^/EXPOSED:/'
+	`
+
+	output, err := session.CombinedOutput(cmd)
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     fmt.Sprintf("Failed to execute command: %v", err),
+			Timestamp:   currentTime,
+		}
+	}
+
+	result := strings.TrimSpace(string(output))
+	if strings.Contains(result, "FILE_NOT_FOUND") {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[NA]",
+			Details:     "/var/lib/nova/instances does not exist",
+			Timestamp:   currentTime,
+		}
+	}
+
+	dir, err := permission.StatOne(client, "/var/lib/nova/instances")
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     err.Error(),
+			Timestamp:   currentTime,
+		}
+	}
+
+	// The instances directory must not be writable by group or others
+	var details strings.Builder
+	failed := false
+	policy := permission.MustFromMode("755").WithOwnership([]string{"nova", "root"}, nil)
+	if violations := policy.Evaluate(dir); len(violations) > 0 {
+		failed = true
+		for _, violation := range violations {
+			details.WriteString(fmt.Sprintf("- /var/lib/nova/instances: %s\n", violation))
+		}
+	} else {
+		details.WriteString(fmt.Sprintf("- /var/lib/nova/instances is %s:%s %s\n", dir.Owner, dir.Group, dir.ModeString()))
+	}
+
+	for _, line := range strings.Split(result, "\n") {
+		if strings.HasPrefix(line, "EXPOSED:") {
+			failed = true
+			details.WriteString(fmt.Sprintf("- %s is accessible by other users\n", strings.TrimPrefix(line, "EXPOSED:")))
+		}
+	}
+
+	result = "[PASS]"
+	if failed {
+		result = "[FAIL]"
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      result,
+		Details:     details.String(),
+		Timestamp:   currentTime,
+	}
+}
+
+// CheckHypervisor05 checks if Kernel Samepage Merging is disabled
+func CheckHypervisor05(client *ssh.Client) checklist.CheckResult {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+	const (
+		description = "Is Kernel Samepage Merging (KSM) disabled?"
+	)
+
+	session, err := client.NewSession()
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     fmt.Sprintf("Failed to create SSH session: %v", err),
+			Timestamp:   currentTime,
+		}
+	}
+	defer session.Close()
+
+	cmd := `
+		if [ ! -r /sys/kernel/mm/ksm/run ]; then
+			echo "KSM_NOT_SUPPORTED"
+			exit 0
+		fi
+
+		echo "RUN:$(cat /sys/kernel/mm/ksm/run)"
+		echo "MERGE_ACROSS_NODES:$(cat /sys/kernel/mm/ksm/merge_across_nodes 2>/dev/null)"
+	`
+
+	output, err := session.CombinedOutput(cmd)
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     fmt.Sprintf("Failed to execute command: %v", err),
+			Timestamp:   currentTime,
+		}
+	}
+
+	result := strings.TrimSpace(string(output))
+	if strings.Contains(result, "KSM_NOT_SUPPORTED") {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[PASS]",
+			Details:     "KSM is not available in this kernel",
+			Timestamp:   currentTime,
+		}
+	}
+
+	// run: 0 = stopped, 1 = running, 2 = stopped and unmerged
+	if strings.Contains(result, "RUN:1") {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[FAIL]",
+			Details:     "KSM is running (/sys/kernel/mm/ksm/run = 1), memory deduplication between guests enables side-channel attacks",
+			Timestamp:   currentTime,
+		}
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      "[PASS]",
+		Details:     "KSM is not running",
+		Timestamp:   currentTime,
+	}
+}
+
+// CheckHypervisor06 checks if VNC and SPICE consoles are protected with TLS
+func CheckHypervisor06(client *ssh.Client) checklist.CheckResult {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+	const (
+		description = "Is TLS enabled for VNC/SPICE consoles?"
+		novaConfig  = "/etc/nova/nova.conf"
+		qemuConfig  = "/etc/libvirt/qemu.conf"
+	)
+
+	session, err := client.NewSession()
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     fmt.Sprintf("Failed to create SSH session: %v", err),
+			Timestamp:   currentTime,
+		}
+	}
+	defer session.Close()
+
+	// Print "section|line" for console related options of nova.conf
+	cmd := fmt.Sprintf(`
+		if [ ! -f "%s" ]; then
+			echo "FILE_NOT_FOUND"
+			exit 0
+		fi
+
+		if [ ! -r "%s" ]; then
+			echo "PERMISSION_DENIED"
+			exit 0
+		fi
+
+		awk '
+			/^[ \t]*\[/ { section = $0; gsub(/[ \t\[\]]/, "", section) }
+			/^[ \t]*(enabled|auth_schemes|require_secure)[ \t]*=/ { print "NOVA:" section "|" $0 }
+		' "%s" 2>/dev/null
+	`, novaConfig, novaConfig, novaConfig)
+
+	output, err := session.CombinedOutput(cmd)
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     fmt.Sprintf("Failed to execute command: %v", err),
+			Timestamp:   currentTime,
+		}
+	}
+
+	result := strings.TrimSpace(string(output))
+	switch {
+	case strings.Contains(result, "FILE_NOT_FOUND"):
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[NA]",
+			Details:     "nova.conf not found (not a compute node)",
+			Timestamp:   currentTime,
+		}
+	case strings.Contains(result, "PERMISSION_DENIED"):
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[NA]",
+			Details:     "Cannot check nova.conf: permission denied",
+			Timestamp:   currentTime,
+		}
+	}
+
+	nova := map[string]string{}
+	for _, line := range strings.Split(result, "\n") {
+		section, option, found := strings.Cut(strings.TrimPrefix(line, "NOVA:"), "|")
+		if !found {
+			continue
+		}
+		key, value, _ := strings.Cut(option, "=")
+		nova[section+"."+strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	qemu, err := readOptions(client, qemuConfig, "vnc_tls", "spice_tls")
+	if err != nil && err != errFileNotFound && err != errPermissionDenied {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     err.Error(),
+			Timestamp:   currentTime,
+		}
+	}
+
+	var details strings.Builder
+	failed := false
+
+	// VNC is enabled by default in nova, SPICE is disabled by default
+	if !strings.EqualFold(nova["vnc.enabled"], "false") {
+		if strings.Contains(nova["vnc.auth_schemes"], "vencrypt") && qemu["vnc_tls"] == "1" {
+			details.WriteString("- VNC console uses VeNCrypt with vnc_tls = 1\n")
+		} else {
+			failed = true
+			details.WriteString(fmt.Sprintf("- VNC console is enabled without TLS ([vnc] auth_schemes = %q, vnc_tls = %q)\n",
+				nova["vnc.auth_schemes"], qemu["vnc_tls"]))
+		}
+	}
+
+	if strings.EqualFold(nova["spice.enabled"], "true") {
+		if strings.EqualFold(nova["spice.require_secure"], "true") && qemu["spice_tls"] == "1" {
+			details.WriteString("- SPICE console requires TLS with spice_tls = 1\n")
+		} else {
+			failed = true
+			details.WriteString(fmt.Sprintf("- SPICE console is enabled without TLS ([spice] require_secure = %q, spice_tls = %q)\n",
+				nova["spice.require_secure"], qemu["spice_tls"]))
+		}
+	}
+
+	if details.Len() == 0 {
+		details.WriteString("No graphical console is enabled")
+	}
+
+	result = "[PASS]"
+	if failed {
+		result = "[FAIL]"
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      result,
+		Details:     details.String(),
+		Timestamp:   currentTime,
+	}
+}
@@ -8,219 +8,117 @@ import (
 	"time"
 
 	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/checklist/permission"
 	"golang.org/x/crypto/ssh"
 )
 
-// Common function to check file ownership
-func checkFileOwnership(client *ssh.Client, filepath string) checklist.CheckResult {
-	currentTime := time.Now().UTC().Format(time.RFC3339)
-	session, err := client.NewSession()
-	if err != nil {
-		return checklist.CheckResult{
-			Result:      "[ERROR]",
-			Description: fmt.Sprintf("Is user/group ownership of %s set to keystone?", filepath),
-			Details:     fmt.Sprintf("Failed to create SSH session: %v", err),
-			Timestamp:   currentTime,
-		}
-	}
-	defer session.Close()
-
-	// First check current ownership
-	cmd := fmt.Sprintf(`
-        if [ -e "%s" ]; then
-            stat -L -c "%%U %%G" "%s"
-        else
-            echo "FILE_NOT_FOUND"
-        fi
-    `, filepath, filepath)
-
-	output, err := session.CombinedOutput(cmd)
-	if err != nil {
-		return checklist.CheckResult{
-			Result:      "[ERROR]",
-			Description: fmt.Sprintf("Is user/group ownership of %s set to keystone?", filepath),
-			Details:     fmt.Sprintf("Failed to execute command: %v", err),
-			Timestamp:   currentTime,
-		}
-	}
+// Expected values used when no profile overrides them
+const (
+	defaultOwner         = "keystone keystone"
+	defaultFileMode      = "640"
+	defaultDirectoryMode = "750"
+)
 
-	currentOwnership := strings.TrimSpace(string(output))
-	if currentOwnership == "FILE_NOT_FOUND" {
-		return checklist.CheckResult{
-			Result:      "[NA]",
-			Description: fmt.Sprintf("Is user/group ownership of %s set to keystone?", filepath),
-			Details:     "File does not exist",
-			Timestamp:   currentTime,
-		}
-	}
+// Common function to check file ownership against the expected "user group"
+func checkFileOwnership(client *ssh.Client, filepath string, expected string) checklist.CheckResult {
+	owners, groups := permission.ParseOwnership(expected)
+	policy := permission.AnyMode.WithOwnership(owners, groups)
 
-	// Check if ownership is correct
-	if currentOwnership == "keystone keystone" {
-		return checklist.CheckResult{
-			Result:      "[PASS]",
-			Description: fmt.Sprintf("Is user/group ownership of %s set to keystone?", filepath),
-			Details:     fmt.Sprintf("Current ownership is correct: %s", currentOwnership),
-			Timestamp:   currentTime,
-		}
-	}
-
-	return checklist.CheckResult{
-		Result:      "[FAIL]",
-		Description: fmt.Sprintf("Is user/group ownership of %s set to keystone?", filepath),
-		Details:     fmt.Sprintf("Current ownership: %s (expected: keystone keystone)", currentOwnership),
-		Timestamp:   currentTime,
-	}
+	description := fmt.Sprintf("Is user/group ownership of %s set to %s?", filepath, strings.Join(strings.Fields(expected), "/"))
+	return permission.CheckFile(client, filepath, policy, description)
 }
 
-// checkFilePermissions checks if file permissions are set correctly
-func checkFilePermissions(client *ssh.Client, filepath string, isDirectory bool) checklist.CheckResult {
-	currentTime := time.Now().UTC().Format(time.RFC3339)
-	session, err := client.NewSession()
-	if err != nil {
-		return checklist.CheckResult{
-			Result:      "[ERROR]",
-			Description: fmt.Sprintf("Are strict permissions set for %s?", filepath),
-			Details:     fmt.Sprintf("Failed to create SSH session: %v", err),
-			Timestamp:   currentTime,
-		}
-	}
-	defer session.Close()
-
-	// Check current permissions
-	cmd := fmt.Sprintf(`
-        if [ -e "%s" ]; then
-            stat -L -c "%%a" "%s"
-        else
-            echo "FILE_NOT_FOUND"
-        fi
-    `, filepath, filepath)
+// checkFilePermissions checks if file permissions are expectedPerms or stricter, class by class
+func checkFilePermissions(client *ssh.Client, filepath string, expectedPerms string) checklist.CheckResult {
+	description := fmt.Sprintf("Are strict permissions set for %s?", filepath)
 
-	output, err := session.CombinedOutput(cmd)
+	policy, err := permission.FromMode(expectedPerms)
 	if err != nil {
 		return checklist.CheckResult{
 			Result:      "[ERROR]",
-			Description: fmt.Sprintf("Are strict permissions set for %s?", filepath),
-			Details:     fmt.Sprintf("Failed to execute command: %v", err),
-			Timestamp:   currentTime,
-		}
-	}
-
-	currentPerms := strings.TrimSpace(string(output))
-	if currentPerms == "FILE_NOT_FOUND" {
-		return checklist.CheckResult{
-			Result:      "[NA]",
-			Description: fmt.Sprintf("Are strict permissions set for %s?", filepath),
-			Details:     "File does not exist",
-			Timestamp:   currentTime,
+			Description: description,
+			Details:     err.Error(),
+			Timestamp:   time.Now().UTC().Format(time.RFC3339),
 		}
 	}
 
-	// Check if permissions are correct
-	// For directories, check if permissions are 750 or stricter
-	// For files, check if permissions are 640 or stricter
-	var isValid bool
-	var expectedPerms string
-
-	if isDirectory {
-		expectedPerms = "750"
-		permsInt := parseOctal(currentPerms)
-		isValid = permsInt <= parseOctal("750")
-	} else {
-		expectedPerms = "640"
-		permsInt := parseOctal(currentPerms)
-		isValid = permsInt <= parseOctal("640")
-	}
-
-	if isValid {
-		return checklist.CheckResult{
-			Result:      "[PASS]",
-			Description: fmt.Sprintf("Are strict permissions set for %s?", filepath),
-			Details:     fmt.Sprintf("Current permissions: %s (meets or exceeds required: %s)", currentPerms, expectedPerms),
-			Timestamp:   currentTime,
-		}
-	}
+	return permission.CheckFile(client, filepath, policy, description)
+}
 
-	return checklist.CheckResult{
-		Result:      "[FAIL]",
-		Description: fmt.Sprintf("Are strict permissions set for %s?", filepath),
-		Details:     fmt.Sprintf("Current permissions: %s (should be %s or stricter)", currentPerms, expectedPerms),
-		Timestamp:   currentTime,
-	}
+// CheckOwnershipWithParams checks that the "path" parameter is owned by the "owner" parameter ("user group")
+func CheckOwnershipWithParams(client *ssh.Client, params checklist.Params) checklist.CheckResult {
+	return checkFileOwnership(client, params.Get("path", "/etc/keystone/keystone.conf"), params.Get("owner", defaultOwner))
 }
 
-// Helper function to parse octal permissions
-func parseOctal(s string) int {
-	n := 0
-	for _, c := range s {
-		n = n*8 + int(c-'0')
-	}
-	return n
+// CheckPermissionsWithParams checks that the "path" parameter has the "mode" parameter or stricter
+func CheckPermissionsWithParams(client *ssh.Client, params checklist.Params) checklist.CheckResult {
+	return checkFilePermissions(client, params.Get("path", "/etc/keystone/keystone.conf"), params.Get("mode", defaultFileMode))
 }
 
 // Identity-01 check functions
 func CheckIdentity0101(client *ssh.Client) checklist.CheckResult {
-	return checkFileOwnership(client, "/etc/keystone/keystone.conf")
+	return checkFileOwnership(client, "/etc/keystone/keystone.conf", defaultOwner)
 }
 
 func CheckIdentity0102(client *ssh.Client) checklist.CheckResult {
-	return checkFileOwnership(client, "/etc/keystone/keystone-paste.ini")
+	return checkFileOwnership(client, "/etc/keystone/keystone-paste.ini", defaultOwner)
 }
 
 func CheckIdentity0103(client *ssh.Client) checklist.CheckResult {
-	return checkFileOwnership(client, "/etc/keystone/policy.json")
+	return checkFileOwnership(client, "/etc/keystone/policy.json", defaultOwner)
 }
 
 func CheckIdentity0104(client *ssh.Client) checklist.CheckResult {
-	return checkFileOwnership(client, "/etc/keystone/logging.conf")
+	return checkFileOwnership(client, "/etc/keystone/logging.conf", defaultOwner)
 }
 
 func CheckIdentity0105(client *ssh.Client) checklist.CheckResult {
-	return checkFileOwnership(client, "/etc/keystone/ssl/certs/signing_cert.pem")
+	return checkFileOwnership(client, "/etc/keystone/ssl/certs/signing_cert.pem", defaultOwner)
 }
 
 func CheckIdentity0106(client *ssh.Client) checklist.CheckResult {
-	return checkFileOwnership(client, "/etc/keystone/ssl/private/signing_key.pem")
+	return checkFileOwnership(client, "/etc/keystone/ssl/private/signing_key.pem", defaultOwner)
 }
 
 func CheckIdentity0107(client *ssh.Client) checklist.CheckResult {
-	return checkFileOwnership(client, "/etc/keystone/ssl/certs/ca.pem")
+	return checkFileOwnership(client, "/etc/keystone/ssl/certs/ca.pem", defaultOwner)
 }
 
 func CheckIdentity0108(client *ssh.Client) checklist.CheckResult {
-	return checkFileOwnership(client, "/etc/keystone")
+	return checkFileOwnership(client, "/etc/keystone", defaultOwner)
 }
 
 // Identity-02 check functions
 func CheckIdentity0201(client *ssh.Client) checklist.CheckResult {
-	return checkFilePermissions(client, "/etc/keystone/keystone.conf", false)
+	return checkFilePermissions(client, "/etc/keystone/keystone.conf", defaultFileMode)
 }
 
 func CheckIdentity0202(client *ssh.Client) checklist.CheckResult {
-	return checkFilePermissions(client, "/etc/keystone/keystone-paste.ini", false)
+	return checkFilePermissions(client, "/etc/keystone/keystone-paste.ini", defaultFileMode)
 }
 
 func CheckIdentity0203(client *ssh.Client) checklist.CheckResult {
-	return checkFilePermissions(client, "/etc/keystone/policy.json", false)
+	return checkFilePermissions(client, "/etc/keystone/policy.json", defaultFileMode)
 }
 
 func CheckIdentity0204(client *ssh.Client) checklist.CheckResult {
-	return checkFilePermissions(client, "/etc/keystone/logging.conf", false)
+	return checkFilePermissions(client, "/etc/keystone/logging.conf", defaultFileMode)
 }
 
 func CheckIdentity0205(client *ssh.Client) checklist.CheckResult {
-	return checkFilePermissions(client, "/etc/keystone/ssl/certs/signing_cert.pem", false)
+	return checkFilePermissions(client, "/etc/keystone/ssl/certs/signing_cert.pem", defaultFileMode)
 }
 
 func CheckIdentity0206(client *ssh.Client) checklist.CheckResult {
-	return checkFilePermissions(client, "/etc/keystone/ssl/private/signing_key.pem", false)
+	return checkFilePermissions(client, "/etc/keystone/ssl/private/signing_key.pem", defaultFileMode)
 }
 
 func CheckIdentity0207(client *ssh.Client) checklist.CheckResult {
-	return checkFilePermissions(client, "/etc/keystone/ssl/certs/ca.pem", false)
+	return checkFilePermissions(client, "/etc/keystone/ssl/certs/ca.pem", defaultFileMode)
 }
 
 func CheckIdentity0208(client *ssh.Client) checklist.CheckResult {
-	return checkFilePermissions(client, "/etc/keystone", true)
+	return checkFilePermissions(client, "/etc/keystone", defaultDirectoryMode)
 }
 
 func CheckIdentity03(client *ssh.Client) checklist.CheckResult {
@@ -263,11 +161,14 @@ func CheckIdentity03(client *ssh.Client) checklist.CheckResult {
 }
 
 func CheckIdentity05(client *ssh.Client) checklist.CheckResult {
-	const (
-		description = "Is max_request_body_size set to default (114688)?"
-		defaultSize = 114688
-		maxSize     = 10485760 // 10MB
-	)
+	return CheckIdentity05WithParams(client, nil)
+}
+
+// CheckIdentity05WithParams checks max_request_body_size against the "default_size" and "max_size" parameters
+func CheckIdentity05WithParams(client *ssh.Client, params checklist.Params) checklist.CheckResult {
+	defaultSize := params.Int("default_size", 114688)
+	maxSize := params.Int("max_size", 10485760) // 10MB
+	description := fmt.Sprintf("Is max_request_body_size set to default (%d)?", defaultSize)
 
 	session, err := client.NewSession()
 	if err != nil {
@@ -330,7 +231,7 @@ func CheckIdentity05(client *ssh.Client) checklist.CheckResult {
 		return checklist.CheckResult{
 			Description: description,
 			Result:      "[PASS]",
-			Details:     "max_request_body_size is set to the default value (114688)",
+			Details:     fmt.Sprintf("max_request_body_size is set to the default value (%d)", defaultSize),
 		}
 	}
 
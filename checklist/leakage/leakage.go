@@ -0,0 +1,412 @@
+// checklist/leakage/leakage.go
+package leakage
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/checklist/permission"
+	"golang.org/x/crypto/ssh"
+)
+
+// services lists the OpenStack components whose /etc and /var/log directories are scanned
+var services = []string{
+	"aodh", "barbican", "ceilometer", "cinder", "designate", "glance", "heat", "horizon",
+	"ironic", "keystone", "magnum", "manila", "neutron", "nova", "octavia",
+	"openstack-dashboard", "placement", "swift",
+}
+
+// maxMatches limits the amount of evidence collected from a single scan
+const maxMatches = 200
+
+var (
+	// Config options holding secrets: password, admin_password, secret, *_key, token, ...
+	configSecretPattern = `^[[:space:]]*[A-Za-z_]*(password|secret|token|_key|passwd)[[:space:]]*=[[:space:]]*[^[:space:]]`
+	// Credentials embedded in URLs such as transport_url or database connection, including the
+	// later hosts of a multi-broker URL such as rabbit://u1:p1@h1,u2:p2@h2/
+	urlCredentialPattern = `://([^/[:space:]]*,)?[^:/@,[:space:]]+:[^@/,[:space:]]+@`
+	privateKeyPattern    = `-----BEGIN ([A-Z]+ )?PRIVATE KEY-----`
+
+	// Log messages that leak secrets, e.g. request bodies or X-Auth-Token headers at DEBUG level
+	logSecretPattern = `("password"[[:space:]]*:[[:space:]]*"[^"*]|password=[^*[:space:]]|X-Auth-Token: [^*{[:space:]]|X-Subject-Token: [^*{[:space:]])`
+
+	assignmentRe   = regexp.MustCompile(`^(\s*[A-Za-z_]+\s*=\s*)(.*)$`)
+	urlAuthorityRe = regexp.MustCompile(`://[^/\s]+`)
+	jsonPasswordRe = regexp.MustCompile(`("password"\s*:\s*")[^"]*"`)
+	keyValueRe     = regexp.MustCompile(`(?i)(password=|X-Auth-Token: |X-Subject-Token: )\S+`)
+)
+
+// secretFilePolicy is the most access allowed on configuration files holding secrets and their backups:
+// read access for the service group, none for other users or named ACL entries beyond that
+var secretFilePolicy = permission.MustFromMode("640")
+
+// match is a single grep hit on the remote host
+type match struct {
+	File    string
+	Line    int
+	Content string
+}
+
+// serviceDirs returns the existing service directories under base as shell words
+func serviceDirs(base string) string {
+	dirs := make([]string, 0, len(services))
+	for _, service := range services {
+		dirs = append(dirs, fmt.Sprintf("%s/%s", base, service))
+	}
+	return strings.Join(dirs, " ")
+}
+
+// grepRemote runs an extended grep over the given directories and returns at most maxMatches hits
+func grepRemote(client *ssh.Client, dirs, pattern string, extraArgs string) ([]match, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SSH session: %v", err)
+	}
+	defer session.Close()
+
+	cmd := fmt.Sprintf(`
+		for d in %s; do
+			[ -d "$d" ] && grep -rnIE -m 20 %s -e '%s' "$d" 2>/dev/null
+		done | head -n %d
+	`, dirs, extraArgs, pattern, maxMatches)
+
+	output, err := session.CombinedOutput(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute command: %v", err)
+	}
+
+	var matches []match
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		lineNumber, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		matches = append(matches, match{File: parts[0], Line: lineNumber, Content: parts[2]})
+	}
+
+	return matches, nil
+}
+
+// redact hides secret values so the snippet can be reported as evidence
+func redact(content string) string {
+	content = strings.TrimSpace(content)
+	content = urlAuthorityRe.ReplaceAllStringFunc(content, redactAuthority)
+	content = jsonPasswordRe.ReplaceAllString(content, `${1}****"`)
+	content = keyValueRe.ReplaceAllString(content, "${1}****")
+
+	if strings.Contains(content, "PRIVATE KEY-----") {
+		return content
+	}
+	if m := assignmentRe.FindStringSubmatch(content); m != nil && !strings.Contains(m[2], "****") {
+		return m[1] + "****"
+	}
+
+	if len(content) > 160 {
+		content = content[:160] + "..."
+	}
+	return content
+}
+
+// redactAuthority masks the password of every user:pass@host of a URL authority, which holds one
+// host per broker in multi-broker URLs such as rabbit://u1:p1@h1:5672,u2:p2@h2:5672
+func redactAuthority(authority string) string {
+	hosts := strings.Split(strings.TrimPrefix(authority, "://"), ",")
+	for i, host := range hosts {
+		at := strings.LastIndex(host, "@")
+		if at < 0 {
+			continue
+		}
+		if user, _, ok := strings.Cut(host[:at], ":"); ok {
+			hosts[i] = user + ":****" + host[at:]
+		}
+	}
+	return "://" + strings.Join(hosts, ",")
+}
+
+// withoutFilePaths drops options such as ssl_key = /etc/ssl/key.pem that reference a file instead of a secret
+func withoutFilePaths(matches []match) []match {
+	var filtered []match
+	for _, m := range matches {
+		if v := assignmentRe.FindStringSubmatch(strings.TrimSpace(m.Content)); v != nil {
+			value := strings.TrimSpace(v[2])
+			if strings.HasPrefix(value, "/") || value == "<None>" {
+				continue
+			}
+		}
+		filtered = append(filtered, m)
+	}
+	return filtered
+}
+
+// toEvidence converts grep matches into redacted evidence
+func toEvidence(matches []match) []checklist.Evidence {
+	evidence := make([]checklist.Evidence, 0, len(matches))
+	for _, m := range matches {
+		evidence = append(evidence, checklist.Evidence{
+			Location: m.File,
+			Line:     m.Line,
+			Snippet:  redact(m.Content),
+		})
+	}
+	return evidence
+}
+
+// CheckLeakage01 checks if configuration files holding plaintext secrets are protected from other users
+func CheckLeakage01(client *ssh.Client) checklist.CheckResult {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+	const (
+		description = "Are plaintext secrets in service configuration files protected?"
+	)
+
+	pattern := fmt.Sprintf("%s|%s|%s", configSecretPattern, urlCredentialPattern, privateKeyPattern)
+	matches, err := grepRemote(client, serviceDirs("/etc"), pattern, "")
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     err.Error(),
+			Timestamp:   currentTime,
+		}
+	}
+
+	matches = withoutFilePaths(matches)
+	if len(matches) == 0 {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[PASS]",
+			Details:     "No plaintext secrets found in readable service configuration files",
+			Timestamp:   currentTime,
+		}
+	}
+
+	// Secrets are expected in service configs; they are a finding when other users can read them
+	files := map[string]bool{}
+	var paths []string
+	for _, m := range matches {
+		if !files[m.File] {
+			files[m.File] = true
+			paths = append(paths, m.File)
+		}
+	}
+
+	stats, err := permission.Stat(client, paths...)
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     err.Error(),
+			Timestamp:   currentTime,
+		}
+	}
+
+	exposed := map[string]string{}
+	for _, file := range stats {
+		if !file.Exists {
+			continue
+		}
+		if violations := secretFilePolicy.Evaluate(file); len(violations) > 0 {
+			exposed[file.Path] = fmt.Sprintf("mode %s: %s", file.ModeString(), strings.Join(violations, ", "))
+		}
+	}
+
+	var exposedMatches []match
+	for _, m := range matches {
+		if _, ok := exposed[m.File]; ok {
+			exposedMatches = append(exposedMatches, m)
+		}
+	}
+
+	if len(exposedMatches) > 0 {
+		var details strings.Builder
+		details.WriteString(fmt.Sprintf("%d plaintext secrets found in files readable by other users:\n", len(exposedMatches)))
+		for _, m := range exposedMatches {
+			if reason, ok := exposed[m.File]; ok {
+				details.WriteString(fmt.Sprintf("- %s (%s)\n", m.File, reason))
+				delete(exposed, m.File)
+			}
+		}
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[FAIL]",
+			Details:     details.String(),
+			Evidence:    toEvidence(exposedMatches),
+			Timestamp:   currentTime,
+		}
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      "[PASS]",
+		Details:     fmt.Sprintf("%d plaintext secrets found in %d files, none of them readable by other users", len(matches), len(files)),
+		Evidence:    toEvidence(matches),
+		Timestamp:   currentTime,
+	}
+}
+
+// CheckLeakage02 checks if service logs contain secrets
+func CheckLeakage02(client *ssh.Client) checklist.CheckResult {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+	const (
+		description = "Are service logs free of plaintext secrets?"
+	)
+
+	pattern := fmt.Sprintf("%s|%s|%s", logSecretPattern, urlCredentialPattern, privateKeyPattern)
+	matches, err := grepRemote(client, serviceDirs("/var/log"), pattern, "--include='*.log'")
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     err.Error(),
+			Timestamp:   currentTime,
+		}
+	}
+
+	if len(matches) > 0 {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[FAIL]",
+			Details:     fmt.Sprintf("%d log lines leak secrets (showing up to %d)", len(matches), maxMatches),
+			Evidence:    toEvidence(matches),
+			Timestamp:   currentTime,
+		}
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      "[PASS]",
+		Details:     "No plaintext secrets found in readable service logs",
+		Timestamp:   currentTime,
+	}
+}
+
+// CheckLeakage03 checks for stale backup copies of service configuration files
+func CheckLeakage03(client *ssh.Client) checklist.CheckResult {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+	const (
+		description = "Are there no stale backup copies of configuration files?"
+	)
+
+	session, err := client.NewSession()
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     fmt.Sprintf("Failed to create SSH session: %v", err),
+			Timestamp:   currentTime,
+		}
+	}
+	defer session.Close()
+
+	cmd := fmt.Sprintf(`
+		for d in %s; do
+			[ -d "$d" ] || continue
+			find "$d" -type f \( -name '*.bak' -o -name '*.orig' -o -name '*.old' -o -name '*~' \
+				-o -name '*.save' -o -name '*.swp' -o -name '*.dpkg-old' -o -name '*.dpkg-dist' \
+				-o -name '*.rpmsave' -o -name '*.rpmnew' -o -name '*.conf.[0-9]*' \) \
+				-print 2>/dev/null
+		done | head -n %d | sed 's/^/BACKUP:/'
+	`, serviceDirs("/etc"), maxMatches)
+
+	output, err := session.CombinedOutput(cmd)
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     fmt.Sprintf("Failed to execute command: %v", err),
+			Timestamp:   currentTime,
+		}
+	}
+
+	var paths []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if file, ok := strings.CutPrefix(line, "BACKUP:"); ok && file != "" {
+			paths = append(paths, file)
+		}
+	}
+
+	var evidence []checklist.Evidence
+	exposed := 0
+	if len(paths) > 0 {
+		files, err := permission.Stat(client, paths...)
+		if err != nil {
+			return checklist.CheckResult{
+				Description: description,
+				Result:      "[ERROR]",
+				Details:     err.Error(),
+				Timestamp:   currentTime,
+			}
+		}
+		for _, file := range files {
+			if !file.Exists {
+				continue
+			}
+			snippet := fmt.Sprintf("backup copy with mode %s", file.ModeString())
+			if violations := secretFilePolicy.Evaluate(file); len(violations) > 0 {
+				exposed++
+				snippet += fmt.Sprintf(" (%s)", strings.Join(violations, ", "))
+			}
+			evidence = append(evidence, checklist.Evidence{Location: file.Path, Snippet: snippet})
+		}
+	}
+
+	if len(evidence) > 0 {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[FAIL]",
+			Details:     fmt.Sprintf("%d backup copies of configuration files found (%d readable by other users); remove them", len(evidence), exposed),
+			Evidence:    evidence,
+			Timestamp:   currentTime,
+		}
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      "[PASS]",
+		Details:     "No backup copies of configuration files found",
+		Timestamp:   currentTime,
+	}
+}
+
+// CheckLeakage04 checks if debug logging is disabled for every service
+func CheckLeakage04(client *ssh.Client) checklist.CheckResult {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+	const (
+		description = "Is debug logging disabled for every service?"
+	)
+
+	pattern := `^[[:space:]]*debug[[:space:]]*=[[:space:]]*(true|True|TRUE|yes|1)[[:space:]]*$`
+	matches, err := grepRemote(client, serviceDirs("/etc"), pattern, "--include='*.conf'")
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     err.Error(),
+			Timestamp:   currentTime,
+		}
+	}
+
+	if len(matches) > 0 {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[FAIL]",
+			Details:     fmt.Sprintf("debug logging is enabled in %d configuration files; DEBUG logs may contain tokens and passwords", len(matches)),
+			Evidence:    toEvidence(matches),
+			Timestamp:   currentTime,
+		}
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      "[PASS]",
+		Details:     "debug is not enabled in any readable service configuration file",
+		Timestamp:   currentTime,
+	}
+}
@@ -0,0 +1,374 @@
+// checklist/permission/permission.go
+package permission
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"golang.org/x/crypto/ssh"
+)
+
+// Special permission bits
+const (
+	setuid = 0o4000
+	setgid = 0o2000
+	sticky = 0o1000
+)
+
+// Policy describes the ownership and access allowed on a file.
+// Access is evaluated per class: any bit outside the allowed bits of a class is a violation,
+// so 700 satisfies a 640 policy while 607 and 2640 do not.
+type Policy struct {
+	// Owners and Groups list the accepted owner and group names; empty accepts any
+	Owners []string
+	Groups []string
+
+	// Owner, Group and Other are the allowed rwx bits (0-7) of each class
+	Owner uint32
+	Group uint32
+	Other uint32
+
+	// Special holds the allowed setuid, setgid and sticky bits
+	Special uint32
+}
+
+// AnyMode allows every permission bit, for policies that only check ownership
+var AnyMode = Policy{Owner: 0o7, Group: 0o7, Other: 0o7, Special: 0o7000}
+
+// FromMode returns a policy allowing at most the group, other and special bits of an octal mode
+// such as "640". The owner class is not restricted as the owner can change the mode anyway.
+func FromMode(mode string) (Policy, error) {
+	value, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || value > 0o7777 {
+		return Policy{}, fmt.Errorf("invalid mode %q", mode)
+	}
+
+	return Policy{
+		Owner:   0o7,
+		Group:   uint32(value>>3) & 0o7,
+		Other:   uint32(value) & 0o7,
+		Special: uint32(value) & 0o7000,
+	}, nil
+}
+
+// MustFromMode is FromMode for constant modes
+func MustFromMode(mode string) Policy {
+	policy, err := FromMode(mode)
+	if err != nil {
+		panic(err)
+	}
+	return policy
+}
+
+// WithOwnership returns a copy of the policy that also requires one of the given owners and groups
+func (p Policy) WithOwnership(owners, groups []string) Policy {
+	p.Owners = owners
+	p.Groups = groups
+	return p
+}
+
+// String describes the allowed access as an rwx string, e.g. rwxr-----
+func (p Policy) String() string {
+	return rwx(p.Owner) + rwx(p.Group) + rwx(p.Other)
+}
+
+// ParseOwnership splits an expected "user group" such as "root keystone" into accepted owners and groups.
+// Alternatives are separated by "|", e.g. "root|keystone keystone".
+func ParseOwnership(expected string) (owners, groups []string) {
+	fields := strings.Fields(expected)
+	if len(fields) > 0 {
+		owners = strings.Split(fields[0], "|")
+	}
+	if len(fields) > 1 {
+		groups = strings.Split(fields[1], "|")
+	}
+	return owners, groups
+}
+
+// ACLEntry is a named user or group entry of a POSIX ACL
+type ACLEntry struct {
+	Tag   string // user or group
+	Name  string
+	Perms uint32 // effective rwx bits after the ACL mask
+}
+
+// FileInfo is the ownership, mode and ACL of a remote file
+type FileInfo struct {
+	Path   string
+	Exists bool
+	Owner  string
+	Group  string
+	Mode   uint32
+
+	// ACL lists named ACL entries; ACLChecked is false when getfacl is not available
+	ACL        []ACLEntry
+	ACLChecked bool
+}
+
+// ModeString returns the octal mode as printed by stat, e.g. 640 or 2750
+func (f FileInfo) ModeString() string {
+	return strconv.FormatUint(uint64(f.Mode), 8)
+}
+
+// Evaluate returns one explanation per violation of the policy, or nil when the file complies
+func (p Policy) Evaluate(file FileInfo) []string {
+	var violations []string
+
+	if len(p.Owners) > 0 && !contains(p.Owners, file.Owner) {
+		violations = append(violations, fmt.Sprintf("owner is %s (expected %s)", file.Owner, strings.Join(p.Owners, " or ")))
+	}
+	if len(p.Groups) > 0 && !contains(p.Groups, file.Group) {
+		violations = append(violations, fmt.Sprintf("group is %s (expected %s)", file.Group, strings.Join(p.Groups, " or ")))
+	}
+
+	classes := []struct {
+		name    string
+		bits    uint32
+		allowed uint32
+	}{
+		{"owner", file.Mode >> 6 & 0o7, p.Owner},
+		{"group", file.Mode >> 3 & 0o7, p.Group},
+		{"other", file.Mode & 0o7, p.Other},
+	}
+	for _, class := range classes {
+		if extra := class.bits &^ class.allowed; extra != 0 {
+			violations = append(violations, fmt.Sprintf("%s has %s access (%s not allowed, at most %s)", class.name, rwx(class.bits), bitNames(extra), rwx(class.allowed)))
+		}
+	}
+
+	special := []struct {
+		bit  uint32
+		name string
+	}{
+		{setuid, "setuid"},
+		{setgid, "setgid"},
+		{sticky, "sticky"},
+	}
+	for _, s := range special {
+		if file.Mode&s.bit != 0 && p.Special&s.bit == 0 {
+			violations = append(violations, fmt.Sprintf("%s bit is set", s.name))
+		}
+	}
+
+	// Named ACL entries grant access to additional users and groups, so they are held to the group class
+	for _, entry := range file.ACL {
+		if extra := entry.Perms &^ p.Group; extra != 0 {
+			violations = append(violations, fmt.Sprintf("ACL %s:%s grants %s access (%s not allowed, at most %s)", entry.Tag, entry.Name, rwx(entry.Perms), bitNames(extra), rwx(p.Group)))
+		}
+	}
+
+	return violations
+}
+
+// CheckFile evaluates policy on a remote path and returns a check result explaining each violation
+func CheckFile(client *ssh.Client, path string, policy Policy, description string) checklist.CheckResult {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	file, err := StatOne(client, path)
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     err.Error(),
+			Timestamp:   currentTime,
+		}
+	}
+	if !file.Exists {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[NA]",
+			Details:     "File does not exist",
+			Timestamp:   currentTime,
+		}
+	}
+
+	current := fmt.Sprintf("%s:%s %s", file.Owner, file.Group, file.ModeString())
+	violations := policy.Evaluate(file)
+	if len(violations) == 0 {
+		details := fmt.Sprintf("Current ownership and permissions are correct: %s", current)
+		if !file.ACLChecked {
+			details += " (getfacl not available, ACLs not evaluated)"
+		}
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[PASS]",
+			Details:     details,
+			Timestamp:   currentTime,
+		}
+	}
+
+	var details strings.Builder
+	var evidence []checklist.Evidence
+	details.WriteString(fmt.Sprintf("Current ownership and permissions: %s\n", current))
+	for _, violation := range violations {
+		details.WriteString(fmt.Sprintf("- %s\n", violation))
+		evidence = append(evidence, checklist.Evidence{Location: file.Path, Snippet: violation})
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      "[FAIL]",
+		Details:     details.String(),
+		Evidence:    evidence,
+		Timestamp:   currentTime,
+	}
+}
+
+// Stat reads the ownership, mode and ACL of each path on the remote host
+func Stat(client *ssh.Client, paths ...string) ([]FileInfo, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SSH session: %v", err)
+	}
+	defer session.Close()
+
+	var cmd strings.Builder
+	cmd.WriteString(`command -v getfacl >/dev/null 2>&1 && echo "GETFACL"` + "\n")
+	for _, path := range paths {
+		fmt.Fprintf(&cmd, `
+			f=%s
+			if [ -e "$f" ]; then
+				echo "STAT:$(stat -L -c '%%U %%G %%a' "$f") $f"
+				getfacl -cpE "$f" 2>/dev/null | grep -E '^(user|group|mask):' | sed 's/^/ACL:/'
+			else
+				echo "MISSING:$f"
+			fi
+		`, ShellQuote(path))
+	}
+	cmd.WriteString("true\n")
+
+	output, err := session.CombinedOutput(cmd.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute command: %v", err)
+	}
+
+	return parseStat(string(output)), nil
+}
+
+// StatOne reads a single path with Stat
+func StatOne(client *ssh.Client, path string) (FileInfo, error) {
+	files, err := Stat(client, path)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	if len(files) != 1 {
+		return FileInfo{}, fmt.Errorf("failed to stat %s", path)
+	}
+	return files[0], nil
+}
+
+// parseStat turns the output of the Stat script into file information
+func parseStat(output string) []FileInfo {
+	var files []FileInfo
+	aclChecked := false
+	var masks []uint32
+
+	// finish applies the ACL mask to the named entries of the last file
+	finish := func() {
+		if len(files) == 0 || len(masks) == 0 {
+			return
+		}
+		last := &files[len(files)-1]
+		for i := range last.ACL {
+			last.ACL[i].Perms &= masks[0]
+		}
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case line == "GETFACL":
+			aclChecked = true
+		case strings.HasPrefix(line, "STAT:"):
+			finish()
+			masks = nil
+			fields := strings.SplitN(strings.TrimPrefix(line, "STAT:"), " ", 4)
+			if len(fields) != 4 {
+				continue
+			}
+			mode, err := strconv.ParseUint(fields[2], 8, 32)
+			if err != nil {
+				continue
+			}
+			files = append(files, FileInfo{
+				Path:       fields[3],
+				Exists:     true,
+				Owner:      fields[0],
+				Group:      fields[1],
+				Mode:       uint32(mode),
+				ACLChecked: aclChecked,
+			})
+		case strings.HasPrefix(line, "MISSING:"):
+			finish()
+			masks = nil
+			files = append(files, FileInfo{Path: strings.TrimPrefix(line, "MISSING:")})
+		case strings.HasPrefix(line, "ACL:") && len(files) > 0:
+			parts := strings.Split(strings.TrimPrefix(line, "ACL:"), ":")
+			if len(parts) != 3 {
+				continue
+			}
+			perms := parseRWX(parts[2])
+			switch {
+			case parts[0] == "mask":
+				masks = append(masks, perms)
+			case parts[1] != "":
+				// Entries without a name mirror the owner and group classes of the mode
+				last := &files[len(files)-1]
+				last.ACL = append(last.ACL, ACLEntry{Tag: parts[0], Name: parts[1], Perms: perms})
+			}
+		}
+	}
+	finish()
+
+	return files
+}
+
+// rwx formats rwx bits as in ls, e.g. r-x
+func rwx(bits uint32) string {
+	out := []byte("---")
+	for i, c := range "rwx" {
+		if bits&(0o4>>i) != 0 {
+			out[i] = byte(c)
+		}
+	}
+	return string(out)
+}
+
+// bitNames names the rwx bits, e.g. "write" or "read, execute"
+func bitNames(bits uint32) string {
+	var names []string
+	for i, name := range []string{"read", "write", "execute"} {
+		if bits&(0o4>>i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// parseRWX parses an rwx string such as r-x into bits
+func parseRWX(s string) uint32 {
+	var bits uint32
+	for i, c := range s {
+		if i < 3 && c != '-' {
+			bits |= 0o4 >> i
+		}
+	}
+	return bits
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ShellQuote quotes a path for use in a POSIX shell
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,222 @@
+package permission
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFromMode(t *testing.T) {
+	tests := []struct {
+		mode    string
+		want    Policy
+		wantErr bool
+	}{
+		{mode: "640", want: Policy{Owner: 0o7, Group: 0o4, Other: 0o0}},
+		{mode: "750", want: Policy{Owner: 0o7, Group: 0o5, Other: 0o0}},
+		{mode: "0600", want: Policy{Owner: 0o7, Group: 0o0, Other: 0o0}},
+		{mode: "1777", want: Policy{Owner: 0o7, Group: 0o7, Other: 0o7, Special: 0o1000}},
+		{mode: "2750", want: Policy{Owner: 0o7, Group: 0o5, Other: 0o0, Special: 0o2000}},
+		{mode: "", wantErr: true},
+		{mode: "648", wantErr: true},
+		{mode: "rw-r-----", wantErr: true},
+		{mode: "17777", wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := FromMode(test.mode)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("FromMode(%q) = %+v, want an error", test.mode, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("FromMode(%q) returned %v", test.mode, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("FromMode(%q) = %+v, want %+v", test.mode, got, test.want)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	keystone := MustFromMode("640").WithOwnership([]string{"root", "keystone"}, []string{"keystone"})
+
+	tests := []struct {
+		name   string
+		policy Policy
+		file   FileInfo
+		want   []string // substrings of the violations, in order
+	}{
+		{
+			name:   "exact mode",
+			policy: MustFromMode("640"),
+			file:   FileInfo{Mode: 0o640},
+		},
+		{
+			name:   "stricter mode",
+			policy: MustFromMode("640"),
+			file:   FileInfo{Mode: 0o600},
+		},
+		{
+			// The owner class is not restricted, so 700 is stricter than 640 for group and other
+			name:   "700 satisfies 640",
+			policy: MustFromMode("640"),
+			file:   FileInfo{Mode: 0o700},
+		},
+		{
+			// 607 is numerically below 640 but grants other users full access
+			name:   "607 violates 640",
+			policy: MustFromMode("640"),
+			file:   FileInfo{Mode: 0o607},
+			want:   []string{"other has rwx access (read, write, execute not allowed, at most ---)"},
+		},
+		{
+			name:   "group write",
+			policy: MustFromMode("640"),
+			file:   FileInfo{Mode: 0o660},
+			want:   []string{"group has rw- access (write not allowed, at most r--)"},
+		},
+		{
+			name:   "setuid",
+			policy: MustFromMode("755"),
+			file:   FileInfo{Mode: 0o4755},
+			want:   []string{"setuid bit is set"},
+		},
+		{
+			name:   "setgid on a 640 file",
+			policy: MustFromMode("640"),
+			file:   FileInfo{Mode: 0o2640},
+			want:   []string{"setgid bit is set"},
+		},
+		{
+			name:   "sticky bit allowed",
+			policy: MustFromMode("1777"),
+			file:   FileInfo{Mode: 0o1777},
+		},
+		{
+			name:   "sticky bit not allowed",
+			policy: MustFromMode("777"),
+			file:   FileInfo{Mode: 0o1777},
+			want:   []string{"sticky bit is set"},
+		},
+		{
+			name:   "ACL within the group class",
+			policy: MustFromMode("640"),
+			file:   FileInfo{Mode: 0o640, ACL: []ACLEntry{{Tag: "user", Name: "nova", Perms: 0o4}}},
+		},
+		{
+			name:   "ACL beyond the group class",
+			policy: MustFromMode("640"),
+			file: FileInfo{Mode: 0o640, ACL: []ACLEntry{
+				{Tag: "user", Name: "backup", Perms: 0o6},
+				{Tag: "group", Name: "admins", Perms: 0o4},
+			}},
+			want: []string{"ACL user:backup grants rw- access (write not allowed, at most r--)"},
+		},
+		{
+			name:   "accepted ownership",
+			policy: keystone,
+			file:   FileInfo{Owner: "root", Group: "keystone", Mode: 0o640},
+		},
+		{
+			name:   "wrong ownership",
+			policy: keystone,
+			file:   FileInfo{Owner: "nova", Group: "nova", Mode: 0o644},
+			want: []string{
+				"owner is nova (expected root or keystone)",
+				"group is nova (expected keystone)",
+				"other has r-- access",
+			},
+		},
+		{
+			name:   "any mode",
+			policy: AnyMode,
+			file:   FileInfo{Mode: 0o7777, ACL: []ACLEntry{{Tag: "user", Name: "x", Perms: 0o7}}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.policy.Evaluate(test.file)
+			if len(got) != len(test.want) {
+				t.Fatalf("Evaluate = %q, want %d violations %q", got, len(test.want), test.want)
+			}
+			for i := range got {
+				if !strings.Contains(got[i], test.want[i]) {
+					t.Errorf("violation %d = %q, want it to contain %q", i, got[i], test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseStat(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []FileInfo
+	}{
+		{
+			name:   "without getfacl",
+			output: "STAT:keystone keystone 640 /etc/keystone/keystone.conf\nMISSING:/etc/keystone/policy.json\n",
+			want: []FileInfo{
+				{Path: "/etc/keystone/keystone.conf", Exists: true, Owner: "keystone", Group: "keystone", Mode: 0o640},
+				{Path: "/etc/keystone/policy.json"},
+			},
+		},
+		{
+			name:   "special bits and spaces in the path",
+			output: "STAT:root root 4755 /usr/bin/sudo\nSTAT:root root 1777 /tmp/dir with spaces\n",
+			want: []FileInfo{
+				{Path: "/usr/bin/sudo", Exists: true, Owner: "root", Group: "root", Mode: 0o4755},
+				{Path: "/tmp/dir with spaces", Exists: true, Owner: "root", Group: "root", Mode: 0o1777},
+			},
+		},
+		{
+			// getfacl prints the mask after the named entries; it limits their effective access
+			name: "ACL entries limited by the mask",
+			output: strings.Join([]string{
+				"GETFACL",
+				"STAT:root nova 640 /etc/nova/nova.conf",
+				"ACL:user::rw-",
+				"ACL:user:backup:rwx",
+				"ACL:group::r--",
+				"ACL:group:admins:rw-",
+				"ACL:mask::r--",
+				"STAT:root glance 640 /etc/glance/glance-api.conf",
+				"ACL:user::rw-",
+				"ACL:user:glance:rw-",
+				"ACL:group::r--",
+				"ACL:mask::rw-",
+				"MISSING:/etc/cinder/cinder.conf",
+			}, "\n"),
+			want: []FileInfo{
+				{
+					Path: "/etc/nova/nova.conf", Exists: true, Owner: "root", Group: "nova", Mode: 0o640, ACLChecked: true,
+					ACL: []ACLEntry{{Tag: "user", Name: "backup", Perms: 0o4}, {Tag: "group", Name: "admins", Perms: 0o4}},
+				},
+				{
+					Path: "/etc/glance/glance-api.conf", Exists: true, Owner: "root", Group: "glance", Mode: 0o640, ACLChecked: true,
+					ACL: []ACLEntry{{Tag: "user", Name: "glance", Perms: 0o6}},
+				},
+				{Path: "/etc/cinder/cinder.conf"},
+			},
+		},
+		{
+			name:   "malformed lines are skipped",
+			output: "STAT:root root\nSTAT:root root 9x9 /etc/x\nACL:user:orphan:rwx\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := parseStat(test.output)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("parseStat =\n%+v\nwant\n%+v", got, test.want)
+			}
+		})
+	}
+}
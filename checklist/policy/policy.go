@@ -0,0 +1,462 @@
+// checklist/policy/policy.go
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"golang.org/x/crypto/ssh"
+	"gopkg.in/yaml.v3"
+)
+
+// serviceConfigs maps each OpenStack service to the config file holding its [oslo_policy] section
+var serviceConfigs = map[string]string{
+	"barbican":  "/etc/barbican/barbican.conf",
+	"cinder":    "/etc/cinder/cinder.conf",
+	"designate": "/etc/designate/designate.conf",
+	"glance":    "/etc/glance/glance-api.conf",
+	"heat":      "/etc/heat/heat.conf",
+	"ironic":    "/etc/ironic/ironic.conf",
+	"keystone":  "/etc/keystone/keystone.conf",
+	"manila":    "/etc/manila/manila.conf",
+	"neutron":   "/etc/neutron/neutron.conf",
+	"nova":      "/etc/nova/nova.conf",
+	"octavia":   "/etc/octavia/octavia.conf",
+	"placement": "/etc/placement/placement.conf",
+}
+
+// adminAPIPatterns match policy rule names that protect administrative APIs
+var adminAPIPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`admin`),
+	regexp.MustCompile(`^identity:(create|update|delete)_(user|project|domain|role|group|credential|service|endpoint|region|identity_provider|mapping|protocol|policy|trust)`),
+	regexp.MustCompile(`^identity:(create|delete)_(grant|system_grant|domain_role|implied_role)`),
+	regexp.MustCompile(`^identity:list_(users|projects|domains|credentials)$`),
+	regexp.MustCompile(`^os_compute_api:os-(hypervisors|migrate|migrate-server|evacuate|services|aggregates|hosts|quota-sets:update|flavor-manage)`),
+	regexp.MustCompile(`^(create|update|delete)_(network|subnet|port):.*(provider|segment|binding|shared|router:external)`),
+	regexp.MustCompile(`^volume_extension:(quotas:update|services|types_manage|volume_admin_actions)`),
+	regexp.MustCompile(`^(publicize_image|communitize_image|set_image_location|delete_image_location)$`),
+}
+
+// memberRolePattern matches checks granting access to non-admin roles
+var memberRolePattern = regexp.MustCompile(`(^|\s|\()role:(_member_|member|reader)(\s|\)|$)`)
+
+// legacyAliases are pre secure-RBAC rule aliases; overrides referencing them pin deprecated defaults
+var legacyAliases = []string{
+	"rule:admin_or_owner", "rule:admin_api", "rule:admin_required", "rule:owner",
+	"rule:admin_or_user", "rule:context_is_admin", "is_admin:True", "is_admin:true",
+}
+
+// policyFile is a policy file loaded from the remote host
+type policyFile struct {
+	Service string
+	Path    string
+	Content string
+	Rules   map[string]string
+}
+
+// loadPolicyFiles reads the policy file of every installed service
+func loadPolicyFiles(client *ssh.Client) ([]policyFile, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SSH session: %v", err)
+	}
+	defer session.Close()
+
+	// For each service print the configured policy_file, falling back to policy.yaml then policy.json
+	var script strings.Builder
+	for _, service := range sortedServices() {
+		script.WriteString(fmt.Sprintf(`
+		dir=/etc/%s
+		if [ -d "$dir" ]; then
+			f=$(awk '/^[ \t]*\[/ { s = $0 } s ~ /oslo_policy/ && /^[ \t]*policy_file[ \t]*=/ { sub(/^[^=]*=[ \t]*/, ""); print }' "%s" 2>/dev/null | tail -n 1)
+			case "$f" in
+				"") for c in policy.yaml policy.json; do [ -f "$dir/$c" ] && f="$dir/$c" && break; done ;;
+				/*) ;;
+				*) f="$dir/$f" ;;
+			esac
+			if [ -n "$f" ] && [ -r "$f" ]; then
+				echo "POLICY_FILE:%s:$f"
+				cat "$f"
+				echo
+				echo "POLICY_END"
+			fi
+		fi
+		`, service, serviceConfigs[service], service))
+	}
+
+	output, err := session.CombinedOutput(script.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute command: %v", err)
+	}
+
+	var files []policyFile
+	var current *policyFile
+	var content strings.Builder
+	for _, line := range strings.Split(string(output), "\n") {
+		switch {
+		case strings.HasPrefix(line, "POLICY_FILE:"):
+			service, path, _ := strings.Cut(strings.TrimPrefix(line, "POLICY_FILE:"), ":")
+			current = &policyFile{Service: service, Path: path}
+			content.Reset()
+		case line == "POLICY_END" && current != nil:
+			current.Content = content.String()
+			current.Rules = parseRules(current.Content)
+			files = append(files, *current)
+			current = nil
+		case current != nil:
+			content.WriteString(line)
+			content.WriteString("\n")
+		}
+	}
+
+	return files, nil
+}
+
+// parseRules parses a policy.json or policy.yaml document into rule name -> check string
+func parseRules(content string) map[string]string {
+	rules := map[string]string{}
+
+	// JSON first: tab indented policy.json files are not valid YAML
+	if err := json.Unmarshal([]byte(content), &rules); err == nil {
+		return rules
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal([]byte(content), &raw); err != nil {
+		return nil
+	}
+	for name, value := range raw {
+		if s, ok := value.(string); ok {
+			rules[name] = s
+		}
+	}
+	return rules
+}
+
+// lineOf returns the 1-based line number where rule is defined in content, or 0
+func lineOf(content, rule string) int {
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, fmt.Sprintf("%q", rule)) || strings.HasPrefix(trimmed, rule+":") ||
+			strings.HasPrefix(trimmed, fmt.Sprintf("'%s'", rule)) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// ruleEvidence builds the evidence entry for a single rule of a policy file
+func ruleEvidence(file policyFile, rule, note string) checklist.Evidence {
+	return checklist.Evidence{
+		Location: file.Path,
+		Line:     lineOf(file.Content, rule),
+		Snippet:  fmt.Sprintf("%q: %q (%s)", rule, file.Rules[rule], note),
+	}
+}
+
+// isAdminAPI reports whether a rule name protects an administrative API
+func isAdminAPI(rule string) bool {
+	for _, pattern := range adminAPIPatterns {
+		if pattern.MatchString(rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAlias reports whether a rule is a reusable alias (e.g. admin_required) rather than an API rule
+func isAlias(rule string) bool {
+	return !strings.Contains(rule, ":")
+}
+
+// permissiveReason explains why a check string is too permissive for rule, or returns ""
+func permissiveReason(rule, check string) string {
+	check = strings.TrimSpace(check)
+	sensitive := isAdminAPI(rule) || (isAlias(rule) && strings.Contains(rule, "admin"))
+
+	switch {
+	case check == "@" && (sensitive || isAlias(rule)):
+		return "always allowed"
+	case check == "" && sensitive:
+		return "empty rule allows any authenticated user"
+	case sensitive && memberRolePattern.MatchString(check) &&
+		!strings.Contains(check, "role:admin"):
+		return "member/reader role granted on an admin API"
+	}
+	return ""
+}
+
+// sortedServices returns the service names in a stable order
+func sortedServices() []string {
+	services := make([]string, 0, len(serviceConfigs))
+	for service := range serviceConfigs {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	return services
+}
+
+// sortedRules returns the rule names of a policy file in a stable order
+func sortedRules(file policyFile) []string {
+	rules := make([]string, 0, len(file.Rules))
+	for rule := range file.Rules {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	return rules
+}
+
+// noPolicyFilesResult is returned when no service has a policy file on the host
+func noPolicyFilesResult(description, currentTime string) checklist.CheckResult {
+	return checklist.CheckResult{
+		Description: description,
+		Result:      "[NA]",
+		Details:     "No readable policy files found; services use the defaults defined in code",
+		Timestamp:   currentTime,
+	}
+}
+
+// CheckPolicy01 checks policy files for overly permissive rules
+func CheckPolicy01(client *ssh.Client) checklist.CheckResult {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+	const (
+		description = "Are policy files free of overly permissive rules?"
+	)
+
+	files, err := loadPolicyFiles(client)
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     err.Error(),
+			Timestamp:   currentTime,
+		}
+	}
+	if len(files) == 0 {
+		return noPolicyFilesResult(description, currentTime)
+	}
+
+	var details strings.Builder
+	var evidence []checklist.Evidence
+	for _, file := range files {
+		if file.Rules == nil {
+			details.WriteString(fmt.Sprintf("- %s: unable to parse policy file\n", file.Path))
+			continue
+		}
+
+		risky := 0
+		for _, rule := range sortedRules(file) {
+			if reason := permissiveReason(rule, file.Rules[rule]); reason != "" {
+				risky++
+				evidence = append(evidence, ruleEvidence(file, rule, reason))
+			}
+		}
+		details.WriteString(fmt.Sprintf("- %s: %d rules, %d overly permissive\n", file.Path, len(file.Rules), risky))
+	}
+
+	result := "[PASS]"
+	if len(evidence) > 0 {
+		result = "[FAIL]"
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      result,
+		Details:     details.String(),
+		Evidence:    evidence,
+		Timestamp:   currentTime,
+	}
+}
+
+// CheckPolicy02 checks policy files for overrides that pin deprecated defaults
+func CheckPolicy02(client *ssh.Client) checklist.CheckResult {
+	return CheckPolicy02WithParams(client, nil)
+}
+
+// CheckPolicy02WithParams reports JSON policy files only when the "json_deprecated" parameter is true
+func CheckPolicy02WithParams(client *ssh.Client, params checklist.Params) checklist.CheckResult {
+	jsonDeprecated := params.Bool("json_deprecated", true)
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+	const (
+		description = "Are policy files free of deprecated rule overrides?"
+	)
+
+	files, err := loadPolicyFiles(client)
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     err.Error(),
+			Timestamp:   currentTime,
+		}
+	}
+	if len(files) == 0 {
+		return noPolicyFilesResult(description, currentTime)
+	}
+
+	var details strings.Builder
+	var evidence []checklist.Evidence
+	for _, file := range files {
+		if jsonDeprecated && strings.HasSuffix(file.Path, ".json") {
+			evidence = append(evidence, checklist.Evidence{
+				Location: file.Path,
+				Snippet:  "JSON formatted policy files are deprecated since Wallaby; migrate with oslopolicy-convert-json-to-yaml",
+			})
+		}
+
+		deprecated := 0
+		for _, rule := range sortedRules(file) {
+			for _, alias := range legacyAliases {
+				if strings.Contains(file.Rules[rule], alias) && !isAlias(rule) {
+					deprecated++
+					evidence = append(evidence, ruleEvidence(file, rule, fmt.Sprintf("overrides the default with legacy %s", alias)))
+					break
+				}
+			}
+		}
+		details.WriteString(fmt.Sprintf("- %s: %d rules, %d deprecated overrides\n", file.Path, len(file.Rules), deprecated))
+	}
+
+	result := "[PASS]"
+	if len(evidence) > 0 {
+		result = "[FAIL]"
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      result,
+		Details:     details.String(),
+		Evidence:    evidence,
+		Timestamp:   currentTime,
+	}
+}
+
+// CheckPolicy03 checks if enforce_scope and enforce_new_defaults are enabled for every service
+func CheckPolicy03(client *ssh.Client) checklist.CheckResult {
+	return CheckPolicy03WithParams(client, nil)
+}
+
+// CheckPolicy03WithParams treats unset options as enabled when the "enabled_by_default" parameter is true,
+// as oslo.policy does from 2024.1
+func CheckPolicy03WithParams(client *ssh.Client, params checklist.Params) checklist.CheckResult {
+	enabledByDefault := params.Bool("enabled_by_default", false)
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+	const (
+		description = "Are enforce_scope and enforce_new_defaults enabled in [oslo_policy]?"
+	)
+
+	session, err := client.NewSession()
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     fmt.Sprintf("Failed to create SSH session: %v", err),
+			Timestamp:   currentTime,
+		}
+	}
+	defer session.Close()
+
+	// Print "CONF:<service>:<file>" then "OPTION:<service>:<line>:<option>" for each [oslo_policy] enforce option
+	var script strings.Builder
+	for _, service := range sortedServices() {
+		script.WriteString(fmt.Sprintf(`
+		f=%s
+		if [ -r "$f" ]; then
+			echo "CONF:%s:$f"
+			awk '/^[ \t]*\[/ { s = $0 } s ~ /oslo_policy/ && /^[ \t]*enforce_(scope|new_defaults)[ \t]*=/ { print "OPTION:%s:" NR ":" $0 }' "$f"
+		fi
+		`, serviceConfigs[service], service, service))
+	}
+
+	output, err := session.CombinedOutput(script.String())
+	if err != nil {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     fmt.Sprintf("Failed to execute command: %v", err),
+			Timestamp:   currentTime,
+		}
+	}
+
+	type options struct {
+		file    string
+		values  map[string]string
+		lineNos map[string]int
+	}
+	services := map[string]*options{}
+	var order []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		switch {
+		case strings.HasPrefix(line, "CONF:"):
+			service, file, _ := strings.Cut(strings.TrimPrefix(line, "CONF:"), ":")
+			services[service] = &options{file: file, values: map[string]string{}, lineNos: map[string]int{}}
+			order = append(order, service)
+		case strings.HasPrefix(line, "OPTION:"):
+			parts := strings.SplitN(strings.TrimPrefix(line, "OPTION:"), ":", 3)
+			if len(parts) != 3 || services[parts[0]] == nil {
+				continue
+			}
+			key, value, _ := strings.Cut(parts[2], "=")
+			key = strings.TrimSpace(key)
+			services[parts[0]].values[key] = strings.TrimSpace(value)
+			services[parts[0]].lineNos[key], _ = strconv.Atoi(parts[1])
+		}
+	}
+
+	if len(order) == 0 {
+		return checklist.CheckResult{
+			Description: description,
+			Result:      "[NA]",
+			Details:     "No readable service configuration files found",
+			Timestamp:   currentTime,
+		}
+	}
+
+	var details strings.Builder
+	var evidence []checklist.Evidence
+	for _, service := range order {
+		opts := services[service]
+		var disabled []string
+		for _, key := range []string{"enforce_scope", "enforce_new_defaults"} {
+			value, set := opts.values[key]
+			if strings.EqualFold(value, "true") || (!set && enabledByDefault) {
+				continue
+			}
+			disabled = append(disabled, key)
+			snippet := fmt.Sprintf("%s is not set", key)
+			if set {
+				snippet = fmt.Sprintf("%s = %s", key, value)
+			}
+			evidence = append(evidence, checklist.Evidence{
+				Location: opts.file,
+				Line:     opts.lineNos[key],
+				Snippet:  snippet,
+			})
+		}
+
+		if len(disabled) > 0 {
+			details.WriteString(fmt.Sprintf("- %s: %s not enabled\n", service, strings.Join(disabled, ", ")))
+		} else {
+			details.WriteString(fmt.Sprintf("- %s: enforce_scope and enforce_new_defaults are enabled\n", service))
+		}
+	}
+
+	result := "[PASS]"
+	if len(evidence) > 0 {
+		result = "[FAIL]"
+	}
+
+	return checklist.CheckResult{
+		Description: description,
+		Result:      result,
+		Details:     details.String(),
+		Evidence:    evidence,
+		Timestamp:   currentTime,
+	}
+}
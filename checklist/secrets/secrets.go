@@ -2,22 +2,28 @@ package secrets
 
 import (
 	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/checklist/permission"
 	"github.com/gunh0/openstack-security-hub/util"
 	"golang.org/x/crypto/ssh"
 )
 
+// barbicanOwnership is the expected ownership of the Barbican configuration files
+var barbicanOwnership = permission.AnyMode.WithOwnership([]string{"root"}, []string{"barbican"})
+
 func CheckKeyManager0101(client *ssh.Client) checklist.CheckResult {
-	return util.ExecuteScriptAndGetResult(
+	return permission.CheckFile(
 		client,
-		"checklist/secrets/key-manager-01-01.sh",
+		"/etc/barbican/barbican.conf",
+		barbicanOwnership,
 		"Is the ownership of config files set to root/barbican? (/etc/barbican/barbican.conf)",
 	)
 }
 
 func CheckKeyManager0102(client *ssh.Client) checklist.CheckResult {
-	return util.ExecuteScriptAndGetResult(
+	return permission.CheckFile(
 		client,
-		"checklist/secrets/key-manager-01-02.sh",
+		"/etc/barbican/barbican-api-paste.ini",
+		barbicanOwnership,
 		"Is the ownership of config files set to root/barbican? (/etc/barbican/barbican-api-paste.ini)",
 	)
 }
@@ -1,10 +1,49 @@
 // checklist/type.go
 package checklist
 
+import "strconv"
+
 // CheckResult represents a check result
 type CheckResult struct {
-	Description string `json:"description"`
-	Result      string `json:"result"`
-	Details     string `json:"details"`
-	Timestamp   string `json:"timestamp"`
+	Description string     `json:"description"`
+	Result      string     `json:"result"`
+	Details     string     `json:"details"`
+	Evidence    []Evidence `json:"evidence,omitempty"`
+	Timestamp   string     `json:"timestamp"`
+}
+
+// Evidence points at the remote location that led to a check result
+type Evidence struct {
+	Location string `json:"location"`
+	Line     int    `json:"line,omitempty"`
+	Snippet  string `json:"snippet,omitempty"`
+}
+
+// Params holds the expected values a check compares against, keyed by name
+type Params map[string]string
+
+// Get returns the value of name, or fallback when it is not set
+func (p Params) Get(name, fallback string) string {
+	if value, ok := p[name]; ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+// Int returns the integer value of name, or fallback when it is not set or invalid
+func (p Params) Int(name string, fallback int) int {
+	value, err := strconv.Atoi(p.Get(name, ""))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// Bool returns the boolean value of name, or fallback when it is not set or invalid
+func (p Params) Bool(name string, fallback bool) bool {
+	value, err := strconv.ParseBool(p.Get(name, ""))
+	if err != nil {
+		return fallback
+	}
+	return value
 }
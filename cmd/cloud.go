@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/checklist/cloud"
+	"github.com/gunh0/openstack-security-hub/openstack"
+	"github.com/gunh0/openstack-security-hub/util"
+	"github.com/spf13/cobra"
+)
+
+func initCloudCommands() {
+	cloudCmd := &cobra.Command{
+		Use:   "cloud",
+		Short: "Run all cloud checks",
+		RunE:  runAllCloudChecks,
+	}
+
+	cloud01Cmd := &cobra.Command{
+		Use:   "cloud-01",
+		Short: "Is the admin role limited to a few projects per user?",
+		RunE:  runCloud01Check,
+	}
+
+	cloud02Cmd := &cobra.Command{
+		Use:   "cloud-02",
+		Short: "Is multi-factor authentication enforced for administrators?",
+		RunE:  runCloud02Check,
+	}
+
+	cloud03Cmd := &cobra.Command{
+		Use:   "cloud-03",
+		Short: "Do security groups block SSH/RDP from 0.0.0.0/0?",
+		RunE:  runCloud03Check,
+	}
+
+	cloud04Cmd := &cobra.Command{
+		Use:   "cloud-04",
+		Short: "Are public images published by the cloud administrators only?",
+		RunE:  runCloud04Check,
+	}
+
+	cloud05Cmd := &cobra.Command{
+		Use:   "cloud-05",
+		Short: "Are all volumes encrypted?",
+		RunE:  runCloud05Check,
+	}
+
+	cloud06Cmd := &cobra.Command{
+		Use:   "cloud-06",
+		Short: "Are instances with floating IPs protected by security groups?",
+		RunE:  runCloud06Check,
+	}
+
+	RootCmd.AddCommand(cloudCmd)
+	RootCmd.AddCommand(cloud01Cmd)
+	RootCmd.AddCommand(cloud02Cmd)
+	RootCmd.AddCommand(cloud03Cmd)
+	RootCmd.AddCommand(cloud04Cmd)
+	RootCmd.AddCommand(cloud05Cmd)
+	RootCmd.AddCommand(cloud06Cmd)
+}
+
+func runCloud01Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetOpenStackClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCloudCheck(cmd.Name(), client, cloud.CheckCloud01)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runCloud02Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetOpenStackClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCloudCheck(cmd.Name(), client, cloud.CheckCloud02)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runCloud03Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetOpenStackClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCloudCheck(cmd.Name(), client, cloud.CheckCloud03)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runCloud04Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetOpenStackClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCloudCheck(cmd.Name(), client, cloud.CheckCloud04)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runCloud05Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetOpenStackClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCloudCheck(cmd.Name(), client, cloud.CheckCloud05)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runCloud06Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetOpenStackClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCloudCheck(cmd.Name(), client, cloud.CheckCloud06)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runAllCloudChecks(cmd *cobra.Command, args []string) error {
+	client, err := util.GetOpenStackClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	checks := []struct {
+		name string
+		fn   func(*openstack.Client) checklist.CheckResult
+	}{
+		{"Cloud-01", cloud.CheckCloud01},
+		{"Cloud-02", cloud.CheckCloud02},
+		{"Cloud-03", cloud.CheckCloud03},
+		{"Cloud-04", cloud.CheckCloud04},
+		{"Cloud-05", cloud.CheckCloud05},
+		{"Cloud-06", cloud.CheckCloud06},
+	}
+
+	for _, check := range checks {
+		result := runCloudCheck(checkID(check.name), client, check.fn)
+		printResult(checkID(check.name), result)
+	}
+	return nil
+}
@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/gunh0/openstack-security-hub/checklist/dashboard"
 	"github.com/gunh0/openstack-security-hub/util"
 	"github.com/spf13/cobra"
@@ -12,25 +10,25 @@ func initDashboardCommands() {
 	dashboard01Cmd := &cobra.Command{
 		Use:   "dashboard-01",
 		Short: "Is user/group of config files set to root/horizon?",
-		Run:   runDashboard01Checks,
+		RunE:  runDashboard01Checks,
 	}
 
 	dashboard04Cmd := &cobra.Command{
 		Use:   "dashboard-04",
 		Short: "Is CSRF_COOKIE_SECURE parameter set to True?",
-		Run:   runDashboard04Checks,
+		RunE:  runDashboard04Checks,
 	}
 
 	dashboard05Cmd := &cobra.Command{
 		Use:   "dashboard-05",
 		Short: "Is SESSION_COOKIE_SECURE parameter set to True?",
-		Run:   runDashboard05Checks,
+		RunE:  runDashboard05Checks,
 	}
 
 	dashboard06Cmd := &cobra.Command{
 		Use:   "dashboard-06",
 		Short: "Is SESSION_COOKIE_HTTPONLY parameter set to True?",
-		Run:   runDashboard06Checks,
+		RunE:  runDashboard06Checks,
 	}
 
 	RootCmd.AddCommand(dashboard01Cmd)
@@ -39,50 +37,50 @@ func initDashboardCommands() {
 	RootCmd.AddCommand(dashboard06Cmd)
 }
 
-func runDashboard01Checks(cmd *cobra.Command, args []string) {
+func runDashboard01Checks(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := dashboard.CheckDashboard01(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, dashboard.CheckDashboard01)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runDashboard04Checks(cmd *cobra.Command, args []string) {
+func runDashboard04Checks(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := dashboard.CheckDashboard04(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, dashboard.CheckDashboard04)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runDashboard05Checks(cmd *cobra.Command, args []string) {
+func runDashboard05Checks(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := dashboard.CheckDashboard05(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, dashboard.CheckDashboard05)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runDashboard06Checks(cmd *cobra.Command, args []string) {
+func runDashboard06Checks(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := dashboard.CheckDashboard06(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, dashboard.CheckDashboard06)
+	printResult(cmd.Name(), result)
+	return nil
 }
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/checklist/database"
+	"github.com/gunh0/openstack-security-hub/util"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+func initDatabaseCommands() {
+	databaseCmd := &cobra.Command{
+		Use:   "database",
+		Short: "Run all database checks",
+		RunE:  runAllDatabaseChecks,
+	}
+
+	database01Cmd := &cobra.Command{
+		Use:   "database-01",
+		Short: "Is TLS used for every service's database connection?",
+		RunE:  runDatabase01Check,
+	}
+
+	database02Cmd := &cobra.Command{
+		Use:   "database-02",
+		Short: "Do services avoid connecting to the database as root?",
+		RunE:  runDatabase02Check,
+	}
+
+	database03Cmd := &cobra.Command{
+		Use:   "database-03",
+		Short: "Does each service use its own database credentials?",
+		RunE:  runDatabase03Check,
+	}
+
+	database04Cmd := &cobra.Command{
+		Use:   "database-04",
+		Short: "Are MySQL option files (my.cnf) protected from other users?",
+		RunE:  runDatabase04Check,
+	}
+
+	database05Cmd := &cobra.Command{
+		Use:   "database-05",
+		Short: "Is the database server bind-address restricted?",
+		RunE:  runDatabase05Check,
+	}
+
+	database06Cmd := &cobra.Command{
+		Use:   "database-06",
+		Short: "Is require_secure_transport enabled on the database server?",
+		RunE:  runDatabase06Check,
+	}
+
+	database07Cmd := &cobra.Command{
+		Use:   "database-07",
+		Short: "Are anonymous database users removed?",
+		RunE:  runDatabase07Check,
+	}
+
+	RootCmd.AddCommand(databaseCmd)
+	RootCmd.AddCommand(database01Cmd)
+	RootCmd.AddCommand(database02Cmd)
+	RootCmd.AddCommand(database03Cmd)
+	RootCmd.AddCommand(database04Cmd)
+	RootCmd.AddCommand(database05Cmd)
+	RootCmd.AddCommand(database06Cmd)
+	RootCmd.AddCommand(database07Cmd)
+}
+
+func runDatabase01Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, database.CheckDatabase01)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runDatabase02Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, database.CheckDatabase02)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runDatabase03Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, database.CheckDatabase03)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runDatabase04Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, database.CheckDatabase04)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runDatabase05Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, database.CheckDatabase05)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runDatabase06Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, database.CheckDatabase06)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runDatabase07Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, database.CheckDatabase07)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runAllDatabaseChecks(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	checks := []struct {
+		name string
+		fn   func(*ssh.Client) checklist.CheckResult
+	}{
+		{"Database-01", database.CheckDatabase01},
+		{"Database-02", database.CheckDatabase02},
+		{"Database-03", database.CheckDatabase03},
+		{"Database-04", database.CheckDatabase04},
+		{"Database-05", database.CheckDatabase05},
+		{"Database-06", database.CheckDatabase06},
+		{"Database-07", database.CheckDatabase07},
+	}
+
+	for _, check := range checks {
+		result := runCheck(checkID(check.name), client, check.fn)
+		printResult(checkID(check.name), result)
+	}
+	return nil
+}
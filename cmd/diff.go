@@ -0,0 +1,99 @@
+// cmd/diff.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gunh0/openstack-security-hub/output"
+	"github.com/gunh0/openstack-security-hub/scan"
+	"github.com/spf13/cobra"
+)
+
+func initDiffCommands() {
+	diffCmd := &cobra.Command{
+		Use:   "diff <scan-a> <scan-b>",
+		Short: "Show what changed between two scans, given as history IDs or files written by scan -o json",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runDiff,
+	}
+	RootCmd.AddCommand(diffCmd)
+}
+
+// loadScan returns the reports of a scan result file, or of the scan with that ID in the history
+func loadScan(ref string) ([]scan.Report, error) {
+	if _, err := os.Stat(ref); err == nil {
+		return loadReports(ref)
+	}
+
+	h, err := openHistory()
+	if err != nil {
+		return nil, err
+	}
+	defer h.Close()
+
+	s, err := h.Get(ref)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", ref, err)
+	}
+	return s.Reports, nil
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	before, err := loadScan(args[0])
+	if err != nil {
+		return err
+	}
+	after, err := loadScan(args[1])
+	if err != nil {
+		return err
+	}
+	diff := scan.Compare(before, after)
+
+	switch outputFormat {
+	case "text":
+		printDiff(args[0], args[1], diff)
+	case "json", "yaml":
+		return output.Encode(os.Stdout, outputFormat, diff)
+	default:
+		return fmt.Errorf("diff supports the text, json and yaml output formats")
+	}
+	return nil
+}
+
+// printDiff prints the changes of a diff per target
+func printDiff(a, b string, diff scan.Diff) {
+	fmt.Printf("Changes from %s to %s: %s\n", a, b, diff.Summary)
+
+	for _, target := range diff.Targets {
+		fmt.Println(strings.Repeat("=", 100))
+		fmt.Printf("Target: %s\n", target.Target)
+		for _, change := range target.Changes {
+			fmt.Printf("[%s] %s: %s -> %s  %s\n", change.Kind, change.ID, orNone(change.Before), orNone(change.After), change.Description)
+			if change.DetailsBefore != "" || change.DetailsAfter != "" {
+				fmt.Printf("  Details before: %s\n", indent(orNone(change.DetailsBefore)))
+				fmt.Printf("  Details after:  %s\n", indent(orNone(change.DetailsAfter)))
+			}
+			for _, evidence := range change.Evidence {
+				location := evidence.Location
+				if evidence.Line > 0 {
+					location = fmt.Sprintf("%s:%d", location, evidence.Line)
+				}
+				fmt.Printf("  Evidence %s: %s -> %s\n", location, indent(orNone(evidence.Before)), indent(orNone(evidence.After)))
+			}
+		}
+	}
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+// indent aligns the continuation lines of a multi-line value under the change
+func indent(s string) string {
+	return strings.ReplaceAll(strings.TrimSpace(s), "\n", "\n    ")
+}
@@ -0,0 +1,210 @@
+// cmd/history.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gunh0/openstack-security-hub/history"
+	"github.com/gunh0/openstack-security-hub/output"
+	"github.com/gunh0/openstack-security-hub/scan"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyMaxAge   string
+	historyMaxScans int
+)
+
+func initHistoryCommands() {
+	historyCmd := &cobra.Command{
+		Use:   "history",
+		Short: "List, show, delete and prune recorded scans",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List recorded scans, newest first",
+		RunE:  runHistoryList,
+	}
+	showCmd := &cobra.Command{
+		Use:   "show <scan-id>",
+		Short: "Show a recorded scan and its results",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runHistoryShow,
+	}
+	deleteCmd := &cobra.Command{
+		Use:   "delete <scan-id>...",
+		Short: "Delete recorded scans",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runHistoryDelete,
+	}
+	pruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete the scans past the retention policy (HISTORY_MAX_AGE, HISTORY_MAX_SCANS)",
+		RunE:  runHistoryPrune,
+	}
+	pruneCmd.Flags().StringVar(&historyMaxAge, "max-age", "", "delete scans older than this age, e.g. 720h or 90d (default HISTORY_MAX_AGE)")
+	pruneCmd.Flags().IntVar(&historyMaxScans, "max-scans", 0, "keep only the newest scans (default HISTORY_MAX_SCANS)")
+
+	historyCmd.AddCommand(listCmd, showCmd, deleteCmd, pruneCmd)
+	RootCmd.AddCommand(historyCmd)
+}
+
+// openHistory opens the history configured by the HISTORY_* environment variables
+func openHistory() (*history.History, error) {
+	config, err := history.ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	h, err := history.Open(config)
+	if err == nil && h == nil {
+		err = fmt.Errorf("the scan history is disabled (HISTORY_DRIVER=none)")
+	}
+	return h, err
+}
+
+// recordScan records s in the history and prints its ID to stderr
+func recordScan(s history.Scan) {
+	config, err := history.ConfigFromEnv()
+	if err != nil {
+		printError(err)
+		return
+	}
+	h, err := history.Open(config)
+	if err != nil {
+		printError(err)
+		return
+	}
+	if h == nil {
+		return
+	}
+	defer h.Close()
+
+	if err := h.Save(s); err != nil {
+		printError(fmt.Errorf("failed to record scan: %v", err))
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Scan recorded in the history as %s\n", s.ID)
+}
+
+// profileName returns the name of profile, or "" without one
+func profileName(profile *scan.Profile) string {
+	if profile == nil {
+		return ""
+	}
+	return profile.Name
+}
+
+func runHistoryList(cmd *cobra.Command, args []string) error {
+	h, err := openHistory()
+	if err != nil {
+		return err
+	}
+	defer h.Close()
+
+	scans, err := h.List()
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == "json" || outputFormat == "yaml" {
+		return output.Encode(os.Stdout, outputFormat, scans)
+	}
+	if outputFormat != "text" {
+		return fmt.Errorf("history list supports the text, json and yaml output formats")
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tSTARTED\tSOURCE\tSTATUS\tVERSION\tPASS\tFAIL\tERROR\tTARGETS")
+	for _, s := range scans {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%d\t%d\t%d\t%s\n",
+			s.ID, s.StartedAt, s.Source, s.Status, s.Version, s.Summary.Pass, s.Summary.Fail, s.Summary.Error, strings.Join(s.Targets, " "))
+	}
+	table.Flush()
+	return nil
+}
+
+func runHistoryShow(cmd *cobra.Command, args []string) error {
+	h, err := openHistory()
+	if err != nil {
+		return err
+	}
+	defer h.Close()
+
+	s, err := h.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	switch outputFormat {
+	case "text":
+		fmt.Printf("Scan: %s (%s, %s)\n", s.ID, s.Source, s.Status)
+		fmt.Printf("Version: %s\n", s.Version)
+		fmt.Printf("Started: %s, finished: %s\n", s.StartedAt, s.FinishedAt)
+		printReports(s.Reports)
+	case "json", "yaml":
+		err = output.Encode(os.Stdout, outputFormat, s)
+	default:
+		err = output.Render(os.Stdout, outputFormat, output.Records(s.Reports))
+	}
+	return err
+}
+
+func runHistoryDelete(cmd *cobra.Command, args []string) error {
+	h, err := openHistory()
+	if err != nil {
+		return err
+	}
+	defer h.Close()
+
+	failed := 0
+	for _, id := range args {
+		if err := h.Delete(id); err != nil {
+			printError(fmt.Errorf("%s: %v", id, err))
+			failed++
+			continue
+		}
+		fmt.Printf("Deleted %s\n", id)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d of %d scans", failed, len(args))
+	}
+	return nil
+}
+
+func runHistoryPrune(cmd *cobra.Command, args []string) error {
+	config, err := history.ConfigFromEnv()
+	if err != nil {
+		return err
+	}
+	if historyMaxAge != "" {
+		if config.Retention.MaxAge, err = history.ParseAge(historyMaxAge); err != nil {
+			return err
+		}
+	}
+	if historyMaxScans > 0 {
+		config.Retention.MaxScans = historyMaxScans
+	}
+
+	h, err := history.Open(config)
+	if err != nil {
+		return err
+	}
+	if h == nil {
+		return fmt.Errorf("the scan history is disabled (HISTORY_DRIVER=none)")
+	}
+	defer h.Close()
+
+	removed, err := h.Prune()
+	for _, id := range removed {
+		fmt.Printf("Deleted %s\n", id)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d scans deleted\n", len(removed))
+	return nil
+}
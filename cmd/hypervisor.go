@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/checklist/hypervisor"
+	"github.com/gunh0/openstack-security-hub/util"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+func initHypervisorCommands() {
+	hypervisorCmd := &cobra.Command{
+		Use:   "hypervisor",
+		Short: "Run all hypervisor checks",
+		RunE:  runAllHypervisorChecks,
+	}
+
+	hypervisor01Cmd := &cobra.Command{
+		Use:   "hypervisor-01",
+		Short: "Is remote access to libvirtd restricted to authenticated TLS connections?",
+		RunE:  runHypervisor01Check,
+	}
+
+	hypervisor02Cmd := &cobra.Command{
+		Use:   "hypervisor-02",
+		Short: "Is a QEMU security_driver (SELinux/AppArmor) enabled?",
+		RunE:  runHypervisor02Check,
+	}
+
+	hypervisor03Cmd := &cobra.Command{
+		Use:   "hypervisor-03",
+		Short: "Are running QEMU processes confined by sVirt?",
+		RunE:  runHypervisor03Check,
+	}
+
+	hypervisor04Cmd := &cobra.Command{
+		Use:   "hypervisor-04",
+		Short: "Are strict permissions set for /var/lib/nova/instances?",
+		RunE:  runHypervisor04Check,
+	}
+
+	hypervisor05Cmd := &cobra.Command{
+		Use:   "hypervisor-05",
+		Short: "Is Kernel Samepage Merging (KSM) disabled?",
+		RunE:  runHypervisor05Check,
+	}
+
+	hypervisor06Cmd := &cobra.Command{
+		Use:   "hypervisor-06",
+		Short: "Is TLS enabled for VNC/SPICE consoles?",
+		RunE:  runHypervisor06Check,
+	}
+
+	RootCmd.AddCommand(hypervisorCmd)
+	RootCmd.AddCommand(hypervisor01Cmd)
+	RootCmd.AddCommand(hypervisor02Cmd)
+	RootCmd.AddCommand(hypervisor03Cmd)
+	RootCmd.AddCommand(hypervisor04Cmd)
+	RootCmd.AddCommand(hypervisor05Cmd)
+	RootCmd.AddCommand(hypervisor06Cmd)
+}
+
+func runHypervisor01Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, hypervisor.CheckHypervisor01)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runHypervisor02Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, hypervisor.CheckHypervisor02)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runHypervisor03Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, hypervisor.CheckHypervisor03)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runHypervisor04Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, hypervisor.CheckHypervisor04)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runHypervisor05Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, hypervisor.CheckHypervisor05)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runHypervisor06Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, hypervisor.CheckHypervisor06)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runAllHypervisorChecks(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	checks := []struct {
+		name string
+		fn   func(*ssh.Client) checklist.CheckResult
+	}{
+		{"Hypervisor-01", hypervisor.CheckHypervisor01},
+		{"Hypervisor-02", hypervisor.CheckHypervisor02},
+		{"Hypervisor-03", hypervisor.CheckHypervisor03},
+		{"Hypervisor-04", hypervisor.CheckHypervisor04},
+		{"Hypervisor-05", hypervisor.CheckHypervisor05},
+		{"Hypervisor-06", hypervisor.CheckHypervisor06},
+	}
+
+	for _, check := range checks {
+		result := runCheck(checkID(check.name), client, check.fn)
+		printResult(checkID(check.name), result)
+	}
+	return nil
+}
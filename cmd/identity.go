@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/gunh0/openstack-security-hub/checklist"
 	"github.com/gunh0/openstack-security-hub/checklist/identity"
 	"github.com/gunh0/openstack-security-hub/util"
@@ -14,129 +12,129 @@ func initIdentityCommands() {
 	identity01Cmd := &cobra.Command{
 		Use:   "identity-01",
 		Short: "Run all identity-01 checks",
-		Run:   runAllIdentity01Checks,
+		RunE:  runAllIdentity01Checks,
 	}
 
 	identity0101Cmd := &cobra.Command{
 		Use:   "identity-01-01",
 		Short: "Check keystone.conf ownership",
-		Run:   runIdentity0101Check,
+		RunE:  runIdentity0101Check,
 	}
 
 	identity0102Cmd := &cobra.Command{
 		Use:   "identity-01-02",
 		Short: "Check keystone-paste.ini ownership",
-		Run:   runIdentity0102Check,
+		RunE:  runIdentity0102Check,
 	}
 
 	identity0103Cmd := &cobra.Command{
 		Use:   "identity-01-03",
 		Short: "Check policy.json ownership",
-		Run:   runIdentity0103Check,
+		RunE:  runIdentity0103Check,
 	}
 
 	identity0104Cmd := &cobra.Command{
 		Use:   "identity-01-04",
 		Short: "Check logging.conf ownership",
-		Run:   runIdentity0104Check,
+		RunE:  runIdentity0104Check,
 	}
 
 	identity0105Cmd := &cobra.Command{
 		Use:   "identity-01-05",
 		Short: "Check signing_cert.pem ownership",
-		Run:   runIdentity0105Check,
+		RunE:  runIdentity0105Check,
 	}
 
 	identity0106Cmd := &cobra.Command{
 		Use:   "identity-01-06",
 		Short: "Check signing_key.pem ownership",
-		Run:   runIdentity0106Check,
+		RunE:  runIdentity0106Check,
 	}
 
 	identity0107Cmd := &cobra.Command{
 		Use:   "identity-01-07",
 		Short: "Check ca.pem ownership",
-		Run:   runIdentity0107Check,
+		RunE:  runIdentity0107Check,
 	}
 
 	identity0108Cmd := &cobra.Command{
 		Use:   "identity-01-08",
 		Short: "Check /etc/keystone directory ownership",
-		Run:   runIdentity0108Check,
+		RunE:  runIdentity0108Check,
 	}
 
 	// Add Identity-02 command
 	identity02Cmd := &cobra.Command{
 		Use:   "identity-02",
 		Short: "Run all identity-02 checks",
-		Run:   runAllIdentity02Checks,
+		RunE:  runAllIdentity02Checks,
 	}
 
 	// Individual Identity-02 commands
 	identity0201Cmd := &cobra.Command{
 		Use:   "identity-02-01",
 		Short: "Check keystone.conf permissions",
-		Run:   runIdentity0201Check,
+		RunE:  runIdentity0201Check,
 	}
 
 	identity0202Cmd := &cobra.Command{
 		Use:   "identity-02-02",
 		Short: "Check keystone-paste.ini permissions",
-		Run:   runIdentity0202Check,
+		RunE:  runIdentity0202Check,
 	}
 
 	identity0203Cmd := &cobra.Command{
 		Use:   "identity-02-03",
 		Short: "Check policy.json permissions",
-		Run:   runIdentity0203Check,
+		RunE:  runIdentity0203Check,
 	}
 
 	identity0204Cmd := &cobra.Command{
 		Use:   "identity-02-04",
 		Short: "Check logging.conf permissions",
-		Run:   runIdentity0204Check,
+		RunE:  runIdentity0204Check,
 	}
 
 	identity0205Cmd := &cobra.Command{
 		Use:   "identity-02-05",
 		Short: "Check signing_cert.pem permissions",
-		Run:   runIdentity0205Check,
+		RunE:  runIdentity0205Check,
 	}
 
 	identity0206Cmd := &cobra.Command{
 		Use:   "identity-02-06",
 		Short: "Check signing_key.pem permissions",
-		Run:   runIdentity0206Check,
+		RunE:  runIdentity0206Check,
 	}
 
 	identity0207Cmd := &cobra.Command{
 		Use:   "identity-02-07",
 		Short: "Check ca.pem permissions",
-		Run:   runIdentity0207Check,
+		RunE:  runIdentity0207Check,
 	}
 
 	identity0208Cmd := &cobra.Command{
 		Use:   "identity-02-08",
 		Short: "Check /etc/keystone directory permissions",
-		Run:   runIdentity0208Check,
+		RunE:  runIdentity0208Check,
 	}
 
 	identity03Cmd := &cobra.Command{
 		Use:   "identity-03",
 		Short: "Is TLS enabled for Identity?",
-		Run:   runIdentity03Check,
+		RunE:  runIdentity03Check,
 	}
 
 	identity05Cmd := &cobra.Command{
 		Use:   "identity-05",
 		Short: "Is max_request_body_size set to default (114688)?",
-		Run:   runIdentity05Check,
+		RunE:  runIdentity05Check,
 	}
 
 	identity06Cmd := &cobra.Command{
 		Use:   "identity-06",
 		Short: "Disable admin token in /etc/keystone/keystone.conf",
-		Run:   runIdentity06Check,
+		RunE:  runIdentity06Check,
 	}
 
 	RootCmd.AddCommand(identity01Cmd)
@@ -162,107 +160,106 @@ func initIdentityCommands() {
 	RootCmd.AddCommand(identity06Cmd)
 }
 
-func runIdentity0101Check(cmd *cobra.Command, args []string) {
+func runIdentity0101Check(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := identity.CheckIdentity0101(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, identity.CheckIdentity0101)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runIdentity0102Check(cmd *cobra.Command, args []string) {
+func runIdentity0102Check(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := identity.CheckIdentity0102(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, identity.CheckIdentity0102)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runIdentity0103Check(cmd *cobra.Command, args []string) {
+func runIdentity0103Check(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := identity.CheckIdentity0103(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, identity.CheckIdentity0103)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runIdentity0104Check(cmd *cobra.Command, args []string) {
+func runIdentity0104Check(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := identity.CheckIdentity0104(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, identity.CheckIdentity0104)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runIdentity0105Check(cmd *cobra.Command, args []string) {
+func runIdentity0105Check(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := identity.CheckIdentity0105(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, identity.CheckIdentity0105)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runIdentity0106Check(cmd *cobra.Command, args []string) {
+func runIdentity0106Check(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := identity.CheckIdentity0106(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, identity.CheckIdentity0106)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runIdentity0107Check(cmd *cobra.Command, args []string) {
+func runIdentity0107Check(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := identity.CheckIdentity0107(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, identity.CheckIdentity0107)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runIdentity0108Check(cmd *cobra.Command, args []string) {
+func runIdentity0108Check(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := identity.CheckIdentity0108(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, identity.CheckIdentity0108)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runAllIdentity01Checks(cmd *cobra.Command, args []string) {
+func runAllIdentity01Checks(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
@@ -281,113 +278,113 @@ func runAllIdentity01Checks(cmd *cobra.Command, args []string) {
 	}
 
 	for _, check := range checks {
-		result := check.fn(client)
-		util.PrettyPrintResult(result)
+		result := runCheck(checkID(check.name), client, check.fn)
+		printResult(checkID(check.name), result)
 	}
+	return nil
 }
 
 // Add individual check functions for Identity-02
-func runIdentity0201Check(cmd *cobra.Command, args []string) {
+func runIdentity0201Check(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := identity.CheckIdentity0201(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, identity.CheckIdentity0201)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runIdentity0202Check(cmd *cobra.Command, args []string) {
+func runIdentity0202Check(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := identity.CheckIdentity0202(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, identity.CheckIdentity0202)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runIdentity0203Check(cmd *cobra.Command, args []string) {
+func runIdentity0203Check(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := identity.CheckIdentity0203(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, identity.CheckIdentity0203)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runIdentity0204Check(cmd *cobra.Command, args []string) {
+func runIdentity0204Check(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := identity.CheckIdentity0204(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, identity.CheckIdentity0204)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runIdentity0205Check(cmd *cobra.Command, args []string) {
+func runIdentity0205Check(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := identity.CheckIdentity0205(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, identity.CheckIdentity0205)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runIdentity0206Check(cmd *cobra.Command, args []string) {
+func runIdentity0206Check(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := identity.CheckIdentity0206(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, identity.CheckIdentity0206)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runIdentity0207Check(cmd *cobra.Command, args []string) {
+func runIdentity0207Check(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := identity.CheckIdentity0207(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, identity.CheckIdentity0207)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runIdentity0208Check(cmd *cobra.Command, args []string) {
+func runIdentity0208Check(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := identity.CheckIdentity0208(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, identity.CheckIdentity0208)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runAllIdentity02Checks(cmd *cobra.Command, args []string) {
+func runAllIdentity02Checks(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
@@ -406,43 +403,44 @@ func runAllIdentity02Checks(cmd *cobra.Command, args []string) {
 	}
 
 	for _, check := range checks {
-		result := check.fn(client)
-		util.PrettyPrintResult(result)
+		result := runCheck(checkID(check.name), client, check.fn)
+		printResult(checkID(check.name), result)
 	}
+	return nil
 }
 
-func runIdentity03Check(cmd *cobra.Command, args []string) {
+func runIdentity03Check(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := identity.CheckIdentity03(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, identity.CheckIdentity03)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runIdentity05Check(cmd *cobra.Command, args []string) {
+func runIdentity05Check(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := identity.CheckIdentity05(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, identity.CheckIdentity05)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runIdentity06Check(cmd *cobra.Command, args []string) {
+func runIdentity06Check(cmd *cobra.Command, args []string) error {
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
-	result := identity.CheckIdentity06(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, identity.CheckIdentity06)
+	printResult(cmd.Name(), result)
+	return nil
 }
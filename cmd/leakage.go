@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/checklist/leakage"
+	"github.com/gunh0/openstack-security-hub/util"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+func initLeakageCommands() {
+	leakageCmd := &cobra.Command{
+		Use:   "leakage",
+		Short: "Run all leakage checks",
+		RunE:  runAllLeakageChecks,
+	}
+
+	leakage01Cmd := &cobra.Command{
+		Use:   "leakage-01",
+		Short: "Are plaintext secrets in service configuration files protected?",
+		RunE:  runLeakage01Check,
+	}
+
+	leakage02Cmd := &cobra.Command{
+		Use:   "leakage-02",
+		Short: "Are service logs free of plaintext secrets?",
+		RunE:  runLeakage02Check,
+	}
+
+	leakage03Cmd := &cobra.Command{
+		Use:   "leakage-03",
+		Short: "Are there no stale backup copies of configuration files?",
+		RunE:  runLeakage03Check,
+	}
+
+	leakage04Cmd := &cobra.Command{
+		Use:   "leakage-04",
+		Short: "Is debug logging disabled for every service?",
+		RunE:  runLeakage04Check,
+	}
+
+	RootCmd.AddCommand(leakageCmd)
+	RootCmd.AddCommand(leakage01Cmd)
+	RootCmd.AddCommand(leakage02Cmd)
+	RootCmd.AddCommand(leakage03Cmd)
+	RootCmd.AddCommand(leakage04Cmd)
+}
+
+func runLeakage01Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, leakage.CheckLeakage01)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runLeakage02Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, leakage.CheckLeakage02)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runLeakage03Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, leakage.CheckLeakage03)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runLeakage04Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, leakage.CheckLeakage04)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runAllLeakageChecks(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	checks := []struct {
+		name string
+		fn   func(*ssh.Client) checklist.CheckResult
+	}{
+		{"Leakage-01", leakage.CheckLeakage01},
+		{"Leakage-02", leakage.CheckLeakage02},
+		{"Leakage-03", leakage.CheckLeakage03},
+		{"Leakage-04", leakage.CheckLeakage04},
+	}
+
+	for _, check := range checks {
+		result := runCheck(checkID(check.name), client, check.fn)
+		printResult(checkID(check.name), result)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/checklist/messaging"
+	"github.com/gunh0/openstack-security-hub/util"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+func initMessagingCommands() {
+	messagingCmd := &cobra.Command{
+		Use:   "messaging",
+		Short: "Run all messaging checks",
+		RunE:  runAllMessagingChecks,
+	}
+
+	messaging01Cmd := &cobra.Command{
+		Use:   "messaging-01",
+		Short: "Is TLS enabled for every service's transport_url?",
+		RunE:  runMessaging01Check,
+	}
+
+	messaging02Cmd := &cobra.Command{
+		Use:   "messaging-02",
+		Short: "Is the RabbitMQ guest account disabled?",
+		RunE:  runMessaging02Check,
+	}
+
+	messaging03Cmd := &cobra.Command{
+		Use:   "messaging-03",
+		Short: "Are strict ownership and permissions set for RabbitMQ configuration files?",
+		RunE:  runMessaging03Check,
+	}
+
+	messaging04Cmd := &cobra.Command{
+		Use:   "messaging-04",
+		Short: "Is the RabbitMQ management plugin restricted?",
+		RunE:  runMessaging04Check,
+	}
+
+	messaging05Cmd := &cobra.Command{
+		Use:   "messaging-05",
+		Short: "Does each service use a separate RabbitMQ virtual host?",
+		RunE:  runMessaging05Check,
+	}
+
+	RootCmd.AddCommand(messagingCmd)
+	RootCmd.AddCommand(messaging01Cmd)
+	RootCmd.AddCommand(messaging02Cmd)
+	RootCmd.AddCommand(messaging03Cmd)
+	RootCmd.AddCommand(messaging04Cmd)
+	RootCmd.AddCommand(messaging05Cmd)
+}
+
+func runMessaging01Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, messaging.CheckMessaging01)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runMessaging02Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, messaging.CheckMessaging02)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runMessaging03Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, messaging.CheckMessaging03)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runMessaging04Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, messaging.CheckMessaging04)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runMessaging05Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, messaging.CheckMessaging05)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runAllMessagingChecks(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	checks := []struct {
+		name string
+		fn   func(*ssh.Client) checklist.CheckResult
+	}{
+		{"Messaging-01", messaging.CheckMessaging01},
+		{"Messaging-02", messaging.CheckMessaging02},
+		{"Messaging-03", messaging.CheckMessaging03},
+		{"Messaging-04", messaging.CheckMessaging04},
+		{"Messaging-05", messaging.CheckMessaging05},
+	}
+
+	for _, check := range checks {
+		result := runCheck(checkID(check.name), client, check.fn)
+		printResult(checkID(check.name), result)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+// cmd/output.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/openstack"
+	"github.com/gunh0/openstack-security-hub/output"
+	"github.com/gunh0/openstack-security-hub/scan"
+	"github.com/gunh0/openstack-security-hub/util"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	outputFormat string
+
+	// pendingRecords holds the results of formats rendered once the command finishes
+	pendingRecords []output.Record
+
+	// checkProfileFile is the --profile of the check commands, loaded into checkProfile
+	checkProfileFile string
+	checkProfile     *scan.Profile
+)
+
+// resultsAnnotation marks the commands printing check results with printResult
+const resultsAnnotation = "results"
+
+func initOutputFlags() {
+	RootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "output format: "+strings.Join(output.Formats, ", "))
+
+	// Errors are printed by Execute; usage is only printed for invalid arguments and flags
+	RootCmd.SilenceErrors = true
+	RootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := output.Validate(outputFormat); err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		if cmd.Annotations[resultsAnnotation] != "" && checkProfileFile != "" {
+			profile, err := scan.LoadProfile(checkProfileFile)
+			if err != nil {
+				return err
+			}
+			checkProfile = profile
+		}
+		return nil
+	}
+}
+
+// markResultCommands marks the commands of RootCmd registered so far as printing check results and
+// gives them the --profile flag
+func markResultCommands() {
+	for _, cmd := range RootCmd.Commands() {
+		if cmd.Annotations == nil {
+			cmd.Annotations = map[string]string{}
+		}
+		cmd.Annotations[resultsAnnotation] = "true"
+		cmd.Flags().StringVar(&checkProfileFile, "profile", "", "YAML profile overriding expected values, enablement and severity of the checks")
+	}
+}
+
+// Execute runs the command line. The results of check commands are rendered even when the command fails,
+// so machine readable formats always print a document, and the returned error sets the exit code.
+func Execute() error {
+	cmd, err := RootCmd.ExecuteC()
+	if cmd != nil && cmd.Annotations[resultsAnnotation] != "" {
+		if flushErr := flushResults(); err == nil {
+			err = flushErr
+		}
+	}
+	return err
+}
+
+// printResult reports the result of the check id in the selected output format
+func printResult(id string, result checklist.CheckResult) {
+	record := output.Record{ID: id, CheckResult: result}
+	if check, ok := scan.Lookup(id); ok {
+		record.Service = check.Service
+		record.Severity = checkProfile.Severity(check)
+	}
+
+	switch outputFormat {
+	case "text":
+		util.PrettyPrintResult(result)
+	case "ndjson":
+		if err := output.WriteLine(os.Stdout, record); err != nil {
+			printError(err)
+		}
+	default:
+		pendingRecords = append(pendingRecords, record)
+	}
+}
+
+// runCheck runs the host check id with fn, or as the --profile sets it
+func runCheck(id string, client *ssh.Client, fn func(*ssh.Client) checklist.CheckResult) checklist.CheckResult {
+	return checkProfile.Check(id, fn)(client)
+}
+
+// runCloudCheck runs the OpenStack API check id with fn, skipped when the --profile disables it
+func runCloudCheck(id string, client *openstack.Client, fn func(*openstack.Client) checklist.CheckResult) checklist.CheckResult {
+	return checkProfile.CloudCheck(id, fn)(client)
+}
+
+// checkID derives the check ID from the names used in the run-all commands, e.g. Identity-01-01
+func checkID(name string) string {
+	return strings.ToLower(name)
+}
+
+// flushResults renders the results collected for formats that need the whole result set,
+// as an empty document when no check ran
+func flushResults() error {
+	if outputFormat == "text" || outputFormat == "ndjson" || output.Validate(outputFormat) != nil {
+		return nil
+	}
+	records := pendingRecords
+	if records == nil {
+		records = []output.Record{}
+	}
+	pendingRecords = nil
+	return output.Render(os.Stdout, outputFormat, records)
+}
+
+// printError reports an error on stderr so stdout only carries the report
+func printError(err error) {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/checklist/policy"
+	"github.com/gunh0/openstack-security-hub/util"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+func initPolicyCommands() {
+	policyCmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Run all policy checks",
+		RunE:  runAllPolicyChecks,
+	}
+
+	policy01Cmd := &cobra.Command{
+		Use:   "policy-01",
+		Short: "Are policy files free of overly permissive rules?",
+		RunE:  runPolicy01Check,
+	}
+
+	policy02Cmd := &cobra.Command{
+		Use:   "policy-02",
+		Short: "Are policy files free of deprecated rule overrides?",
+		RunE:  runPolicy02Check,
+	}
+
+	policy03Cmd := &cobra.Command{
+		Use:   "policy-03",
+		Short: "Are enforce_scope and enforce_new_defaults enabled in [oslo_policy]?",
+		RunE:  runPolicy03Check,
+	}
+
+	RootCmd.AddCommand(policyCmd)
+	RootCmd.AddCommand(policy01Cmd)
+	RootCmd.AddCommand(policy02Cmd)
+	RootCmd.AddCommand(policy03Cmd)
+}
+
+func runPolicy01Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, policy.CheckPolicy01)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runPolicy02Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, policy.CheckPolicy02)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runPolicy03Check(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := runCheck(cmd.Name(), client, policy.CheckPolicy03)
+	printResult(cmd.Name(), result)
+	return nil
+}
+
+func runAllPolicyChecks(cmd *cobra.Command, args []string) error {
+	client, err := util.GetSSHClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	checks := []struct {
+		name string
+		fn   func(*ssh.Client) checklist.CheckResult
+	}{
+		{"Policy-01", policy.CheckPolicy01},
+		{"Policy-02", policy.CheckPolicy02},
+		{"Policy-03", policy.CheckPolicy03},
+	}
+
+	for _, check := range checks {
+		result := runCheck(checkID(check.name), client, check.fn)
+		printResult(checkID(check.name), result)
+	}
+	return nil
+}
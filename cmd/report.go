@@ -0,0 +1,86 @@
+// cmd/report.go
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/gunh0/openstack-security-hub/output"
+	"github.com/gunh0/openstack-security-hub/scan"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportInput     string
+	reportFormat    string
+	reportFramework string
+)
+
+func initReportCommands() {
+	reportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Render a saved scan result (scan -o json) as a report",
+		RunE:  runReport,
+	}
+	reportCmd.Flags().StringVarP(&reportInput, "input", "i", "", "scan result file written by scan -o json")
+	reportCmd.Flags().StringVar(&reportFormat, "format", "html", "report format: html, or any record output format (json, csv, junit, sarif, ...)")
+	reportCmd.Flags().StringVar(&reportFramework, "framework", "", "map results to the controls of a compliance framework ("+frameworkIDs()+"), e.g. for --format controls")
+	reportCmd.MarkFlagRequired("input")
+
+	benchmarkCmd := &cobra.Command{
+		Use:   "benchmark",
+		Short: "Print the checklist as an XCCDF 1.2 benchmark",
+		RunE:  runBenchmark,
+	}
+
+	RootCmd.AddCommand(reportCmd)
+	RootCmd.AddCommand(benchmarkCmd)
+}
+
+// loadReports reads scan reports saved with scan -o json
+func loadReports(file string) ([]scan.Report, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scan result: %v", err)
+	}
+
+	var reports []scan.Report
+	if err := json.Unmarshal(content, &reports); err != nil {
+		return nil, fmt.Errorf("invalid scan result %s: %v", file, err)
+	}
+	return reports, nil
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	reports, err := loadReports(reportInput)
+	if err != nil {
+		return err
+	}
+
+	if reportFramework != "" {
+		framework, err := scan.LookupFramework(reportFramework)
+		if err != nil {
+			return err
+		}
+		for i := range reports {
+			reports[i].Framework = framework.ID
+		}
+	}
+
+	switch reportFormat {
+	case "html":
+		err = output.WriteHTML(os.Stdout, reports)
+	case "text":
+		err = fmt.Errorf("use scan without --output for text reports")
+	default:
+		if err = output.Validate(reportFormat); err == nil {
+			err = output.Render(os.Stdout, reportFormat, output.Records(reports))
+		}
+	}
+	return err
+}
+
+func runBenchmark(cmd *cobra.Command, args []string) error {
+	return output.WriteXCCDF(os.Stdout, nil)
+}
@@ -2,8 +2,6 @@
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
 )
 
@@ -11,20 +9,34 @@ var RootCmd = &cobra.Command{
 	Use:   "security-hub",
 	Short: "OpenStack Security Hub CLI",
 	Long: `A CLI tool for OpenStack security checking.
-Run 'security-hub help' for usage information.`,
-	// No arguments starts the server
-	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) == 0 {
-			fmt.Println("Starting server... Use Ctrl+C to stop")
-		}
-	},
+Run 'security-hub serve' to start the API server, or 'security-hub help' for usage information.`,
 }
 
 func init() {
+	// Output format shared by every check command
+	initOutputFlags()
+
 	// Initialize all service commands
 	initIdentityCommands()
 	initDashboardCommands()
 	initKeyManagerCommands()
+	initMessagingCommands()
+	initDatabaseCommands()
+	initHypervisorCommands()
+	initLeakageCommands()
+	initPolicyCommands()
+	initCloudCommands()
+
+	// The commands above print check results
+	markResultCommands()
+
+	initScanCommands()
+	initReportCommands()
+	initHistoryCommands()
+	initDiffCommands()
+	initWaiverCommands()
+	initTargetCommands()
+	initServeCommands()
 
 	// Add help command
 	helpCmd := &cobra.Command{
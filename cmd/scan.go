@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gunh0/openstack-security-hub/history"
+	"github.com/gunh0/openstack-security-hub/output"
+	"github.com/gunh0/openstack-security-hub/scan"
+	"github.com/gunh0/openstack-security-hub/targets"
+	"github.com/gunh0/openstack-security-hub/util"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	scanHosts       []string
+	scanNoDiscovery bool
+	scanRelease     string
+	scanProfile     string
+	scanFramework   string
+	scanChecks      []string
+	scanNoHistory   bool
+	scanWaivers     string
+	scanTimeout     string
+)
+
+func initScanCommands() {
+	scanCmd := &cobra.Command{
+		Use:   "scan",
+		Short: "Discover services on each host and run the applicable checks",
+		RunE:  runScan,
+	}
+	scanCmd.Flags().StringSliceVar(&scanHosts, "hosts", nil, "target names or hosts to scan as host:port (default SSH_HOST)")
+	scanCmd.Flags().BoolVar(&scanNoDiscovery, "no-discovery", false, "run every host check without service discovery")
+	scanCmd.Flags().StringVar(&scanRelease, "release", "", "OpenStack release (e.g. 2024.1 or caracal) overriding the detected one")
+	scanCmd.Flags().StringVar(&scanProfile, "profile", "", "YAML profile overriding expected values, enablement and severity of checks")
+	scanCmd.Flags().StringSliceVar(&scanChecks, "checks", nil, "run only the checks matching these IDs or glob patterns (e.g. identity-01-*)")
+	scanCmd.Flags().StringVar(&scanWaivers, "waivers", "", "YAML waiver file turning accepted failures into [WAIVED] (default WAIVER_FILE or waivers.yaml)")
+	scanCmd.Flags().StringVar(&scanTimeout, "check-timeout", "", "stop checks running longer than this and report them as [TIMEOUT], 0 for no limit (default CHECK_TIMEOUT or 5m)")
+	scanCmd.Flags().BoolVar(&scanNoHistory, "no-history", false, "do not record the scan in the history")
+	scanCmd.Flags().StringVar(&scanFramework, "framework", "", "run only the checks mapped to a compliance framework ("+frameworkIDs()+") and tag results with its controls")
+
+	discoverCmd := &cobra.Command{
+		Use:   "discover",
+		Short: "Detect installed OpenStack services, deployment style and release",
+		RunE:  runDiscover,
+	}
+	discoverCmd.Flags().StringSliceVar(&scanHosts, "hosts", nil, "target names or hosts to inspect as host:port (default SSH_HOST)")
+
+	RootCmd.AddCommand(scanCmd)
+	RootCmd.AddCommand(discoverCmd)
+}
+
+// frameworkIDs lists the supported framework IDs for flag help
+func frameworkIDs() string {
+	ids := make([]string, 0, len(scan.Frameworks))
+	for _, framework := range scan.Frameworks {
+		ids = append(ids, framework.ID)
+	}
+	return strings.Join(ids, ", ")
+}
+
+// targetHosts returns the target names or hosts given on the command line, or SSH_HOST
+func targetHosts() []string {
+	if len(scanHosts) > 0 {
+		return scanHosts
+	}
+	return []string{os.Getenv("SSH_HOST")}
+}
+
+func runScan(cmd *cobra.Command, args []string) error {
+	opts := scan.Options{NoDiscovery: scanNoDiscovery, Checks: scanChecks}
+	if err := scan.ValidateChecks(scanChecks); err != nil {
+		return err
+	}
+	if scanRelease != "" {
+		release, err := scan.NormalizeRelease(scanRelease)
+		if err != nil {
+			return err
+		}
+		opts.Release = release
+	}
+	if scanProfile != "" {
+		profile, err := scan.LoadProfile(scanProfile)
+		if err != nil {
+			return err
+		}
+		opts.Profile = profile
+	}
+
+	if scanFramework != "" {
+		framework, err := scan.LookupFramework(scanFramework)
+		if err != nil {
+			return err
+		}
+		opts.Framework = framework.ID
+	}
+
+	var err error
+	opts.CheckTimeout, err = scan.CheckTimeoutFromEnv()
+	if scanTimeout != "" {
+		opts.CheckTimeout, err = time.ParseDuration(scanTimeout)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid check timeout: %v", err)
+	}
+
+	waiverFile := scanWaivers
+	if waiverFile == "" {
+		waiverFile = scan.WaiverFilePath()
+	}
+	waivers, err := scan.LoadWaivers(waiverFile)
+	if err != nil {
+		return err
+	}
+	opts.Waivers = waivers
+
+	store, err := targets.OpenFromEnv()
+	if err != nil {
+		return err
+	}
+	opts.Connect = store.Connect
+
+	startedAt := time.Now().UTC().Format(time.RFC3339)
+	reports := scan.Run(context.Background(), targetHosts(), opts)
+	reportExpiredWaivers(reports)
+	if !scanNoHistory {
+		recordScan(history.Scan{
+			ID:         history.NewID(),
+			Status:     "completed",
+			Source:     "cli",
+			Targets:    targetHosts(),
+			Checks:     scanChecks,
+			Profile:    profileName(opts.Profile),
+			Release:    opts.Release,
+			Framework:  opts.Framework,
+			StartedAt:  startedAt,
+			FinishedAt: time.Now().UTC().Format(time.RFC3339),
+			Reports:    reports,
+		})
+	}
+
+	if outputFormat != "text" {
+		return writeReports(reports)
+	}
+	printReports(reports)
+	return nil
+}
+
+// reportExpiredWaivers warns on stderr about the failures whose waiver has expired
+func reportExpiredWaivers(reports []scan.Report) {
+	for _, report := range reports {
+		for _, result := range report.Results {
+			if waiver := result.Waiver; waiver != nil && waiver.Expired {
+				fmt.Fprintf(os.Stderr, "Warning: waiver %s (%s) of %s on %s expired on %s, the failure is reported again\n",
+					waiver.ID, waiver.Owner, result.ID, report.Target, waiver.Expires)
+			}
+		}
+	}
+}
+
+// printReports prints scan reports as text
+func printReports(reports []scan.Report) {
+	for _, report := range reports {
+		fmt.Println(strings.Repeat("=", 100))
+		fmt.Printf("Target: %s\n", report.Target)
+		if report.Discovery != nil {
+			printDiscovery(report.Discovery)
+		}
+		if report.Release != "" {
+			fmt.Printf("Checks selected for release: %s\n", report.Release)
+		}
+		if report.Profile != "" {
+			fmt.Printf("Profile: %s\n", report.Profile)
+		}
+		if report.Framework != "" {
+			fmt.Printf("Framework: %s\n", report.Framework)
+		}
+
+		for _, result := range report.Results {
+			fmt.Printf("[%s] severity: %s\n", result.ID, result.Severity)
+			if len(result.Controls) > 0 {
+				fmt.Printf("Controls: %s\n", strings.Join(result.Controls, ", "))
+			}
+			if waiver := result.Waiver; waiver != nil && !waiver.Expired {
+				fmt.Printf("Waiver: %s by %s until %s: %s\n", waiver.ID, waiver.Owner, waiver.Expires, waiver.Justification)
+			}
+			util.PrettyPrintResult(result.CheckResult)
+		}
+
+		for _, skipped := range report.Skipped {
+			fmt.Printf("[SKIPPED] %s: %s\n", skipped.ID, skipped.Reason)
+		}
+	}
+}
+
+// writeReports renders scan reports in a machine readable output format
+func writeReports(reports []scan.Report) error {
+	if outputFormat == "json" || outputFormat == "yaml" {
+		// Documents keep the report structure of the API, including discovery
+		return output.Encode(os.Stdout, outputFormat, reports)
+	}
+	return output.Render(os.Stdout, outputFormat, output.Records(reports))
+}
+
+// connectHost returns an SSH client for the named target, or for host as host:port
+func connectHost(host string) (*ssh.Client, error) {
+	store, err := targets.OpenFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return store.Connect(context.Background(), host)
+}
+
+func runDiscover(cmd *cobra.Command, args []string) error {
+	if outputFormat != "text" {
+		return writeDiscoveries()
+	}
+
+	hosts := targetHosts()
+	failed := 0
+	for _, host := range hosts {
+		fmt.Println(strings.Repeat("=", 100))
+		fmt.Printf("Target: %s\n", host)
+
+		client, err := connectHost(host)
+		if err != nil {
+			printError(err)
+			failed++
+			continue
+		}
+
+		discovery, err := scan.Discover(client)
+		client.Close()
+		if err != nil {
+			printError(err)
+			failed++
+			continue
+		}
+		printDiscovery(discovery)
+	}
+	if failed > 0 {
+		return fmt.Errorf("discovery failed on %d of %d hosts", failed, len(hosts))
+	}
+	return nil
+}
+
+// writeDiscoveries encodes the discovery of each host, keyed by host as in the API
+func writeDiscoveries() error {
+	if outputFormat != "json" && outputFormat != "yaml" {
+		return fmt.Errorf("discover supports the text, json and yaml output formats")
+	}
+
+	discoveries := map[string]interface{}{}
+	for _, host := range targetHosts() {
+		client, err := connectHost(host)
+		if err != nil {
+			discoveries[host] = map[string]string{"error": err.Error()}
+			continue
+		}
+
+		discovery, err := scan.Discover(client)
+		client.Close()
+		if err != nil {
+			discoveries[host] = map[string]string{"error": err.Error()}
+			continue
+		}
+		discoveries[host] = discovery
+	}
+
+	return output.Encode(os.Stdout, outputFormat, discoveries)
+}
+
+func printDiscovery(discovery *scan.Discovery) {
+	fmt.Printf("Deployment: %s\n", discovery.Deployment)
+	if discovery.Release != "" {
+		fmt.Printf("Release: %s\n", discovery.Release)
+	} else {
+		fmt.Println("Release: unknown")
+	}
+
+	services := []string{}
+	for _, name := range discovery.ServiceNames() {
+		service := fmt.Sprintf("%s (%s", name, discovery.Services[name])
+		if version, ok := discovery.Versions[name]; ok {
+			service += " " + version
+		}
+		services = append(services, service+")")
+	}
+	fmt.Printf("Services: %s\n", strings.Join(services, ", "))
+}
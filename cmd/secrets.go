@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/gunh0/openstack-security-hub/checklist/secrets"
 	"github.com/gunh0/openstack-security-hub/util"
 	"github.com/spf13/cobra"
@@ -12,19 +10,19 @@ func initKeyManagerCommands() {
 	keyManager0101Cmd := &cobra.Command{
 		Use:   "key-manager-01-01",
 		Short: "Is user/group ownership of /etc/barbican/barbican.conf set to root:barbican?",
-		Run:   runKeyManager0101Checks,
+		RunE:  runKeyManager0101Checks,
 	}
 
 	keyManager0102Cmd := &cobra.Command{
 		Use:   "key-manager-01-02",
 		Short: "Is user/group ownership of /etc/barbican/barbican-api-paste.ini set to root:barbican?",
-		Run:   runKeyManager0102Checks,
+		RunE:  runKeyManager0102Checks,
 	}
 
 	keyManager03Cmd := &cobra.Command{
 		Use:   "key-manager-03",
 		Short: "Is OpenStack Identity used for authentication?",
-		Run:   runKeyManager03Checks,
+		RunE:  runKeyManager03Checks,
 	}
 
 	RootCmd.AddCommand(keyManager0101Cmd)
@@ -32,44 +30,44 @@ func initKeyManagerCommands() {
 	RootCmd.AddCommand(keyManager03Cmd)
 }
 
-func runKeyManager0101Checks(cmd *cobra.Command, args []string) {
+func runKeyManager0101Checks(cmd *cobra.Command, args []string) error {
 	// Get SSH client
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
 	// Run check and print result
-	result := secrets.CheckKeyManager0101(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, secrets.CheckKeyManager0101)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runKeyManager0102Checks(cmd *cobra.Command, args []string) {
+func runKeyManager0102Checks(cmd *cobra.Command, args []string) error {
 	// Get SSH client
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
 	// Run check and print result
-	result := secrets.CheckKeyManager0102(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, secrets.CheckKeyManager0102)
+	printResult(cmd.Name(), result)
+	return nil
 }
 
-func runKeyManager03Checks(cmd *cobra.Command, args []string) {
+func runKeyManager03Checks(cmd *cobra.Command, args []string) error {
 	// Get SSH client
 	client, err := util.GetSSHClient()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	defer client.Close()
 
 	// Run check and print result
-	result := secrets.CheckKeyManager03(client)
-	util.PrettyPrintResult(result)
+	result := runCheck(cmd.Name(), client, secrets.CheckKeyManager03)
+	printResult(cmd.Name(), result)
+	return nil
 }
@@ -0,0 +1,239 @@
+// cmd/serve.go
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gunh0/openstack-security-hub/api"
+	"github.com/gunh0/openstack-security-hub/api/handler"
+	"github.com/gunh0/openstack-security-hub/auth"
+	"github.com/gunh0/openstack-security-hub/docs"
+	"github.com/spf13/cobra"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+var (
+	serveListen      string
+	serveTLSCert     string
+	serveTLSKey      string
+	serveClientCA    string
+	serveSwaggerHost string
+	serveRequestTime string
+	serveShutdown    string
+)
+
+func initServeCommands() {
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Start the API server",
+		Long: `Start the API server and its Swagger documentation.
+Flags default to the LISTEN_ADDR, TLS_CERT_FILE, TLS_KEY_FILE, TLS_CLIENT_CA_FILE, SWAGGER_HOST,
+REQUEST_TIMEOUT and SHUTDOWN_TIMEOUT environment variables.
+
+On SIGINT or SIGTERM the server stops accepting requests and scan jobs, and waits up to the
+shutdown timeout for the running scans before cancelling them.`,
+		Args: cobra.NoArgs,
+		RunE: runServe,
+	}
+	serveCmd.Flags().StringVar(&serveListen, "listen", "", "address to listen on, e.g. 127.0.0.1:8443 (default :8080)")
+	serveCmd.Flags().StringVar(&serveTLSCert, "tls-cert", "", "PEM certificate file; serves HTTPS with --tls-key")
+	serveCmd.Flags().StringVar(&serveTLSKey, "tls-key", "", "PEM private key file of --tls-cert")
+	serveCmd.Flags().StringVar(&serveClientCA, "client-ca", "", "PEM CA bundle; clients must present a certificate signed by it (mTLS)")
+	serveCmd.Flags().StringVar(&serveSwaggerHost, "swagger-host", "", "host[:port] used by the Swagger UI to call the API (default derived from --listen)")
+	serveCmd.Flags().StringVar(&serveRequestTime, "request-timeout", "", "stop the checks of a request running longer than this, 0 for no limit (default 15m)")
+	serveCmd.Flags().StringVar(&serveShutdown, "shutdown-timeout", "", "time given to running scans on shutdown before they are cancelled (default 1m)")
+
+	RootCmd.AddCommand(serveCmd)
+}
+
+// flagOrEnv returns the flag value, or the environment variable name, or fallback
+func flagOrEnv(value, name, fallback string) string {
+	if value != "" {
+		return value
+	}
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// durationFlag parses the duration flag value, or the environment variable name, or fallback
+func durationFlag(value, name, fallback string) (time.Duration, error) {
+	value = flagOrEnv(value, name, fallback)
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return duration, nil
+}
+
+// swaggerHost derives the host the Swagger UI calls from the listen address, using localhost for
+// wildcard addresses
+func swaggerHost(listen string) string {
+	host, port, err := net.SplitHostPort(listen)
+	if err != nil {
+		return listen
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// serverTLSConfig returns the TLS settings of the server, requiring client certificates signed by clientCA when set
+func serverTLSConfig(clientCA string) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if clientCA == "" {
+		return config, nil
+	}
+
+	pem, err := os.ReadFile(clientCA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", clientCA)
+	}
+	config.ClientCAs = pool
+	config.ClientAuth = tls.RequireAndVerifyClientCert
+	return config, nil
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	listen := flagOrEnv(serveListen, "LISTEN_ADDR", ":8080")
+	certFile := flagOrEnv(serveTLSCert, "TLS_CERT_FILE", "")
+	keyFile := flagOrEnv(serveTLSKey, "TLS_KEY_FILE", "")
+	clientCA := flagOrEnv(serveClientCA, "TLS_CLIENT_CA_FILE", "")
+	requestTimeout, err := durationFlag(serveRequestTime, "REQUEST_TIMEOUT", "15m")
+	if err != nil {
+		return err
+	}
+	shutdownTimeout, err := durationFlag(serveShutdown, "SHUTDOWN_TIMEOUT", "1m")
+	if err != nil {
+		return err
+	}
+
+	useTLS := certFile != "" || keyFile != ""
+	if useTLS && (certFile == "" || keyFile == "") {
+		return errors.New("both a TLS certificate and key are required (--tls-cert and --tls-key)")
+	}
+	if clientCA != "" && !useTLS {
+		return errors.New("client certificate verification (--client-ca) requires TLS (--tls-cert and --tls-key)")
+	}
+	tlsConfig, err := serverTLSConfig(clientCA)
+	if err != nil {
+		return err
+	}
+
+	config, err := auth.ConfigFromEnv()
+	if err != nil {
+		return err
+	}
+	authenticator, err := auth.New(config)
+	if err != nil {
+		return err
+	}
+	if authenticator == nil {
+		log.Printf("Warning: API authentication is disabled (API_AUTH=none), anyone reaching the server can run scans")
+	}
+
+	scheme := "http"
+	if useTLS {
+		scheme = "https"
+	}
+	docs.SwaggerInfo.Title = "OpenStack Security Hub API"
+	docs.SwaggerInfo.Description = "API server for OpenStack security checking"
+	docs.SwaggerInfo.Version = "1.0"
+	docs.SwaggerInfo.Host = flagOrEnv(serveSwaggerHost, "SWAGGER_HOST", swaggerHost(listen))
+	docs.SwaggerInfo.BasePath = "/api/v1"
+	docs.SwaggerInfo.Schemes = []string{scheme}
+
+	r := gin.Default()
+	r.Use(api.RequestTimeout(requestTimeout))
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.InstanceName(api.DocsInstance)))
+
+	// Register all API routes and health check endpoint
+	api.RegisterRoutes(r, authenticator)
+
+	// Requests run in baseCtx, cancelled when the shutdown timeout is exceeded
+	baseCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+	server := &http.Server{
+		Addr:              listen,
+		Handler:           r,
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: 30 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return baseCtx
+		},
+	}
+	log.Printf("Starting server on %s://%s...", scheme, listen)
+	if clientCA != "" {
+		log.Printf("Clients must present a certificate signed by %s", clientCA)
+	}
+	log.Printf("Swagger documentation available at %s://%s/swagger/index.html", scheme, docs.SwaggerInfo.Host)
+
+	signals, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	serveErr := make(chan error, 1)
+	go func() {
+		if useTLS {
+			serveErr <- server.ListenAndServeTLS(certFile, keyFile)
+		} else {
+			serveErr <- server.ListenAndServe()
+		}
+	}()
+	select {
+	case err := <-serveErr:
+		// The server could not listen, so no scan was started
+		if err := handler.CloseScanHistory(); err != nil {
+			log.Printf("[ERROR] Failed to close the scan history: %v", err)
+		}
+		return err
+	case <-signals.Done():
+	}
+	// A second signal terminates the server at once
+	stop()
+
+	log.Printf("Shutting down, waiting up to %s for running scans...", shutdownTimeout)
+	deadline, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	jobsDone := make(chan error, 1)
+	go func() {
+		jobsDone <- handler.ShutdownScanJobs(deadline)
+	}()
+	if err := server.Shutdown(deadline); err != nil {
+		log.Printf("Cancelling the requests still running after %s", shutdownTimeout)
+		cancelRequests()
+
+		// Give the cancelled requests time to report their partial results
+		grace, cancelGrace := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := server.Shutdown(grace); err != nil {
+			server.Close()
+		}
+		cancelGrace()
+	}
+	if err := <-jobsDone; err != nil {
+		log.Printf("Cancelled the scan jobs still running after %s", shutdownTimeout)
+	}
+	if err := handler.CloseScanHistory(); err != nil {
+		log.Printf("[ERROR] Failed to close the scan history: %v", err)
+	}
+	log.Printf("Server stopped")
+	return nil
+}
@@ -0,0 +1,295 @@
+// cmd/targets.go
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gunh0/openstack-security-hub/output"
+	"github.com/gunh0/openstack-security-hub/targets"
+	"github.com/spf13/cobra"
+)
+
+var (
+	targetsFile     string
+	targetsKeyFile  string
+	targetHost      string
+	targetPort      int
+	targetUser      string
+	targetAuth      string
+	targetPassword  bool
+	targetKey       string
+	targetKeyPath   string
+	targetPassStdin bool
+	targetRole      string
+	targetTags      []string
+	targetJumpHost  string
+	targetHostKey   string
+	targetKnownHost string
+)
+
+func initTargetCommands() {
+	targetsCmd := &cobra.Command{
+		Use:   "targets",
+		Short: "List, add, update and delete the named hosts to scan",
+		Long: `Manage named targets: hosts to scan over SSH with their port, user, authentication,
+role, tags, jump host and host key. A target name can be given wherever a host is
+expected, e.g. scan --hosts controller-1,compute-1.
+
+Secrets (passwords, private keys and passphrases) are encrypted in the target file with
+the key TARGETS_KEY, or the key file created on first use (default TARGETS_KEY_FILE or
+targets.key).`,
+	}
+	targetsCmd.PersistentFlags().StringVar(&targetsFile, "file", "", "YAML target file (default TARGETS_FILE or targets.yaml)")
+	targetsCmd.PersistentFlags().StringVar(&targetsKeyFile, "key-file", "", "file holding the encryption key of the secrets (default TARGETS_KEY_FILE or targets.key)")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List targets",
+		Args:  cobra.NoArgs,
+		RunE:  runTargetsList,
+	}
+	showCmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show a target without its secrets",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runTargetsShow,
+	}
+	addCmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Add a target",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runTargetsAdd,
+	}
+	updateCmd := &cobra.Command{
+		Use:   "update <name>",
+		Short: "Change the given settings of a target",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runTargetsUpdate,
+	}
+	for _, c := range []*cobra.Command{addCmd, updateCmd} {
+		c.Flags().StringVar(&targetHost, "host", "", "host name or IP address")
+		c.Flags().IntVar(&targetPort, "port", 22, "SSH port")
+		c.Flags().StringVar(&targetUser, "user", "", "SSH user (default SSH_USER with --auth env)")
+		c.Flags().StringVar(&targetAuth, "auth", targets.AuthEnv, "authentication method: env (SSH_USER and SSH_PASSWORD), password, private_key or key_file")
+		c.Flags().BoolVar(&targetPassword, "password-stdin", false, "read the password from stdin (--auth password)")
+		c.Flags().StringVar(&targetKey, "private-key-file", "", "PEM private key to store encrypted in the target file (--auth private_key)")
+		c.Flags().StringVar(&targetKeyPath, "ssh-key", "", "path of a private key read at each connection (--auth key_file)")
+		c.Flags().BoolVar(&targetPassStdin, "passphrase-stdin", false, "read the passphrase of the private key from stdin")
+		c.Flags().StringVar(&targetRole, "role", "", "role of the host, e.g. controller, compute or storage")
+		c.Flags().StringSliceVar(&targetTags, "tags", nil, "comma separated tags")
+		c.Flags().StringVar(&targetJumpHost, "jump-host", "", "name of the target to connect through")
+		c.Flags().StringVar(&targetHostKey, "host-key", "", "SHA256 fingerprint of the host key, as printed by ssh-keygen -l (default: check the known hosts file)")
+		c.Flags().StringVar(&targetKnownHost, "known-hosts", "", "known hosts file holding the host key (default SSH_KNOWN_HOSTS or ~/.ssh/known_hosts)")
+	}
+	deleteCmd := &cobra.Command{
+		Use:   "delete <name>...",
+		Short: "Delete targets and their secrets",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runTargetsDelete,
+	}
+
+	targetsCmd.AddCommand(listCmd, showCmd, addCmd, updateCmd, deleteCmd)
+	RootCmd.AddCommand(targetsCmd)
+}
+
+// openTargets opens the target store selected by --file and --key-file, or by the environment
+func openTargets() (*targets.Store, error) {
+	file, keyFile := targetsFile, targetsKeyFile
+	if file == "" {
+		file = targets.FilePath()
+	}
+	if keyFile == "" {
+		keyFile = targets.KeyFilePath()
+	}
+	return targets.Open(file, os.Getenv("TARGETS_KEY"), keyFile)
+}
+
+func runTargetsList(cmd *cobra.Command, args []string) error {
+	store, err := openTargets()
+	if err != nil {
+		return err
+	}
+	list, err := store.List()
+	if err != nil {
+		return err
+	}
+	for i := range list {
+		list[i] = list[i].Redacted()
+	}
+
+	if outputFormat == "json" || outputFormat == "yaml" {
+		return output.Encode(os.Stdout, outputFormat, list)
+	}
+	if outputFormat != "text" {
+		return fmt.Errorf("targets list supports the text, json and yaml output formats")
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "NAME\tADDRESS\tUSER\tAUTH\tROLE\tTAGS\tJUMP HOST")
+	for _, t := range list {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			t.Name, t.Address(), t.User, t.Auth.Method, t.Role, strings.Join(t.Tags, ","), t.JumpHost)
+	}
+	table.Flush()
+	return nil
+}
+
+func runTargetsShow(cmd *cobra.Command, args []string) error {
+	store, err := openTargets()
+	if err != nil {
+		return err
+	}
+	target, err := store.Get(args[0])
+	if err != nil {
+		return fmt.Errorf("%s: %v", args[0], err)
+	}
+
+	format := outputFormat
+	if format == "text" {
+		format = "yaml"
+	}
+	if format != "json" && format != "yaml" {
+		return fmt.Errorf("targets show supports the text, json and yaml output formats")
+	}
+	return output.Encode(os.Stdout, format, target.Redacted())
+}
+
+func runTargetsAdd(cmd *cobra.Command, args []string) error {
+	store, err := openTargets()
+	if err != nil {
+		return err
+	}
+
+	target := targets.Target{Name: args[0]}
+	if err := applyTargetFlags(cmd, &target); err != nil {
+		return err
+	}
+	if _, err := store.Create(target); err != nil {
+		return fmt.Errorf("%s: %v", target.Name, err)
+	}
+	fmt.Printf("Added %s\n", target.Name)
+	return nil
+}
+
+func runTargetsUpdate(cmd *cobra.Command, args []string) error {
+	store, err := openTargets()
+	if err != nil {
+		return err
+	}
+
+	target, err := store.Get(args[0])
+	if err != nil {
+		return fmt.Errorf("%s: %v", args[0], err)
+	}
+	if cmd.Flags().Changed("auth") && targetAuth != target.Auth.Method {
+		// Secrets of the previous method do not carry over
+		target.Auth = targets.Auth{}
+	}
+	if err := applyTargetFlags(cmd, &target); err != nil {
+		return err
+	}
+	if _, err := store.Put(target); err != nil {
+		return fmt.Errorf("%s: %v", target.Name, err)
+	}
+	fmt.Printf("Updated %s\n", target.Name)
+	return nil
+}
+
+func runTargetsDelete(cmd *cobra.Command, args []string) error {
+	store, err := openTargets()
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, name := range args {
+		if err := store.Delete(name); err != nil {
+			printError(fmt.Errorf("%s: %v", name, err))
+			failed++
+			continue
+		}
+		fmt.Printf("Deleted %s\n", name)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d of %d targets", failed, len(args))
+	}
+	return nil
+}
+
+// applyTargetFlags sets the fields of target given on the command line, reading secrets from stdin
+// and key files
+func applyTargetFlags(cmd *cobra.Command, target *targets.Target) error {
+	flags := cmd.Flags()
+	if targetPassword && targetPassStdin {
+		return errors.New("--password-stdin and --passphrase-stdin cannot be used together")
+	}
+
+	if flags.Changed("host") {
+		target.Host = targetHost
+	}
+	if flags.Changed("port") || target.Port == 0 {
+		target.Port = targetPort
+	}
+	if flags.Changed("user") {
+		target.User = targetUser
+	}
+	if flags.Changed("auth") || target.Auth.Method == "" {
+		target.Auth.Method = targetAuth
+	}
+	if flags.Changed("role") {
+		target.Role = targetRole
+	}
+	if flags.Changed("tags") {
+		target.Tags = targetTags
+	}
+	if flags.Changed("jump-host") {
+		target.JumpHost = targetJumpHost
+	}
+	if flags.Changed("host-key") {
+		target.HostKey = targetHostKey
+	}
+	if flags.Changed("known-hosts") {
+		target.KnownHosts = targetKnownHost
+	}
+	if flags.Changed("ssh-key") {
+		target.Auth.KeyFile = targetKeyPath
+	}
+	if targetKey != "" {
+		key, err := os.ReadFile(targetKey)
+		if err != nil {
+			return fmt.Errorf("failed to read private key: %v", err)
+		}
+		target.Auth.PrivateKey = string(key)
+	}
+
+	if targetPassword || targetPassStdin {
+		secret, err := readSecret(os.Stdin)
+		if err != nil {
+			return err
+		}
+		if targetPassword {
+			target.Auth.Password = secret
+		} else {
+			target.Auth.Passphrase = secret
+		}
+	}
+	return nil
+}
+
+// readSecret reads the first line of r, so that secrets stay out of the shell history and process list
+func readSecret(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read stdin: %v", err)
+	}
+	secret := strings.TrimRight(line, "\r\n")
+	if secret == "" {
+		return "", errors.New("no secret on stdin")
+	}
+	return secret, nil
+}
@@ -0,0 +1,162 @@
+// cmd/waivers.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gunh0/openstack-security-hub/output"
+	"github.com/gunh0/openstack-security-hub/scan"
+	"github.com/spf13/cobra"
+)
+
+var (
+	waiverFile          string
+	waiverHosts         []string
+	waiverJustification string
+	waiverOwner         string
+	waiverExpires       string
+)
+
+func initWaiverCommands() {
+	waiversCmd := &cobra.Command{
+		Use:   "waivers",
+		Short: "List, add and delete the waivers of accepted risks",
+	}
+	waiversCmd.PersistentFlags().StringVar(&waiverFile, "file", "", "YAML waiver file (default WAIVER_FILE or waivers.yaml)")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List waivers and whether they are active or expired",
+		RunE:  runWaiversList,
+	}
+	addCmd := &cobra.Command{
+		Use:   "add <check>",
+		Short: "Waive the failure of a check (ID or glob pattern) on some hosts until a date",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runWaiversAdd,
+	}
+	addCmd.Flags().StringSliceVar(&waiverHosts, "hosts", nil, "hosts or glob patterns the waiver applies to (default every host)")
+	addCmd.Flags().StringVar(&waiverJustification, "justification", "", "why the risk is accepted")
+	addCmd.Flags().StringVar(&waiverOwner, "owner", "", "who accepted the risk")
+	addCmd.Flags().StringVar(&waiverExpires, "expires", "", "last day the waiver applies, as YYYY-MM-DD")
+	deleteCmd := &cobra.Command{
+		Use:   "delete <waiver-id>...",
+		Short: "Delete waivers",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runWaiversDelete,
+	}
+
+	waiversCmd.AddCommand(listCmd, addCmd, deleteCmd)
+	RootCmd.AddCommand(waiversCmd)
+}
+
+// waiverPath returns the waiver file selected by --file, WAIVER_FILE or the default
+func waiverPath() string {
+	if waiverFile != "" {
+		return waiverFile
+	}
+	return scan.WaiverFilePath()
+}
+
+func runWaiversList(cmd *cobra.Command, args []string) error {
+	waivers, err := scan.LoadWaivers(waiverPath())
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == "json" || outputFormat == "yaml" {
+		if waivers == nil {
+			waivers = []scan.Waiver{}
+		}
+		return output.Encode(os.Stdout, outputFormat, waivers)
+	}
+	if outputFormat != "text" {
+		return fmt.Errorf("waivers list supports the text, json and yaml output formats")
+	}
+
+	now := time.Now()
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tCHECK\tHOSTS\tOWNER\tEXPIRES\tSTATUS\tJUSTIFICATION")
+	for _, w := range waivers {
+		hosts := strings.Join(w.Hosts, " ")
+		if hosts == "" {
+			hosts = "*"
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			w.ID, w.Check, hosts, w.Owner, w.Expires, w.Status(now), w.Justification)
+	}
+	table.Flush()
+	return nil
+}
+
+func runWaiversAdd(cmd *cobra.Command, args []string) error {
+	file := waiverPath()
+	waivers, err := scan.LoadWaivers(file)
+	if err != nil {
+		return err
+	}
+
+	waiver := scan.Waiver{
+		ID:            scan.NewWaiverID(),
+		Check:         args[0],
+		Hosts:         waiverHosts,
+		Justification: waiverJustification,
+		Owner:         waiverOwner,
+		Expires:       waiverExpires,
+	}
+	if err := waiver.Validate(); err != nil {
+		return err
+	}
+	if err := scan.SaveWaivers(file, append(waivers, waiver)); err != nil {
+		return fmt.Errorf("failed to save waivers: %v", err)
+	}
+	fmt.Printf("Added %s to %s\n", waiver.ID, file)
+	return nil
+}
+
+func runWaiversDelete(cmd *cobra.Command, args []string) error {
+	file := waiverPath()
+	waivers, err := scan.LoadWaivers(file)
+	if err != nil {
+		return err
+	}
+
+	remove := map[string]bool{}
+	for _, id := range args {
+		remove[id] = true
+	}
+	kept := waivers[:0]
+	deleted := map[string]bool{}
+	for _, w := range waivers {
+		if remove[w.ID] {
+			deleted[w.ID] = true
+			continue
+		}
+		kept = append(kept, w)
+	}
+	for _, id := range args {
+		if !deleted[id] {
+			printError(fmt.Errorf("%s: waiver not found", id))
+		}
+	}
+	if len(deleted) == 0 {
+		return fmt.Errorf("none of the %d waivers were found", len(args))
+	}
+
+	if err := scan.SaveWaivers(file, kept); err != nil {
+		return fmt.Errorf("failed to save waivers: %v", err)
+	}
+	for _, id := range args {
+		if deleted[id] {
+			fmt.Printf("Deleted %s\n", id)
+		}
+	}
+	if len(deleted) < len(remove) {
+		return fmt.Errorf("%d of %d waivers were not found", len(remove)-len(deleted), len(remove))
+	}
+	return nil
+}
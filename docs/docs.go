@@ -19,9 +19,221 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/benchmark": {
+            "get": {
+                "description": "Returns the checklist as an XCCDF 1.2 benchmark with one rule per check (IDs like xccdf_com.github.gunh0_rule_identity-01-01), grouped by service, with descriptions, rationales and fixtexts. Scan results are exported as TestResults of this benchmark with GET /scan?format=xccdf.",
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "Scan"
+                ],
+                "summary": "XCCDF benchmark of the checklist",
+                "responses": {
+                    "200": {
+                        "description": "XCCDF benchmark",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/cloud": {
+            "get": {
+                "description": "Runs every cloud-level check through the OpenStack APIs instead of SSH. Credentials are taken from clouds.yaml (OS_CLOUD) or the OS_* environment variables.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cloud API"
+                ],
+                "summary": "Run all cloud checks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/checklist.CheckResult"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/check/cloud-01": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cloud API"
+                ],
+                "summary": "Is the admin role limited to a few projects per user?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/cloud-02": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cloud API"
+                ],
+                "summary": "Is multi-factor authentication enforced for administrators?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/cloud-03": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cloud API"
+                ],
+                "summary": "Do security groups block SSH/RDP from 0.0.0.0/0?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/cloud-04": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cloud API"
+                ],
+                "summary": "Are public images published by the cloud administrators only?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/cloud-05": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cloud API"
+                ],
+                "summary": "Are all volumes encrypted?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/cloud-06": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cloud API"
+                ],
+                "summary": "Are instances with floating IPs protected by security groups?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
         "/check/dashboard-01": {
             "get": {
-                "description": "Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user ownership of such critical configuration files must be set to root and group ownership must be set to horizon.",
                 "produces": [
                     "application/json"
                 ],
@@ -29,6 +241,20 @@ const docTemplate = `{
                     "Dashboard"
                 ],
                 "summary": "Is user/group ownership of config files set to root/horizon?Is user/group of config files set to root/horizon?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -44,7 +270,6 @@ const docTemplate = `{
         },
         "/check/dashboard-04": {
             "get": {
-                "description": "CSRF (Cross-site request forgery) is an attack which forces an end user to execute unauthorized commands on a web application in which he/she is currently authenticated. A successful CSRF exploit can compromise end user data and operations. If the targeted end user has admin privileges, this can compromise the entire web application.",
                 "produces": [
                     "application/json"
                 ],
@@ -52,6 +277,20 @@ const docTemplate = `{
                     "Dashboard"
                 ],
                 "summary": "Is CSRF_COOKIE_SECURE parameter set to True?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -67,7 +306,6 @@ const docTemplate = `{
         },
         "/check/dashboard-05": {
             "get": {
-                "description": "The “SECURE” cookie attribute instructs web browsers to only send the cookie through an encrypted HTTPS (SSL/TLS) connection. This session protection mechanism is mandatory to prevent the disclosure of the session ID through MitM (Man-in-the-Middle) attacks. It ensures that an attacker cannot simply capture the session ID from web browser traffic.",
                 "produces": [
                     "application/json"
                 ],
@@ -75,6 +313,20 @@ const docTemplate = `{
                     "Dashboard"
                 ],
                 "summary": "Is SESSION_COOKIE_SECURE parameter set to True?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -90,7 +342,6 @@ const docTemplate = `{
         },
         "/check/dashboard-06": {
             "get": {
-                "description": "The “HTTPONLY” cookie attribute instructs web browsers not to allow scripts (e.g. JavaScript or VBscript) an ability to access the cookies via the DOM document.cookie object. This session ID protection is mandatory to prevent session ID stealing through XSS attacks.",
                 "produces": [
                     "application/json"
                 ],
@@ -98,6 +349,20 @@ const docTemplate = `{
                     "Dashboard"
                 ],
                 "summary": "Is SESSION_COOKIE_HTTPONLY parameter set to True?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -111,16 +376,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/check/identity-01": {
+        "/check/database": {
             "get": {
-                "description": "Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user and group ownership of such critical configuration files must be set to that component owner. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly.",
+                "description": "Runs every database (MariaDB/MySQL) check. Each service's [database] connection string carries a plaintext password, so both the client connections and the database server must be hardened.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Identity"
+                    "Database"
+                ],
+                "summary": "Run all database checks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Is user/group ownership of config files set to keystone?",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -134,16 +413,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/check/identity-01-01": {
+        "/check/database-01": {
             "get": {
-                "description": "Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user and group ownership of such critical configuration files must be set to that component owner. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Identity"
+                    "Database"
+                ],
+                "summary": "Is TLS used for every service's database connection?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Is user/group ownership of config files set to keystone? (/etc/keystone/keystone.conf)",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -154,16 +446,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/check/identity-01-02": {
+        "/check/database-02": {
             "get": {
-                "description": "Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user and group ownership of such critical configuration files must be set to that component owner. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Identity"
+                    "Database"
+                ],
+                "summary": "Do services avoid connecting to the database as root?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Is user/group ownership of config files set to keystone? (/etc/keystone/keystone-paste.ini)",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -174,16 +479,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/check/identity-01-03": {
+        "/check/database-03": {
             "get": {
-                "description": "Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user and group ownership of such critical configuration files must be set to that component owner. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Identity"
+                    "Database"
+                ],
+                "summary": "Does each service use its own database credentials?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Is user/group ownership of config files set to keystone? (/etc/keystone/policy.json)",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -194,16 +512,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/check/identity-01-04": {
+        "/check/database-04": {
             "get": {
-                "description": "Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user and group ownership of such critical configuration files must be set to that component owner. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Identity"
+                    "Database"
+                ],
+                "summary": "Are MySQL option files (my.cnf) protected from other users?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Is user/group ownership of config files set to keystone? (/etc/keystone/logging.conf)",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -214,16 +545,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/check/identity-01-05": {
+        "/check/database-05": {
             "get": {
-                "description": "Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user and group ownership of such critical configuration files must be set to that component owner. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Identity"
+                    "Database"
+                ],
+                "summary": "Is the database server bind-address restricted?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Is user/group ownership of config files set to keystone? (/etc/keystone/ssl/certs/signing_cert.pem)",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -234,16 +578,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/check/identity-01-06": {
+        "/check/database-06": {
             "get": {
-                "description": "Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user and group ownership of such critical configuration files must be set to that component owner. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Identity"
+                    "Database"
+                ],
+                "summary": "Is require_secure_transport enabled on the database server?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Is user/group ownership of config files set to keystone? (/etc/keystone/ssl/private/signing_key.pem)",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -254,16 +611,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/check/identity-01-07": {
+        "/check/database-07": {
             "get": {
-                "description": "Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user and group ownership of such critical configuration files must be set to that component owner. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Identity"
+                    "Database"
+                ],
+                "summary": "Are anonymous database users removed?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Is user/group ownership of config files set to keystone? (/etc/keystone/ssl/certs/ca.pem)",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -274,36 +644,66 @@ const docTemplate = `{
                 }
             }
         },
-        "/check/identity-01-08": {
+        "/check/hypervisor": {
             "get": {
-                "description": "Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user and group ownership of such critical configuration files must be set to that component owner. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly.",
+                "description": "Runs every hypervisor (libvirt/QEMU) check on a compute node. The hypervisor is the isolation boundary between tenants, so its management interfaces and guest confinement must be hardened.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Identity"
+                    "Hypervisor"
+                ],
+                "summary": "Run all hypervisor checks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Is user/group ownership of config files set to keystone? (/etc/keystone)",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/checklist.CheckResult"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/checklist.CheckResult"
+                            }
                         }
                     }
                 }
             }
         },
-        "/check/key-manager-01-01": {
+        "/check/hypervisor-01": {
             "get": {
-                "description": "Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally, modifies or deletes any of the parameters or the file itself then it would cause severe availability issues resulting in a denial of service to the other end users. User ownership of such critical configuration files must be set to root and group ownership must be set to barbican. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Secrets Management"
+                    "Hypervisor"
+                ],
+                "summary": "Is remote access to libvirtd restricted to authenticated TLS connections?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Is the ownership of config files set to root/barbican? (/etc/barbican/barbican.conf)",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -314,16 +714,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/check/key-manager-01-02": {
+        "/check/hypervisor-02": {
             "get": {
-                "description": "Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally, modifies or deletes any of the parameters or the file itself then it would cause severe availability issues resulting in a denial of service to the other end users. User ownership of such critical configuration files must be set to root and group ownership must be set to barbican. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Secrets Management"
+                    "Hypervisor"
+                ],
+                "summary": "Is a QEMU security_driver (SELinux/AppArmor) enabled?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Is the ownership of config files set to root/barbican? (/etc/barbican/barbican-api-paste.ini)",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -334,16 +747,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/check/key-manager-03": {
+        "/check/hypervisor-03": {
             "get": {
-                "description": "OpenStack supports various authentication strategies like noauth and keystone. If the noauth strategy is used then the users can interact with OpenStack services without any authentication. This could be a potential risk since an attacker might gain unauthorized access to the OpenStack components. We strongly recommend that all services must be authenticated with keystone using their service accounts.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Secrets Management"
+                    "Hypervisor"
+                ],
+                "summary": "Are running QEMU processes confined by sVirt?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Is OpenStack Identity used for authentication?",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -354,39 +780,2423 @@ const docTemplate = `{
                 }
             }
         },
-        "/health": {
+        "/check/hypervisor-04": {
             "get": {
-                "description": "Check if the API server is running",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "Hypervisor"
                 ],
-                "summary": "Health check endpoint",
-                "responses": {}
-            }
-        }
-    },
-    "definitions": {
-        "checklist.CheckResult": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "details": {
-                    "type": "string"
-                },
-                "result": {
-                    "type": "string"
-                },
-                "timestamp": {
-                    "type": "string"
+                "summary": "Are strict permissions set for /var/lib/nova/instances?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
                 }
             }
+        },
+        "/check/hypervisor-05": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Hypervisor"
+                ],
+                "summary": "Is Kernel Samepage Merging (KSM) disabled?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/hypervisor-06": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Hypervisor"
+                ],
+                "summary": "Is TLS enabled for VNC/SPICE consoles?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/identity-01": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Identity"
+                ],
+                "summary": "Is user/group ownership of config files set to keystone?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/checklist.CheckResult"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/check/identity-01-01": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Identity"
+                ],
+                "summary": "Is user/group ownership of config files set to keystone? (/etc/keystone/keystone.conf)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/identity-01-02": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Identity"
+                ],
+                "summary": "Is user/group ownership of config files set to keystone? (/etc/keystone/keystone-paste.ini)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/identity-01-03": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Identity"
+                ],
+                "summary": "Is user/group ownership of config files set to keystone? (/etc/keystone/policy.json)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/identity-01-04": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Identity"
+                ],
+                "summary": "Is user/group ownership of config files set to keystone? (/etc/keystone/logging.conf)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/identity-01-05": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Identity"
+                ],
+                "summary": "Is user/group ownership of config files set to keystone? (/etc/keystone/ssl/certs/signing_cert.pem)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/identity-01-06": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Identity"
+                ],
+                "summary": "Is user/group ownership of config files set to keystone? (/etc/keystone/ssl/private/signing_key.pem)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/identity-01-07": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Identity"
+                ],
+                "summary": "Is user/group ownership of config files set to keystone? (/etc/keystone/ssl/certs/ca.pem)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/identity-01-08": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Identity"
+                ],
+                "summary": "Is user/group ownership of config files set to keystone? (/etc/keystone)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/identity-02-01": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Identity"
+                ],
+                "summary": "Are strict permissions set for Identity configuration files? (/etc/keystone/keystone.conf)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/key-manager-01-01": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Secrets Management"
+                ],
+                "summary": "Is the ownership of config files set to root/barbican? (/etc/barbican/barbican.conf)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/key-manager-01-02": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Secrets Management"
+                ],
+                "summary": "Is the ownership of config files set to root/barbican? (/etc/barbican/barbican-api-paste.ini)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/key-manager-03": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Secrets Management"
+                ],
+                "summary": "Is OpenStack Identity used for authentication?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/leakage": {
+            "get": {
+                "description": "Runs every secret leakage check. Service configuration directories under /etc and logs under /var/log are scanned for plaintext secrets, stale backups and debug logging. Secrets are redacted in the reported evidence.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Secret Leakage"
+                ],
+                "summary": "Run all leakage checks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/checklist.CheckResult"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/check/leakage-01": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Secret Leakage"
+                ],
+                "summary": "Are plaintext secrets in service configuration files protected?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/leakage-02": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Secret Leakage"
+                ],
+                "summary": "Are service logs free of plaintext secrets?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/leakage-03": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Secret Leakage"
+                ],
+                "summary": "Are there no stale backup copies of configuration files?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/leakage-04": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Secret Leakage"
+                ],
+                "summary": "Is debug logging disabled for every service?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/messaging": {
+            "get": {
+                "description": "Runs every message bus (RabbitMQ / oslo.messaging) check. The message bus is where the credentials of all services meet, so a weakness there affects the whole cloud.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messaging"
+                ],
+                "summary": "Run all messaging checks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/checklist.CheckResult"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/check/messaging-01": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messaging"
+                ],
+                "summary": "Is TLS enabled for every service's transport_url?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/messaging-02": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messaging"
+                ],
+                "summary": "Is the RabbitMQ guest account disabled?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/messaging-03": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messaging"
+                ],
+                "summary": "Are strict ownership and permissions set for RabbitMQ configuration files?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/messaging-04": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messaging"
+                ],
+                "summary": "Is the RabbitMQ management plugin restricted?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/messaging-05": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messaging"
+                ],
+                "summary": "Does each service use a separate RabbitMQ virtual host?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/policy": {
+            "get": {
+                "description": "Runs every policy analyzer check. The policy file of each service (policy.json or policy.yaml, or the file configured with [oslo_policy] policy_file) is loaded and each risky rule is reported as evidence.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Policy"
+                ],
+                "summary": "Run all policy checks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/checklist.CheckResult"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/check/policy-01": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Policy"
+                ],
+                "summary": "Are policy files free of overly permissive rules?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/policy-02": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Policy"
+                ],
+                "summary": "Are policy files free of deprecated rule overrides?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/check/policy-03": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Policy"
+                ],
+                "summary": "Are enforce_scope and enforce_new_defaults enabled in [oslo_policy]?",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered target (default SSH_HOST)",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values and enablement",
+                        "name": "profile",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/checklist.CheckResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/discover": {
+            "get": {
+                "description": "Detects the installed OpenStack services (by package, systemd unit, container or /etc/\u003cservice\u003e presence), the deployment style and the release of each host without running any check.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Scan"
+                ],
+                "summary": "Discover services on hosts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated names of registered targets (default SSH_HOST)",
+                        "name": "hosts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/scan.Discovery"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check if the API server is running",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check endpoint",
+                "responses": {}
+            }
+        },
+        "/scan": {
+            "get": {
+                "description": "Detects the installed OpenStack services, the deployment style (DevStack, Kolla, OpenStack-Ansible, packages) and the release of each host, then runs only the checks that apply to it. The detected or given release selects the checks and expected values of that release. Checks that do not apply are reported as skipped with a reason. The OpenStack API checks run once when credentials are configured.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/sarif+json"
+                ],
+                "tags": [
+                    "Scan"
+                ],
+                "summary": "Discover services and run the applicable checks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated names of registered targets (default SSH_HOST)",
+                        "name": "hosts",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Run every host check without service discovery",
+                        "name": "no_discovery",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "OpenStack release (e.g. 2024.1 or caracal) overriding the detected one",
+                        "name": "release",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a profile in PROFILE_DIR (default profiles) overriding expected values, enablement and severity",
+                        "name": "profile",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated check IDs or glob patterns (e.g. identity-01-*) limiting the scan",
+                        "name": "checks",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "cis",
+                            "nist-800-53",
+                            "iso-27001",
+                            "pci-dss"
+                        ],
+                        "type": "string",
+                        "description": "Compliance framework limiting the scan to its mapped checks and tagging results with its controls",
+                        "name": "framework",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "junit",
+                            "sarif",
+                            "xccdf",
+                            "controls"
+                        ],
+                        "type": "string",
+                        "description": "Report format: json (default), junit for a JUnit XML export, sarif for a SARIF 2.1.0 log, xccdf for XCCDF 1.2 TestResults or controls for a per control rollup (requires framework)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/scan.Report"
+                            }
+                        },
+                        "headers": {
+                            "X-Scan-Id": {
+                                "type": "string",
+                                "description": "ID of the scan recorded in the history, served by GET /scans/{id}"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/scans": {
+            "get": {
+                "description": "Lists the queued and running scan jobs, newest first, followed by the scans recorded in the history, without their reports.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Scan"
+                ],
+                "summary": "List scan jobs and past scans",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/jobs.Job"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Queues a scan of the given targets and returns the job immediately. Jobs run in a pool of SCAN_WORKERS workers (default 4); up to SCAN_QUEUE_SIZE jobs (default 100) wait in the queue. Targets are names of registered targets; without targets SSH_HOST is scanned. Checks, profile, release and framework select the checks as in GET /scan.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Scan"
+                ],
+                "summary": "Submit a scan job",
+                "parameters": [
+                    {
+                        "description": "Scan request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/jobs.Request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/jobs.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/scans/{id}": {
+            "get": {
+                "description": "Returns the status (queued, running, completed or cancelled), the progress and, once the job has finished, the reports of a scan job or of a scan recorded in the history.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Scan"
+                ],
+                "summary": "Get a scan job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/jobs.Job"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Cancels a queued or running scan job. A running job stops its current check at once; that check and the remaining checks of the current target are reported as skipped and the targets scanned so far are kept. A finished scan is deleted from the history.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Scan"
+                ],
+                "summary": "Cancel a scan job or delete a past scan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/jobs.Job"
+                        }
+                    },
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/scans/{id}/diff/{other}": {
+            "get": {
+                "description": "Reports what changed from scan id to scan other, per target: checks newly failing, newly passing or newly errored, checks added or removed, and other changes of result, details or evidence. Details and evidence are shown before and after, e.g. a file mode going from 640 to 644. Both scans must have finished.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Scan"
+                ],
+                "summary": "Compare two scans",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the earlier scan",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the later scan",
+                        "name": "other",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/scan.Diff"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/scans/{id}/events": {
+            "get": {
+                "description": "Streams the events of a scan job as Server-Sent Events until the job finishes. Each event is named after its type: status (queued, running, then completed or cancelled, with the progress), connected, connection_failed and disconnected for hosts and the OpenStack APIs, check_started, result (with the check result), skipped and target_finished. Events already emitted are replayed first; a reconnecting client resumes after the Last-Event-ID header or the after query parameter.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Scan"
+                ],
+                "summary": "Stream the events of a scan job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only stream the events after this event ID",
+                        "name": "after",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/jobs.Event"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/targets": {
+            "get": {
+                "description": "Lists the named targets of the target file TARGETS_FILE (default targets.yaml) without their secrets. A target name can be given wherever a host is expected: in the hosts of GET /scan, POST /scans and GET /discover, and in the target parameter of the check routes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Targets"
+                ],
+                "summary": "List targets",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/targets.Target"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds a named host to scan over SSH. The auth method is password or private_key (PEM key in the request, which may be protected by a passphrase). The env and key_file methods and known_hosts files use credentials and files of the server and are only available from the command line; pin the host key with host_key instead. Secrets are encrypted in the target file with the key TARGETS_KEY or TARGETS_KEY_FILE (default targets.key) and never returned. A jump host is the name of another target to connect through.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Targets"
+                ],
+                "summary": "Add a target",
+                "parameters": [
+                    {
+                        "description": "Target",
+                        "name": "target",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/targets.Target"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/targets.Target"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/targets/{name}": {
+            "get": {
+                "description": "Returns a named target without its secrets.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Targets"
+                ],
+                "summary": "Get a target",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Target name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/targets.Target"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Creates or replaces the named target, with the auth methods of POST /targets. Secrets left out of the request keep their stored value as long as the auth method, the address and the jump host are unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Targets"
+                ],
+                "summary": "Replace a target",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Target name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target",
+                        "name": "target",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/targets.Target"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/targets.Target"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes a named target and its secrets. A target used as the jump host of another target cannot be deleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Targets"
+                ],
+                "summary": "Delete a target",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Target name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/waivers": {
+            "get": {
+                "description": "Lists the waivers of the waiver file WAIVER_FILE (default waivers.yaml) with their status, active or expired. A failed check covered by an active waiver is reported as [WAIVED]; once the waiver expires the check fails again and its result names the expired waiver.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Waivers"
+                ],
+                "summary": "List waivers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.WaiverStatus"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Accepts the failure of a check (ID or glob pattern such as identity-01-*) on some hosts (hosts, host:port or glob patterns; none means every host) until the expiry date (YYYY-MM-DD, inclusive). A justification and an owner are required. Without an ID one is generated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Waivers"
+                ],
+                "summary": "Add a waiver",
+                "parameters": [
+                    {
+                        "description": "Waiver",
+                        "name": "waiver",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scan.Waiver"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.WaiverStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/waivers/{id}": {
+            "delete": {
+                "description": "Removes a waiver from the waiver file; the checks it covered are reported as failed again by the next scans.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Waivers"
+                ],
+                "summary": "Delete a waiver",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Waiver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "checklist.CheckResult": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "string"
+                },
+                "evidence": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/checklist.Evidence"
+                    }
+                },
+                "result": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "checklist.Evidence": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "snippet": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.WaiverStatus": {
+            "type": "object",
+            "properties": {
+                "check": {
+                    "description": "Check is a check ID or glob pattern such as identity-01-*",
+                    "type": "string"
+                },
+                "expires": {
+                    "description": "Expires is the last day the waiver applies, as YYYY-MM-DD",
+                    "type": "string"
+                },
+                "hosts": {
+                    "description": "Hosts are the targets the waiver applies to, as hosts, host:port or glob patterns; empty means every target",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "justification": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "jobs.Event": {
+            "type": "object",
+            "properties": {
+                "check": {
+                    "description": "Check and Description identify the check of check_started events",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Error explains a connection_failed event",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "progress": {
+                    "$ref": "#/definitions/jobs.Progress"
+                },
+                "result": {
+                    "$ref": "#/definitions/scan.Result"
+                },
+                "skipped": {
+                    "$ref": "#/definitions/scan.Skipped"
+                },
+                "status": {
+                    "description": "Status and Progress are set on status events",
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "jobs.Job": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "progress": {
+                    "$ref": "#/definitions/jobs.Progress"
+                },
+                "reports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scan.Report"
+                    }
+                },
+                "request": {
+                    "$ref": "#/definitions/jobs.Request"
+                },
+                "source": {
+                    "description": "Source and Version are set on scans read from the history",
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "jobs.Progress": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "targets": {
+                    "type": "integer"
+                },
+                "targets_done": {
+                    "type": "integer"
+                }
+            }
+        },
+        "jobs.Request": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "description": "Checks limits the scan to the checks matching these IDs or glob patterns",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "framework": {
+                    "description": "Framework limits the scan to the checks mapped to a compliance framework",
+                    "type": "string"
+                },
+                "no_discovery": {
+                    "description": "NoDiscovery runs every host check without service discovery",
+                    "type": "boolean"
+                },
+                "profile": {
+                    "description": "Profile is the name of the profile overriding expected values, enablement and severity",
+                    "type": "string"
+                },
+                "release": {
+                    "description": "Release overrides the detected release (e.g. 2024.1)",
+                    "type": "string"
+                },
+                "targets": {
+                    "description": "Targets are the names of the registered targets to scan, or SSH_HOST",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "scan.AppliedWaiver": {
+            "type": "object",
+            "properties": {
+                "expired": {
+                    "type": "boolean"
+                },
+                "expires": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "justification": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                }
+            }
+        },
+        "scan.Change": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "string"
+                },
+                "before": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "details_after": {
+                    "type": "string"
+                },
+                "details_before": {
+                    "type": "string"
+                },
+                "evidence": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scan.EvidenceDelta"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "service": {
+                    "type": "string"
+                }
+            }
+        },
+        "scan.Diff": {
+            "type": "object",
+            "properties": {
+                "summary": {
+                    "$ref": "#/definitions/scan.DiffSummary"
+                },
+                "targets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scan.TargetDiff"
+                    }
+                }
+            }
+        },
+        "scan.DiffSummary": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "integer"
+                },
+                "changed": {
+                    "type": "integer"
+                },
+                "newly_errored": {
+                    "type": "integer"
+                },
+                "newly_failing": {
+                    "type": "integer"
+                },
+                "newly_passing": {
+                    "type": "integer"
+                },
+                "removed": {
+                    "type": "integer"
+                }
+            }
+        },
+        "scan.Discovery": {
+            "type": "object",
+            "properties": {
+                "deployment": {
+                    "type": "string"
+                },
+                "release": {
+                    "type": "string"
+                },
+                "services": {
+                    "description": "Services maps each detected service to how it was found (package, systemd, container or config)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "versions": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "scan.EvidenceDelta": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "string"
+                },
+                "before": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                }
+            }
+        },
+        "scan.Report": {
+            "type": "object",
+            "properties": {
+                "discovery": {
+                    "$ref": "#/definitions/scan.Discovery"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "framework": {
+                    "type": "string"
+                },
+                "profile": {
+                    "type": "string"
+                },
+                "release": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scan.Result"
+                    }
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scan.Skipped"
+                    }
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string"
+                }
+            }
+        },
+        "scan.Result": {
+            "type": "object",
+            "properties": {
+                "controls": {
+                    "description": "Controls lists the controls of the selected framework that the check addresses",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "string"
+                },
+                "evidence": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/checklist.Evidence"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "string"
+                },
+                "service": {
+                    "type": "string"
+                },
+                "severity": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "waiver": {
+                    "description": "Waiver is the waiver that turned a failure into [WAIVED], or the expired waiver of a failure",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/scan.AppliedWaiver"
+                        }
+                    ]
+                }
+            }
+        },
+        "scan.Skipped": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "service": {
+                    "type": "string"
+                }
+            }
+        },
+        "scan.TargetDiff": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scan.Change"
+                    }
+                },
+                "target": {
+                    "type": "string"
+                }
+            }
+        },
+        "scan.Waiver": {
+            "type": "object",
+            "properties": {
+                "check": {
+                    "description": "Check is a check ID or glob pattern such as identity-01-*",
+                    "type": "string"
+                },
+                "expires": {
+                    "description": "Expires is the last day the waiver applies, as YYYY-MM-DD",
+                    "type": "string"
+                },
+                "hosts": {
+                    "description": "Hosts are the targets the waiver applies to, as hosts, host:port or glob patterns; empty means every target",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "justification": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                }
+            }
+        },
+        "targets.Auth": {
+            "type": "object",
+            "properties": {
+                "key_file": {
+                    "type": "string"
+                },
+                "method": {
+                    "description": "Method is env (default), password, private_key or key_file",
+                    "type": "string"
+                },
+                "passphrase": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "private_key": {
+                    "type": "string"
+                }
+            }
+        },
+        "targets.Target": {
+            "type": "object",
+            "properties": {
+                "auth": {
+                    "$ref": "#/definitions/targets.Auth"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "host_key": {
+                    "description": "HostKey pins the host key by its SHA256 fingerprint, e.g. SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8.\nWithout it the host key must be in the KnownHosts file, or in SSH_KNOWN_HOSTS (default ~/.ssh/known_hosts).",
+                    "type": "string"
+                },
+                "jump_host": {
+                    "description": "JumpHost is the name of the target to connect through",
+                    "type": "string"
+                },
+                "known_hosts": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "port": {
+                    "description": "Port defaults to 22",
+                    "type": "integer"
+                },
+                "role": {
+                    "description": "Role is the part the host plays in the cloud, e.g. controller, compute or storage",
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "API token or Keystone token as \"Bearer \u003ctoken\u003e\"; X-Auth-Token is also accepted",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
+    },
+    "security": [
+        {
+            "BearerAuth": []
         }
-    }
+    ]
 }`
 
 // SwaggerInfo holds exported Swagger Info so clients can modify it
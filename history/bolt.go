@@ -0,0 +1,109 @@
+// history/bolt.go
+package history
+
+import (
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// scansBucket holds the scans without their reports, so listing does not decode every report
+	scansBucket   = []byte("scans")
+	reportsBucket = []byte("reports")
+)
+
+// boltBackend stores scans in a bbolt file, which only one process can open at a time
+type boltBackend struct {
+	db *bolt.DB
+}
+
+func openBolt(path string) (backend, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{scansBucket, reportsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &boltBackend{db: db}, nil
+}
+
+func (b *boltBackend) save(s Scan) error {
+	reports, err := json.Marshal(s.Reports)
+	if err != nil {
+		return err
+	}
+	s.Reports = nil
+	meta, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(scansBucket).Put([]byte(s.ID), meta); err != nil {
+			return err
+		}
+		return tx.Bucket(reportsBucket).Put([]byte(s.ID), reports)
+	})
+}
+
+func (b *boltBackend) get(id string) (Scan, error) {
+	var s Scan
+	err := b.db.View(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(scansBucket).Get([]byte(id))
+		if meta == nil {
+			return ErrNotFound
+		}
+		if err := json.Unmarshal(meta, &s); err != nil {
+			return err
+		}
+		if reports := tx.Bucket(reportsBucket).Get([]byte(id)); reports != nil {
+			return json.Unmarshal(reports, &s.Reports)
+		}
+		return nil
+	})
+	return s, err
+}
+
+func (b *boltBackend) list() ([]Scan, error) {
+	scans := []Scan{}
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(scansBucket).ForEach(func(_, meta []byte) error {
+			var s Scan
+			if err := json.Unmarshal(meta, &s); err != nil {
+				return err
+			}
+			scans = append(scans, s)
+			return nil
+		})
+	})
+	return scans, err
+}
+
+func (b *boltBackend) delete(id string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(scansBucket).Get([]byte(id)) == nil {
+			return ErrNotFound
+		}
+		if err := tx.Bucket(scansBucket).Delete([]byte(id)); err != nil {
+			return err
+		}
+		return tx.Bucket(reportsBucket).Delete([]byte(id))
+	})
+}
+
+func (b *boltBackend) close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,251 @@
+// history/history.go
+package history
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gunh0/openstack-security-hub/scan"
+)
+
+// ErrNotFound is returned for an unknown scan ID
+var ErrNotFound = errors.New("scan not found in history")
+
+// Scan is a recorded scan with its reports
+type Scan struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+
+	// Source is where the scan was started: cli, api or job
+	Source    string   `json:"source"`
+	Targets   []string `json:"targets"`
+	Checks    []string `json:"checks,omitempty"`
+	Profile   string   `json:"profile,omitempty"`
+	Release   string   `json:"release,omitempty"`
+	Framework string   `json:"framework,omitempty"`
+
+	// Version is the version of OpenStack Security Hub that ran the scan
+	Version    string        `json:"version"`
+	StartedAt  string        `json:"started_at"`
+	FinishedAt string        `json:"finished_at"`
+	Summary    Summary       `json:"summary"`
+	Reports    []scan.Report `json:"reports,omitempty"`
+}
+
+// NewID returns a random scan ID
+func NewID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// Summary counts the targets and results of a scan
+type Summary struct {
+	Targets int `json:"targets"`
+	Pass    int `json:"pass"`
+	Fail    int `json:"fail"`
+	Error   int `json:"error"`
+	NA      int `json:"na"`
+	Skipped int `json:"skipped"`
+	Waived  int `json:"waived"`
+	Timeout int `json:"timeout"`
+}
+
+// Summarize counts the targets, results and skipped checks of reports
+func Summarize(reports []scan.Report) Summary {
+	summary := Summary{Targets: len(reports)}
+	for _, report := range reports {
+		for _, result := range report.Results {
+			switch result.Result {
+			case "[PASS]":
+				summary.Pass++
+			case "[FAIL]":
+				summary.Fail++
+			case "[ERROR]":
+				summary.Error++
+			case "[NA]":
+				summary.NA++
+			case scan.Waived:
+				summary.Waived++
+			case scan.Timeout:
+				summary.Timeout++
+			}
+		}
+		summary.Skipped += len(report.Skipped)
+	}
+	return summary
+}
+
+// backend stores scans; list returns the scans without their reports
+type backend interface {
+	save(Scan) error
+	get(id string) (Scan, error)
+	list() ([]Scan, error)
+	delete(id string) error
+	close() error
+}
+
+// Retention limits how long and how many scans are kept; zero values keep everything
+type Retention struct {
+	MaxAge   time.Duration
+	MaxScans int
+}
+
+// Config selects the storage backend and retention policy of the history
+type Config struct {
+	// Driver is sqlite (default), bolt or none to disable the history
+	Driver    string
+	Path      string
+	Retention Retention
+}
+
+// ConfigFromEnv reads HISTORY_DRIVER, HISTORY_PATH, HISTORY_MAX_AGE (e.g. 720h or 90d) and HISTORY_MAX_SCANS
+func ConfigFromEnv() (Config, error) {
+	config := Config{Driver: os.Getenv("HISTORY_DRIVER"), Path: os.Getenv("HISTORY_PATH")}
+	if config.Driver == "" {
+		config.Driver = "sqlite"
+	}
+	if config.Path == "" {
+		config.Path = "security-hub.db"
+	}
+
+	if value := os.Getenv("HISTORY_MAX_AGE"); value != "" {
+		age, err := ParseAge(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid HISTORY_MAX_AGE: %v", err)
+		}
+		config.Retention.MaxAge = age
+	}
+	if value := os.Getenv("HISTORY_MAX_SCANS"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return config, fmt.Errorf("invalid HISTORY_MAX_SCANS %q", value)
+		}
+		config.Retention.MaxScans = n
+	}
+	return config, nil
+}
+
+// ParseAge parses a duration such as 720h, also accepting a number of days such as 90d
+func ParseAge(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid number of days %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// History records scans in a storage backend and applies the retention policy
+type History struct {
+	backend   backend
+	retention Retention
+}
+
+// Open opens the history selected by config, or returns nil when the history is disabled
+func Open(config Config) (*History, error) {
+	var (
+		b   backend
+		err error
+	)
+	switch config.Driver {
+	case "none":
+		return nil, nil
+	case "sqlite":
+		b, err = openSQLite(config.Path)
+	case "bolt":
+		b, err = openBolt(config.Path)
+	default:
+		return nil, fmt.Errorf("unknown history driver %q (use sqlite, bolt or none)", config.Driver)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open scan history %s: %v", config.Path, err)
+	}
+	return &History{backend: b, retention: config.Retention}, nil
+}
+
+// Save records s, computing its summary, then removes the scans past retention. s is recorded
+// when Save returns nil; a failure to remove old scans is only logged.
+func (h *History) Save(s Scan) error {
+	s.Summary = Summarize(s.Reports)
+	if s.Version == "" {
+		s.Version = scan.Version
+	}
+	if err := h.backend.save(s); err != nil {
+		return err
+	}
+	if _, err := h.Prune(); err != nil {
+		log.Printf("[ERROR] Failed to remove the scans past retention from the history: %v", err)
+	}
+	return nil
+}
+
+// Get returns the scan with the given ID and its reports
+func (h *History) Get(id string) (Scan, error) {
+	return h.backend.get(id)
+}
+
+// List returns every recorded scan without its reports, newest first
+func (h *History) List() ([]Scan, error) {
+	scans, err := h.backend.list()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(scans, func(i, j int) bool {
+		if scans[i].StartedAt != scans[j].StartedAt {
+			return scans[i].StartedAt > scans[j].StartedAt
+		}
+		return scans[i].FinishedAt > scans[j].FinishedAt
+	})
+	return scans, nil
+}
+
+// Delete removes the scan with the given ID
+func (h *History) Delete(id string) error {
+	return h.backend.delete(id)
+}
+
+// Prune removes the scans older than the maximum age and those beyond the maximum number of scans,
+// returning the IDs of the removed scans
+func (h *History) Prune() ([]string, error) {
+	if h.retention.MaxAge == 0 && h.retention.MaxScans == 0 {
+		return nil, nil
+	}
+
+	scans, err := h.List()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().UTC().Add(-h.retention.MaxAge).Format(time.RFC3339)
+	var removed []string
+	for i, s := range scans {
+		expired := h.retention.MaxAge > 0 && s.StartedAt < cutoff
+		excess := h.retention.MaxScans > 0 && i >= h.retention.MaxScans
+		if !expired && !excess {
+			continue
+		}
+		if err := h.backend.delete(s.ID); err != nil {
+			return removed, err
+		}
+		removed = append(removed, s.ID)
+	}
+	return removed, nil
+}
+
+// Close closes the storage backend
+func (h *History) Close() error {
+	return h.backend.close()
+}
@@ -0,0 +1,109 @@
+// history/sqlite.go
+package history
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchema keeps the scan metadata and the reports, with every check result, as JSON documents
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS scans (
+	id          TEXT PRIMARY KEY,
+	started_at  TEXT NOT NULL,
+	finished_at TEXT NOT NULL,
+	meta        TEXT NOT NULL,
+	reports     TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS scans_started_at ON scans (started_at);
+`
+
+// sqliteBackend stores scans in a SQLite database, which the server and the CLI can share
+type sqliteBackend struct {
+	db *sql.DB
+}
+
+func openSQLite(path string) (backend, error) {
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &sqliteBackend{db: db}, nil
+}
+
+func (b *sqliteBackend) save(s Scan) error {
+	reports, err := json.Marshal(s.Reports)
+	if err != nil {
+		return err
+	}
+	s.Reports = nil
+	meta, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.db.Exec(`INSERT OR REPLACE INTO scans (id, started_at, finished_at, meta, reports) VALUES (?, ?, ?, ?, ?)`,
+		s.ID, s.StartedAt, s.FinishedAt, string(meta), string(reports))
+	return err
+}
+
+func (b *sqliteBackend) get(id string) (Scan, error) {
+	var meta, reports string
+	err := b.db.QueryRow(`SELECT meta, reports FROM scans WHERE id = ?`, id).Scan(&meta, &reports)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Scan{}, ErrNotFound
+	}
+	if err != nil {
+		return Scan{}, err
+	}
+
+	var s Scan
+	if err := json.Unmarshal([]byte(meta), &s); err != nil {
+		return Scan{}, err
+	}
+	return s, json.Unmarshal([]byte(reports), &s.Reports)
+}
+
+func (b *sqliteBackend) list() ([]Scan, error) {
+	rows, err := b.db.Query(`SELECT meta FROM scans`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	scans := []Scan{}
+	for rows.Next() {
+		var meta string
+		if err := rows.Scan(&meta); err != nil {
+			return nil, err
+		}
+		var s Scan
+		if err := json.Unmarshal([]byte(meta), &s); err != nil {
+			return nil, err
+		}
+		scans = append(scans, s)
+	}
+	return scans, rows.Err()
+}
+
+func (b *sqliteBackend) delete(id string) error {
+	result, err := b.db.Exec(`DELETE FROM scans WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (b *sqliteBackend) close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,575 @@
+// jobs/jobs.go
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gunh0/openstack-security-hub/history"
+	"github.com/gunh0/openstack-security-hub/scan"
+)
+
+// Job statuses
+const (
+	Queued    = "queued"
+	Running   = "running"
+	Completed = "completed"
+	Cancelled = "cancelled"
+)
+
+var (
+	// ErrNotFound is returned for an unknown job ID
+	ErrNotFound = errors.New("scan job not found")
+
+	// ErrQueueFull is returned when the queue cannot take another job
+	ErrQueueFull = errors.New("scan queue is full, retry later")
+
+	// ErrFinished is returned when cancelling a job that has already finished
+	ErrFinished = errors.New("scan job has already finished")
+
+	// ErrActive is returned when deleting a job that is queued or running
+	ErrActive = errors.New("scan job is still queued or running, cancel it first")
+
+	// ErrShuttingDown is returned when submitting a job after Shutdown
+	ErrShuttingDown = errors.New("server is shutting down, retry later")
+)
+
+// Request describes the scan run by a job
+type Request struct {
+	// Targets are the names of the registered targets to scan, or SSH_HOST
+	Targets []string `json:"targets"`
+
+	// Checks limits the scan to the checks matching these IDs or glob patterns
+	Checks []string `json:"checks,omitempty"`
+
+	// Profile is the name of the profile overriding expected values, enablement and severity
+	Profile string `json:"profile,omitempty"`
+
+	// Release overrides the detected release (e.g. 2024.1)
+	Release string `json:"release,omitempty"`
+
+	// Framework limits the scan to the checks mapped to a compliance framework
+	Framework string `json:"framework,omitempty"`
+
+	// NoDiscovery runs every host check without service discovery
+	NoDiscovery bool `json:"no_discovery,omitempty"`
+}
+
+// Progress counts the targets and checks a job has finished
+type Progress struct {
+	Targets     int `json:"targets"`
+	TargetsDone int `json:"targets_done"`
+	Results     int `json:"results"`
+	Skipped     int `json:"skipped"`
+	Failed      int `json:"failed"`
+}
+
+// StatusEvent is the type of the events reporting a change of the job status.
+// The last event of a job reports its final status and progress.
+const StatusEvent = "status"
+
+// Event is a scan event of a job, numbered from 1 in the order the events happened
+type Event struct {
+	ID int `json:"id"`
+	scan.Event
+
+	// Status and Progress are set on status events
+	Status   string    `json:"status,omitempty"`
+	Progress *Progress `json:"progress,omitempty"`
+}
+
+// Job is a snapshot of a scan job
+type Job struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+
+	// Source and Version are set on scans read from the history
+	Source     string        `json:"source,omitempty"`
+	Version    string        `json:"version,omitempty"`
+	Request    Request       `json:"request"`
+	Progress   Progress      `json:"progress"`
+	CreatedAt  string        `json:"created_at"`
+	StartedAt  string        `json:"started_at,omitempty"`
+	FinishedAt string        `json:"finished_at,omitempty"`
+	Reports    []scan.Report `json:"reports,omitempty"`
+}
+
+// job is the state of a queued or running scan, guarded by the manager mutex
+type job struct {
+	Job
+	opts   scan.Options
+	cancel context.CancelFunc
+
+	// seq orders jobs by submission
+	seq int
+
+	// events is the event log of the job; changed is closed and replaced whenever an event is added
+	events  []Event
+	changed chan struct{}
+
+	// recorded is set once the finished job is saved in the history
+	recorded bool
+}
+
+// finishedRetention is how long a finished job recorded in the history stays in memory, so that
+// clients following its events keep reading the same numbered event log
+const finishedRetention = 10 * time.Minute
+
+// unrecordedRetention is how long a finished job that could not be recorded in the history stays in memory
+const unrecordedRetention = 24 * time.Hour
+
+// Manager queues scan jobs and runs them in a fixed pool of workers.
+// With a history, finished jobs are moved to the history and served from there once
+// finishedRetention has passed.
+type Manager struct {
+	mu      sync.Mutex
+	jobs    map[string]*job
+	queue   chan *job
+	seq     int
+	history *history.History
+
+	// closing is set by Shutdown; running counts the jobs being run by the workers
+	closing bool
+	running sync.WaitGroup
+}
+
+// NewManager starts workers that run up to queueSize queued jobs, workers at a time.
+// h may be nil to keep finished jobs in memory only.
+func NewManager(workers, queueSize int, h *history.History) *Manager {
+	m := &Manager{
+		jobs:    map[string]*job{},
+		queue:   make(chan *job, queueSize),
+		history: h,
+	}
+	for i := 0; i < workers; i++ {
+		go m.work()
+	}
+	return m
+}
+
+// Submit queues a scan of request run with opts and returns the queued job
+func (m *Manager) Submit(request Request, opts scan.Options) (Job, error) {
+	j := &job{
+		Job: Job{
+			ID:        history.NewID(),
+			Status:    Queued,
+			Request:   request,
+			Progress:  Progress{Targets: len(request.Targets)},
+			CreatedAt: now(),
+		},
+		opts:    opts,
+		changed: make(chan struct{}),
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closing {
+		return Job{}, ErrShuttingDown
+	}
+	select {
+	case m.queue <- j:
+	default:
+		return Job{}, ErrQueueFull
+	}
+	m.seq++
+	j.seq = m.seq
+	m.jobs[j.ID] = j
+	j.publishStatus()
+	return j.snapshot(), nil
+}
+
+// Get returns the job with the given ID, or the scan with that ID from the history
+func (m *Manager) Get(id string) (Job, error) {
+	m.mu.Lock()
+	j, ok := m.jobs[id]
+	if ok {
+		defer m.mu.Unlock()
+		return j.snapshot(), nil
+	}
+	m.mu.Unlock()
+
+	s, err := m.getHistory(id)
+	if err != nil {
+		return Job{}, err
+	}
+	return fromHistory(s), nil
+}
+
+// List returns the jobs in memory, newest first, followed by the other scans of the history, without
+// their reports
+func (m *Manager) List() ([]Job, error) {
+	m.mu.Lock()
+	all := make([]*job, 0, len(m.jobs))
+	inMemory := map[string]bool{}
+	for _, j := range m.jobs {
+		all = append(all, j)
+		inMemory[j.ID] = true
+	}
+	sort.Slice(all, func(i, k int) bool {
+		return all[i].seq > all[k].seq
+	})
+
+	list := make([]Job, 0, len(all))
+	for _, j := range all {
+		snapshot := j.Job
+		snapshot.Reports = nil
+		list = append(list, snapshot)
+	}
+	m.mu.Unlock()
+
+	if m.history == nil {
+		return list, nil
+	}
+	scans, err := m.history.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range scans {
+		// Finished jobs stay in memory for a while after being recorded
+		if !inMemory[s.ID] {
+			list = append(list, fromHistory(s))
+		}
+	}
+	return list, nil
+}
+
+// Delete removes a finished job, from memory and from the history
+func (m *Manager) Delete(id string) error {
+	m.mu.Lock()
+	j, ok := m.jobs[id]
+	if ok && !j.finished() {
+		m.mu.Unlock()
+		return ErrActive
+	}
+	if ok {
+		delete(m.jobs, id)
+	}
+	recorded := ok && j.recorded
+	m.mu.Unlock()
+	if ok && !recorded {
+		return nil
+	}
+
+	if m.history == nil {
+		return ErrNotFound
+	}
+	err := m.history.Delete(id)
+	if errors.Is(err, history.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// Events returns the events of a job after the event numbered after, a channel closed when
+// another event is added, and whether the job has finished so no events will follow
+func (m *Manager) Events(id string, after int) ([]Event, <-chan struct{}, bool, error) {
+	m.mu.Lock()
+	j, ok := m.jobs[id]
+	m.mu.Unlock()
+	if !ok {
+		s, err := m.getHistory(id)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		// Jobs moved to the history replay the events recorded in their reports
+		j = replay(fromHistory(s))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if after < 0 || after > len(j.events) {
+		after = 0
+	}
+	events := append([]Event(nil), j.events[after:]...)
+	return events, j.changed, j.finished(), nil
+}
+
+// Cancel stops a queued or running job. A running job stops its current check
+// and keeps the reports of the targets scanned so far.
+func (m *Manager) Cancel(id string) (Job, error) {
+	m.mu.Lock()
+	j, ok := m.jobs[id]
+	if !ok {
+		m.mu.Unlock()
+		if _, err := m.getHistory(id); err != nil {
+			return Job{}, err
+		}
+		return Job{}, ErrFinished
+	}
+
+	switch j.Status {
+	case Queued:
+		s := m.cancelQueued(j)
+		snapshot := j.snapshot()
+		m.mu.Unlock()
+		m.record(j, s)
+		return snapshot, nil
+	case Running:
+		j.cancel()
+		defer m.mu.Unlock()
+		return j.snapshot(), nil
+	default:
+		defer m.mu.Unlock()
+		return j.snapshot(), ErrFinished
+	}
+}
+
+// Shutdown stops starting jobs, cancels the queued ones and waits for the running ones to finish.
+// When ctx is done first the running jobs are cancelled: they stop their current check and are
+// recorded as cancelled with the targets scanned so far.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	m.closing = true
+	cancelled := map[*job]history.Scan{}
+	for _, j := range m.jobs {
+		if j.Status == Queued {
+			cancelled[j] = m.cancelQueued(j)
+		}
+	}
+	m.mu.Unlock()
+	for j, s := range cancelled {
+		m.record(j, s)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		m.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	m.mu.Lock()
+	for _, j := range m.jobs {
+		if j.Status == Running {
+			j.cancel()
+		}
+	}
+	m.mu.Unlock()
+	<-done
+	return ctx.Err()
+}
+
+func (m *Manager) work() {
+	for j := range m.queue {
+		m.run(j)
+	}
+}
+
+// run scans the targets of j unless it was cancelled while queued
+func (m *Manager) run(j *job) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m.mu.Lock()
+	if j.Status == Cancelled {
+		m.mu.Unlock()
+		return
+	}
+	m.running.Add(1)
+	defer m.running.Done()
+	j.Status = Running
+	j.StartedAt = now()
+	j.cancel = cancel
+	j.publishStatus()
+	m.mu.Unlock()
+
+	opts := j.opts
+	opts.Progress = func(event scan.Event) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		j.count(event)
+		j.publish(Event{Event: event})
+	}
+	reports := scan.Run(ctx, j.Request.Targets, opts)
+
+	m.mu.Lock()
+	j.Reports = reports
+	j.Status = Completed
+	if ctx.Err() != nil {
+		j.Status = Cancelled
+	}
+	j.FinishedAt = now()
+	j.publishStatus()
+	s := historyScan(j)
+	m.mu.Unlock()
+	m.record(j, s)
+}
+
+// cancelQueued cancels a job that has not started and returns its history record; the worker
+// dequeuing it skips it. The manager mutex must be held.
+func (m *Manager) cancelQueued(j *job) history.Scan {
+	j.Status = Cancelled
+	j.FinishedAt = now()
+	j.publishStatus()
+	return historyScan(j)
+}
+
+// record saves s, the history record of the finished job j, and removes j from memory once
+// finishedRetention has passed, or unrecordedRetention when it could not be saved. The manager
+// mutex must not be held, so that a slow history does not block the other jobs.
+func (m *Manager) record(j *job, s history.Scan) {
+	if m.history == nil {
+		return
+	}
+
+	retention := finishedRetention
+	if err := m.history.Save(s); err != nil {
+		log.Printf("[ERROR] Failed to record scan job %s in the history: %v", j.ID, err)
+		retention = unrecordedRetention
+	} else {
+		m.mu.Lock()
+		deleted := m.jobs[j.ID] != j
+		j.recorded = true
+		m.mu.Unlock()
+		if deleted {
+			// The job was deleted from memory while being saved
+			if err := m.history.Delete(j.ID); err != nil {
+				log.Printf("[ERROR] Failed to delete scan job %s from the history: %v", j.ID, err)
+			}
+			return
+		}
+	}
+
+	time.AfterFunc(retention, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if m.jobs[j.ID] == j {
+			delete(m.jobs, j.ID)
+		}
+	})
+}
+
+// historyScan returns the history record of a finished job. The manager mutex must be held.
+func historyScan(j *job) history.Scan {
+	startedAt := j.StartedAt
+	if startedAt == "" {
+		// Jobs cancelled while queued never started
+		startedAt = j.CreatedAt
+	}
+	return history.Scan{
+		ID:         j.ID,
+		Status:     j.Status,
+		Source:     "job",
+		Targets:    j.Request.Targets,
+		Checks:     j.Request.Checks,
+		Profile:    j.Request.Profile,
+		Release:    j.opts.Release,
+		Framework:  j.Request.Framework,
+		StartedAt:  startedAt,
+		FinishedAt: j.FinishedAt,
+		Reports:    append([]scan.Report(nil), j.Reports...),
+	}
+}
+
+// getHistory returns the scan with the given ID from the history
+func (m *Manager) getHistory(id string) (history.Scan, error) {
+	if m.history == nil {
+		return history.Scan{}, ErrNotFound
+	}
+	s, err := m.history.Get(id)
+	if errors.Is(err, history.ErrNotFound) {
+		return s, ErrNotFound
+	}
+	return s, err
+}
+
+// fromHistory returns a recorded scan as a finished job
+func fromHistory(s history.Scan) Job {
+	return Job{
+		ID:      s.ID,
+		Status:  s.Status,
+		Source:  s.Source,
+		Version: s.Version,
+		Request: Request{
+			Targets:   s.Targets,
+			Checks:    s.Checks,
+			Profile:   s.Profile,
+			Release:   s.Release,
+			Framework: s.Framework,
+		},
+		Progress: Progress{
+			Targets:     s.Summary.Targets,
+			TargetsDone: s.Summary.Targets,
+			Results:     s.Summary.Pass + s.Summary.Fail + s.Summary.Error + s.Summary.NA + s.Summary.Waived + s.Summary.Timeout,
+			Skipped:     s.Summary.Skipped,
+			Failed:      s.Summary.Fail,
+		},
+		CreatedAt:  s.StartedAt,
+		StartedAt:  s.StartedAt,
+		FinishedAt: s.FinishedAt,
+		Reports:    s.Reports,
+	}
+}
+
+// replay rebuilds the event log of a finished job from its reports
+func replay(finished Job) *job {
+	j := &job{Job: finished, changed: make(chan struct{})}
+	for _, report := range finished.Reports {
+		for i := range report.Results {
+			j.publish(Event{Event: scan.Event{Type: scan.EventResult, Target: report.Target, Result: &report.Results[i]}})
+		}
+		for i := range report.Skipped {
+			j.publish(Event{Event: scan.Event{Type: scan.EventSkipped, Target: report.Target, Skipped: &report.Skipped[i]}})
+		}
+		j.publish(Event{Event: scan.Event{Type: scan.EventTargetFinished, Target: report.Target}})
+	}
+	j.publishStatus()
+	return j
+}
+
+// finished reports whether the job has reached a final status
+func (j *job) finished() bool {
+	return j.Status == Completed || j.Status == Cancelled
+}
+
+// publish numbers event, adds it to the event log and wakes up the readers of the log
+func (j *job) publish(event Event) {
+	event.ID = len(j.events) + 1
+	j.events = append(j.events, event)
+	close(j.changed)
+	j.changed = make(chan struct{})
+}
+
+// publishStatus publishes the current status and progress of the job
+func (j *job) publishStatus() {
+	progress := j.Progress
+	j.publish(Event{Event: scan.Event{Type: StatusEvent}, Status: j.Status, Progress: &progress})
+}
+
+// count updates the progress of j with a scan event
+func (j *job) count(event scan.Event) {
+	switch event.Type {
+	case scan.EventResult:
+		j.Progress.Results++
+		if event.Result.Result == "[FAIL]" {
+			j.Progress.Failed++
+		}
+	case scan.EventSkipped:
+		j.Progress.Skipped++
+	case scan.EventTargetFinished:
+		if event.Target == scan.CloudTarget {
+			// The OpenStack APIs are scanned after the hosts when credentials are configured
+			j.Progress.Targets = len(j.Request.Targets) + 1
+		}
+		j.Progress.TargetsDone++
+	}
+}
+
+// snapshot copies the job for callers outside the manager mutex
+func (j *job) snapshot() Job {
+	snapshot := j.Job
+	snapshot.Reports = append([]scan.Report(nil), j.Reports...)
+	return snapshot
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,66 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gunh0/openstack-security-hub/history"
+	"github.com/gunh0/openstack-security-hub/scan"
+	"golang.org/x/crypto/ssh"
+)
+
+// unreachable fails every connection, so that jobs finish at once with a connection error
+func unreachable(ctx context.Context, target string) (*ssh.Client, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestListFinishedJobOnce(t *testing.T) {
+	h, err := history.Open(history.Config{Driver: "sqlite", Path: filepath.Join(t.TempDir(), "history.db")})
+	if err != nil {
+		t.Fatalf("history.Open: %v", err)
+	}
+	defer h.Close()
+	m := NewManager(1, 1, h)
+
+	submitted, err := m.Submit(Request{Targets: []string{"controller-1"}, Checks: []string{"identity-01-01"}}, scan.Options{
+		Checks:  []string{"identity-01-01"},
+		Connect: unreachable,
+	})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		job, err := m.Get(submitted.ID)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if job.Status == Completed {
+			if _, err := h.Get(submitted.ID); err == nil {
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s is still %s", job.ID, job.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	list, err := m.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	found := 0
+	for _, job := range list {
+		if job.ID == submitted.ID {
+			found++
+		}
+	}
+	if found != 1 {
+		t.Errorf("List returned the finished job %d times, want once: %+v", found, list)
+	}
+}
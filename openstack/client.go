@@ -0,0 +1,294 @@
+// openstack/client.go
+package openstack
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errClosed is returned by the requests of a closed client
+var errClosed = errors.New("the OpenStack client is closed")
+
+// Client is an authenticated OpenStack API client using a Keystone v3 token and its service catalog
+type Client struct {
+	HTTPClient *http.Client
+
+	// ProjectID is the project the token is scoped to
+	ProjectID string
+
+	// mu guards closed, set by Close while checks stopped at their deadline may still run
+	mu     sync.Mutex
+	closed bool
+	token  string
+
+	// ctx is cancelled by Close to end the requests still running
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	catalog  []catalogService
+	region   string
+	iface    string
+	identity string
+}
+
+type catalogService struct {
+	Type      string `json:"type"`
+	Endpoints []struct {
+		Interface string `json:"interface"`
+		Region    string `json:"region"`
+		URL       string `json:"url"`
+	} `json:"endpoints"`
+}
+
+// tokenResponse is the body returned by POST /v3/auth/tokens
+type tokenResponse struct {
+	Token struct {
+		Catalog []catalogService `json:"catalog"`
+		Project struct {
+			ID string `json:"id"`
+		} `json:"project"`
+	} `json:"token"`
+}
+
+// NewClient authenticates against Keystone and returns a client for the cloud described by cfg.
+// A custom httpClient may be passed, e.g. to reach a local fake API server; nil builds one from cfg.
+func NewClient(cfg Config, httpClient *http.Client) (*Client, error) {
+	if httpClient == nil {
+		var err error
+		httpClient, err = NewHTTPClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := &Client{
+		HTTPClient: httpClient,
+		ctx:        ctx,
+		cancel:     cancel,
+		region:     cfg.RegionName,
+		iface:      cfg.Interface,
+		identity:   versionedURL(cfg.AuthURL, "/v3"),
+	}
+
+	if err := client.authenticate(cfg); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// NewHTTPClient builds an HTTP client honouring the CA bundle and TLS verification settings
+func NewHTTPClient(cfg Config) (*http.Client, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.Insecure}
+
+	if cfg.CACert != "" {
+		pem, err := os.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{Transport: transport, Timeout: 60 * time.Second}, nil
+}
+
+// authenticate requests a project scoped token with password or application credential auth
+func (c *Client) authenticate(cfg Config) error {
+	var identity map[string]interface{}
+	if cfg.ApplicationCredentialID != "" {
+		identity = map[string]interface{}{
+			"methods": []string{"application_credential"},
+			"application_credential": map[string]interface{}{
+				"id":     cfg.ApplicationCredentialID,
+				"secret": cfg.ApplicationCredentialSecret,
+			},
+		}
+	} else {
+		identity = map[string]interface{}{
+			"methods": []string{"password"},
+			"password": map[string]interface{}{
+				"user": map[string]interface{}{
+					"name":     cfg.Username,
+					"password": cfg.Password,
+					"domain":   map[string]string{"name": cfg.UserDomainName},
+				},
+			},
+		}
+	}
+
+	auth := map[string]interface{}{"identity": identity}
+	if cfg.ApplicationCredentialID == "" && cfg.ProjectName != "" {
+		auth["scope"] = map[string]interface{}{
+			"project": map[string]interface{}{
+				"name":   cfg.ProjectName,
+				"domain": map[string]string{"name": cfg.ProjectDomainName},
+			},
+		}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"auth": auth})
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.HTTPClient.Post(c.identity+"/auth/tokens", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to authenticate: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to authenticate: %s", resp.Status)
+	}
+
+	var token tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return fmt.Errorf("failed to decode token: %v", err)
+	}
+
+	c.token = resp.Header.Get("X-Subject-Token")
+	c.catalog = token.Token.Catalog
+	c.ProjectID = token.Token.Project.ID
+
+	return nil
+}
+
+// Endpoint returns the catalog URL of the first service of the given types matching the configured
+// interface and region
+func (c *Client) Endpoint(serviceTypes ...string) (string, error) {
+	for _, serviceType := range serviceTypes {
+		for _, service := range c.catalog {
+			if service.Type != serviceType {
+				continue
+			}
+			for _, endpoint := range service.Endpoints {
+				if endpoint.Interface != c.iface {
+					continue
+				}
+				if c.region != "" && endpoint.Region != c.region {
+					continue
+				}
+				return strings.TrimSuffix(endpoint.URL, "/"), nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no %s endpoint found in the service catalog", strings.Join(serviceTypes, "/"))
+}
+
+// Get performs an authenticated GET request on an absolute URL and decodes the JSON response into out
+func (c *Client) Get(url string, out interface{}) error {
+	c.mu.Lock()
+	closed := c.closed
+	c.mu.Unlock()
+	if closed {
+		return errClosed
+	}
+
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Auth-Token", c.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("GET %s failed: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("GET %s failed: %s %s", url, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response of %s: %v", url, err)
+	}
+
+	return nil
+}
+
+// ServiceGet resolves the endpoint of a service and performs Get on path relative to it.
+// The versioned base (e.g. /v2.0 for network) is appended when the catalog URL omits it.
+func (c *Client) ServiceGet(serviceType, version, path string, out interface{}) error {
+	var endpoint string
+	var err error
+
+	switch serviceType {
+	case "identity":
+		endpoint = c.identity
+	case "volume":
+		// The block storage catalog URL already contains the version and project
+		endpoint, err = c.Endpoint("block-storage", "volumev3", "volume")
+	default:
+		endpoint, err = c.Endpoint(serviceType)
+		endpoint = versionedURL(endpoint, version)
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.Get(endpoint+path, out)
+}
+
+// Close revokes the token so it cannot be reused after the checks are done. The requests still running,
+// e.g. of a check stopped at its deadline, fail, and later requests return an error.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	closed := c.closed
+	c.closed = true
+	c.mu.Unlock()
+	if closed {
+		return nil
+	}
+	c.cancel()
+	if c.token == "" {
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodDelete, c.identity+"/auth/tokens", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Auth-Token", c.token)
+	req.Header.Set("X-Subject-Token", c.token)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %v", err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// versionedURL appends version to base unless it is already present
+func versionedURL(base, version string) string {
+	base = strings.TrimSuffix(base, "/")
+	if version == "" || strings.HasSuffix(base, version) {
+		return base
+	}
+	return base + version
+}
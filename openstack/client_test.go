@@ -0,0 +1,164 @@
+package openstack
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeKeystone serves POST/DELETE /identity/v3/auth/tokens with a catalog pointing back at the server,
+// and GET /network/v2.0/networks for authenticated requests
+func fakeKeystone(t *testing.T, revoked *string) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/identity/v3/auth/tokens":
+			var body struct {
+				Auth struct {
+					Identity struct {
+						Password struct {
+							User struct {
+								Name     string `json:"name"`
+								Password string `json:"password"`
+							} `json:"user"`
+						} `json:"password"`
+					} `json:"identity"`
+					Scope struct {
+						Project struct {
+							Name string `json:"name"`
+						} `json:"project"`
+					} `json:"scope"`
+				} `json:"auth"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("invalid token request: %v", err)
+			}
+			user := body.Auth.Identity.Password.User
+			if user.Name != "admin" || user.Password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if body.Auth.Scope.Project.Name != "admin" {
+				t.Errorf("token scoped to project %q, want admin", body.Auth.Scope.Project.Name)
+			}
+
+			w.Header().Set("X-Subject-Token", "token-1")
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"token": map[string]interface{}{
+					"project": map[string]string{"id": "project-admin"},
+					"catalog": []map[string]interface{}{
+						{"type": "network", "endpoints": []map[string]string{
+							{"interface": "internal", "region": "RegionOne", "url": "http://internal.invalid/network"},
+							{"interface": "public", "region": "RegionTwo", "url": server.URL + "/other"},
+							{"interface": "public", "region": "RegionOne", "url": server.URL + "/network/"},
+						}},
+					},
+				},
+			})
+		case r.Method == http.MethodDelete && r.URL.Path == "/identity/v3/auth/tokens":
+			*revoked = r.Header.Get("X-Subject-Token")
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet && r.URL.Path == "/network/v2.0/networks":
+			if r.Header.Get("X-Auth-Token") != "token-1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"networks": [{"id": "net-1"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testConfig(server *httptest.Server) Config {
+	return Config{
+		AuthURL:           server.URL + "/identity",
+		Username:          "admin",
+		Password:          "secret",
+		UserDomainName:    "Default",
+		ProjectName:       "admin",
+		ProjectDomainName: "Default",
+		RegionName:        "RegionOne",
+		Interface:         "public",
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	var revoked string
+	server := fakeKeystone(t, &revoked)
+
+	client, err := NewClient(testConfig(server), server.Client())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if client.ProjectID != "project-admin" {
+		t.Errorf("ProjectID = %q, want project-admin", client.ProjectID)
+	}
+
+	endpoint, err := client.Endpoint("network")
+	if err != nil {
+		t.Fatalf("Endpoint: %v", err)
+	}
+	if want := server.URL + "/network"; endpoint != want {
+		t.Errorf("Endpoint = %q, want %q (public interface of RegionOne)", endpoint, want)
+	}
+	if _, err := client.Endpoint("compute"); err == nil {
+		t.Error("Endpoint(compute) succeeded, want an error for a service missing from the catalog")
+	}
+
+	var networks struct {
+		Networks []struct {
+			ID string `json:"id"`
+		} `json:"networks"`
+	}
+	if err := client.ServiceGet("network", "/v2.0", "/networks", &networks); err != nil {
+		t.Fatalf("ServiceGet: %v", err)
+	}
+	if len(networks.Networks) != 1 || networks.Networks[0].ID != "net-1" {
+		t.Errorf("ServiceGet decoded %+v", networks)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if revoked != "token-1" {
+		t.Errorf("revoked token %q, want token-1", revoked)
+	}
+	if err := client.ServiceGet("network", "/v2.0", "/networks", &networks); !errors.Is(err, errClosed) {
+		t.Errorf("ServiceGet after Close = %v, want %v", err, errClosed)
+	}
+}
+
+func TestNewClientRejected(t *testing.T) {
+	var revoked string
+	server := fakeKeystone(t, &revoked)
+
+	cfg := testConfig(server)
+	cfg.Password = "wrong"
+	_, err := NewClient(cfg, server.Client())
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("NewClient with a wrong password returned %v, want a 401 error", err)
+	}
+}
+
+func TestVersionedURL(t *testing.T) {
+	tests := []struct {
+		base, version, want string
+	}{
+		{"http://h/network", "/v2.0", "http://h/network/v2.0"},
+		{"http://h/network/v2.0/", "/v2.0", "http://h/network/v2.0"},
+		{"http://h/identity", "", "http://h/identity"},
+	}
+	for _, test := range tests {
+		if got := versionedURL(test.base, test.version); got != test.want {
+			t.Errorf("versionedURL(%q, %q) = %q, want %q", test.base, test.version, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// openstack/config.go
+package openstack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the credentials and endpoint selection used to talk to the OpenStack APIs
+type Config struct {
+	AuthURL                     string
+	Username                    string
+	Password                    string
+	UserDomainName              string
+	ProjectName                 string
+	ProjectDomainName           string
+	ApplicationCredentialID     string
+	ApplicationCredentialSecret string
+	RegionName                  string
+	Interface                   string
+	CACert                      string
+	Insecure                    bool
+}
+
+// cloudsFile is the subset of clouds.yaml understood by LoadConfig
+type cloudsFile struct {
+	Clouds map[string]struct {
+		Auth struct {
+			AuthURL                     string `yaml:"auth_url"`
+			Username                    string `yaml:"username"`
+			Password                    string `yaml:"password"`
+			UserDomainName              string `yaml:"user_domain_name"`
+			ProjectName                 string `yaml:"project_name"`
+			ProjectDomainName           string `yaml:"project_domain_name"`
+			ApplicationCredentialID     string `yaml:"application_credential_id"`
+			ApplicationCredentialSecret string `yaml:"application_credential_secret"`
+		} `yaml:"auth"`
+		RegionName string `yaml:"region_name"`
+		Interface  string `yaml:"interface"`
+		CACert     string `yaml:"cacert"`
+		Verify     *bool  `yaml:"verify"`
+	} `yaml:"clouds"`
+}
+
+// cloudsSearchPath lists clouds.yaml locations in the order used by the OpenStack client
+func cloudsSearchPath() []string {
+	paths := []string{}
+	if file := os.Getenv("OS_CLIENT_CONFIG_FILE"); file != "" {
+		paths = append(paths, file)
+	}
+	paths = append(paths, "clouds.yaml")
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, ".config", "openstack", "clouds.yaml"))
+	}
+	return append(paths, "/etc/openstack/clouds.yaml")
+}
+
+// LoadConfig returns the API configuration from clouds.yaml when OS_CLOUD is set,
+// otherwise from the OS_* environment variables. Environment variables override clouds.yaml values.
+func LoadConfig() (Config, error) {
+	cfg := Config{
+		UserDomainName:    "Default",
+		ProjectDomainName: "Default",
+		Interface:         "public",
+	}
+
+	if name := os.Getenv("OS_CLOUD"); name != "" {
+		found := false
+		for _, path := range cloudsSearchPath() {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+
+			var clouds cloudsFile
+			if err := yaml.Unmarshal(content, &clouds); err != nil {
+				return cfg, fmt.Errorf("failed to parse %s: %v", path, err)
+			}
+			cloud, ok := clouds.Clouds[name]
+			if !ok {
+				continue
+			}
+
+			found = true
+			cfg.AuthURL = cloud.Auth.AuthURL
+			cfg.Username = cloud.Auth.Username
+			cfg.Password = cloud.Auth.Password
+			cfg.ApplicationCredentialID = cloud.Auth.ApplicationCredentialID
+			cfg.ApplicationCredentialSecret = cloud.Auth.ApplicationCredentialSecret
+			cfg.ProjectName = cloud.Auth.ProjectName
+			setIfNotEmpty(&cfg.UserDomainName, cloud.Auth.UserDomainName)
+			setIfNotEmpty(&cfg.ProjectDomainName, cloud.Auth.ProjectDomainName)
+			setIfNotEmpty(&cfg.Interface, cloud.Interface)
+			cfg.RegionName = cloud.RegionName
+			cfg.CACert = cloud.CACert
+			if cloud.Verify != nil {
+				cfg.Insecure = !*cloud.Verify
+			}
+			break
+		}
+		if !found {
+			return cfg, fmt.Errorf("cloud %q not found in clouds.yaml", name)
+		}
+	}
+
+	setIfNotEmpty(&cfg.AuthURL, os.Getenv("OS_AUTH_URL"))
+	setIfNotEmpty(&cfg.Username, os.Getenv("OS_USERNAME"))
+	setIfNotEmpty(&cfg.Password, os.Getenv("OS_PASSWORD"))
+	setIfNotEmpty(&cfg.UserDomainName, os.Getenv("OS_USER_DOMAIN_NAME"))
+	setIfNotEmpty(&cfg.ProjectName, os.Getenv("OS_PROJECT_NAME"))
+	setIfNotEmpty(&cfg.ProjectDomainName, os.Getenv("OS_PROJECT_DOMAIN_NAME"))
+	setIfNotEmpty(&cfg.ApplicationCredentialID, os.Getenv("OS_APPLICATION_CREDENTIAL_ID"))
+	setIfNotEmpty(&cfg.ApplicationCredentialSecret, os.Getenv("OS_APPLICATION_CREDENTIAL_SECRET"))
+	setIfNotEmpty(&cfg.RegionName, os.Getenv("OS_REGION_NAME"))
+	setIfNotEmpty(&cfg.Interface, os.Getenv("OS_INTERFACE"))
+	setIfNotEmpty(&cfg.CACert, os.Getenv("OS_CACERT"))
+	if insecure, err := strconv.ParseBool(os.Getenv("OS_INSECURE")); err == nil {
+		cfg.Insecure = insecure
+	}
+
+	if cfg.AuthURL == "" {
+		return cfg, fmt.Errorf("no OpenStack credentials configured: set OS_CLOUD or OS_AUTH_URL")
+	}
+
+	return cfg, nil
+}
+
+func setIfNotEmpty(target *string, value string) {
+	if value != "" {
+		*target = value
+	}
+}
@@ -0,0 +1,108 @@
+// output/controls.go
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gunh0/openstack-security-hub/scan"
+)
+
+// Control rolls up the results of the checks mapped to one framework control
+type Control struct {
+	ID      string   `json:"id"`
+	Status  string   `json:"status"`
+	Checks  []string `json:"checks"`
+	Pass    int      `json:"pass"`
+	Fail    int      `json:"fail"`
+	Error   int      `json:"error"`
+	NA      int      `json:"na"`
+	Skipped int      `json:"skipped"`
+	Waived  int      `json:"waived"`
+
+	// Coverage is the percentage of evaluated results that passed; NA and skipped results are left out
+	Coverage int `json:"coverage"`
+}
+
+// Controls rolls records up per control, in natural control order
+func Controls(records []Record) []Control {
+	controls := map[string]*Control{}
+	checks := map[string]map[string]bool{}
+
+	for _, record := range records {
+		for _, id := range record.Controls {
+			control, ok := controls[id]
+			if !ok {
+				control = &Control{ID: id}
+				controls[id] = control
+				checks[id] = map[string]bool{}
+			}
+			if !checks[id][record.ID] {
+				checks[id][record.ID] = true
+				control.Checks = append(control.Checks, record.ID)
+			}
+
+			switch record.Result {
+			case "[PASS]":
+				control.Pass++
+			case "[FAIL]":
+				control.Fail++
+			case "[ERROR]", scan.Timeout:
+				control.Error++
+			case "[NA]":
+				control.NA++
+			case Skipped:
+				control.Skipped++
+			case scan.Waived:
+				control.Waived++
+			}
+		}
+	}
+
+	list := make([]Control, 0, len(controls))
+	for _, control := range controls {
+		evaluated := control.Pass + control.Fail + control.Error
+		switch {
+		case control.Fail > 0:
+			control.Status = "FAIL"
+		case control.Error > 0:
+			control.Status = "ERROR"
+		case control.Pass > 0:
+			control.Status = "PASS"
+		default:
+			control.Status = "NA"
+		}
+		if evaluated > 0 {
+			control.Coverage = control.Pass * 100 / evaluated
+		}
+		list = append(list, *control)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return scan.CompareControls(list[i].ID, list[j].ID) < 0
+	})
+	return list
+}
+
+// writeControls prints the per control rollup as a table
+func writeControls(w io.Writer, records []Record) error {
+	controls := Controls(records)
+	if len(controls) == 0 {
+		return fmt.Errorf("no results are mapped to controls; select a framework with --framework")
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "CONTROL\tSTATUS\tCOVERAGE\tPASS\tFAIL\tERROR\tN/A\tSKIPPED\tWAIVED\tCHECKS")
+	for _, control := range controls {
+		coverage := "n/a"
+		if control.Pass+control.Fail+control.Error > 0 {
+			coverage = fmt.Sprintf("%d%%", control.Coverage)
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%s\n",
+			control.ID, control.Status, coverage, control.Pass, control.Fail, control.Error, control.NA, control.Skipped, control.Waived, strings.Join(control.Checks, " "))
+	}
+	return table.Flush()
+}
@@ -0,0 +1,322 @@
+// output/html.go
+package output
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gunh0/openstack-security-hub/scan"
+)
+
+// htmlReport is the data rendered by the HTML template
+type htmlReport struct {
+	Generated  string
+	Targets    []string
+	StartedAt  string
+	FinishedAt string
+	Summary    htmlRate
+	Severities []htmlCount
+	Services   []htmlRate
+	Hosts      []htmlRate
+	Framework  string
+	Controls   []Control
+	Findings   []htmlFinding
+	Checks     []htmlCheck
+}
+
+// htmlRate counts the results of a group of checks
+type htmlRate struct {
+	Name    string
+	Pass    int
+	Fail    int
+	Error   int
+	NA      int
+	Skipped int
+	Waived  int
+}
+
+// Evaluated is the number of results counted in the pass rate; NA, skipped and waived checks are left out
+func (r htmlRate) Evaluated() int {
+	return r.Pass + r.Fail + r.Error
+}
+
+// Rate is the percentage of evaluated checks that passed, shown as n/a when nothing was evaluated
+func (r htmlRate) Rate() int {
+	if r.Evaluated() == 0 {
+		return 0
+	}
+	return r.Pass * 100 / r.Evaluated()
+}
+
+type htmlCount struct {
+	Severity string
+	Count    int
+}
+
+type htmlFinding struct {
+	Record
+	Rank int
+}
+
+type htmlCheck struct {
+	ID          string
+	Service     string
+	Description string
+	Severity    string
+	Rationale   string
+	Remediation string
+}
+
+// WriteHTML renders scan reports as a self-contained HTML compliance report
+func WriteHTML(w io.Writer, reports []scan.Report) error {
+	report := htmlReport{Generated: time.Now().UTC().Format(time.RFC3339), Summary: htmlRate{Name: "All checks"}}
+	var services, hosts rates
+	failures := map[string]int{}
+	seen := map[string]bool{}
+
+	for _, r := range reports {
+		report.Targets = append(report.Targets, r.Target)
+		if report.StartedAt == "" || r.StartedAt < report.StartedAt {
+			report.StartedAt = r.StartedAt
+		}
+		if r.FinishedAt > report.FinishedAt {
+			report.FinishedAt = r.FinishedAt
+		}
+		if r.Framework != "" {
+			if framework, err := scan.LookupFramework(r.Framework); err == nil {
+				report.Framework = framework.Title
+			}
+		}
+	}
+
+	records := Records(reports)
+	report.Controls = Controls(records)
+	for _, record := range records {
+		for _, rate := range []*htmlRate{&report.Summary, services.get(record.Service), hosts.get(record.Target)} {
+			count(rate, record.Result)
+		}
+
+		if record.Result == "[FAIL]" || record.Result == "[ERROR]" || record.Result == scan.Timeout {
+			if record.Result == "[FAIL]" {
+				failures[record.Severity]++
+			}
+			report.Findings = append(report.Findings, htmlFinding{Record: record, Rank: severityRank(record.Severity)})
+		}
+
+		if check, ok := scan.Lookup(record.ID); ok && !seen[check.ID] {
+			seen[check.ID] = true
+			severity := record.Severity
+			if severity == "" {
+				severity = (*scan.Profile)(nil).Severity(check)
+			}
+			report.Checks = append(report.Checks, htmlCheck{
+				ID:          check.ID,
+				Service:     check.Service,
+				Description: check.Description,
+				Severity:    severity,
+				Rationale:   check.Rationale(),
+				Remediation: check.Remediation(),
+			})
+		}
+	}
+
+	report.Services = services.list()
+	report.Hosts = hosts.list()
+
+	for i := len(scan.Severities) - 1; i >= 0; i-- {
+		severity := scan.Severities[i]
+		report.Severities = append(report.Severities, htmlCount{Severity: severity, Count: failures[severity]})
+	}
+
+	// Most severe findings first, then by host and check
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Rank != b.Rank {
+			return a.Rank > b.Rank
+		}
+		if a.Target != b.Target {
+			return a.Target < b.Target
+		}
+		return a.ID < b.ID
+	})
+
+	return htmlTemplate.Execute(w, report)
+}
+
+// rates counts results per group name, keeping the order in which groups first appear
+type rates struct {
+	names  []string
+	counts map[string]*htmlRate
+}
+
+func (r *rates) get(name string) *htmlRate {
+	if r.counts == nil {
+		r.counts = map[string]*htmlRate{}
+	}
+	if _, ok := r.counts[name]; !ok {
+		r.names = append(r.names, name)
+		r.counts[name] = &htmlRate{Name: name}
+	}
+	return r.counts[name]
+}
+
+func (r *rates) list() []htmlRate {
+	list := make([]htmlRate, 0, len(r.names))
+	for _, name := range r.names {
+		list = append(list, *r.counts[name])
+	}
+	return list
+}
+
+func count(rate *htmlRate, result string) {
+	switch result {
+	case "[PASS]":
+		rate.Pass++
+	case "[FAIL]":
+		rate.Fail++
+	case "[ERROR]", scan.Timeout:
+		rate.Error++
+	case "[NA]":
+		rate.NA++
+	case Skipped:
+		rate.Skipped++
+	case scan.Waived:
+		rate.Waived++
+	}
+}
+
+// severityRank orders severities from info (0) to critical; unknown severities rank lowest
+func severityRank(severity string) int {
+	for i, s := range scan.Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"status": func(result string) string {
+		return strings.ToLower(strings.Trim(result, "[]"))
+	},
+	"join": strings.Join,
+	"plural": func(n int, word string) string {
+		if n == 1 {
+			return fmt.Sprintf("%d %s", n, word)
+		}
+		return fmt.Sprintf("%d %ss", n, word)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>OpenStack Security Hub compliance report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 1200px; color: #222; padding: 0 1em; }
+h1 { margin-bottom: 0.2em; }
+h2 { border-bottom: 2px solid #ddd; padding-bottom: 0.2em; margin-top: 2em; }
+.meta { color: #666; }
+.cards { display: flex; flex-wrap: wrap; gap: 1em; }
+.card { border: 1px solid #ddd; border-radius: 6px; padding: 0.8em 1.2em; min-width: 8em; }
+.card .value { font-size: 2em; font-weight: bold; }
+table { border-collapse: collapse; width: 100%; margin-top: 0.5em; }
+th, td { border-bottom: 1px solid #eee; padding: 0.4em 0.6em; text-align: left; vertical-align: top; }
+th { background: #f5f5f5; }
+th.sortable { cursor: pointer; user-select: none; }
+th.sortable:after { content: " \2195"; color: #aaa; }
+.bar { background: #f0d0d0; border-radius: 3px; height: 0.8em; width: 10em; display: inline-block; vertical-align: middle; }
+.bar span { background: #4a4; border-radius: 3px; height: 100%; display: block; }
+.pass { color: #2a7a2a; } .fail { color: #b22; } .error, .timeout { color: #b60; } .na, .skipped { color: #888; } .waived { color: #76a; }
+.sev { font-weight: bold; text-transform: uppercase; font-size: 0.85em; }
+.sev-critical { color: #800; } .sev-high { color: #c22; } .sev-medium { color: #c70; } .sev-low { color: #47a; } .sev-info { color: #777; }
+pre { white-space: pre-wrap; margin: 0; font-size: 0.9em; }
+.check { margin-bottom: 1.5em; }
+.check h3 { margin-bottom: 0.3em; }
+</style>
+</head>
+<body>
+<h1>OpenStack Security Hub compliance report</h1>
+<p class="meta">Generated {{.Generated}}{{if .StartedAt}} from a scan started {{.StartedAt}} and finished {{.FinishedAt}}{{end}}. Targets: {{join .Targets ", "}}.</p>
+
+<h2>Executive summary</h2>
+<div class="cards">
+  <div class="card"><div class="value">{{if .Summary.Evaluated}}{{.Summary.Rate}}%{{else}}n/a{{end}}</div>pass rate</div>
+  <div class="card"><div class="value pass">{{.Summary.Pass}}</div>passed</div>
+  <div class="card"><div class="value fail">{{.Summary.Fail}}</div>failed</div>
+  <div class="card"><div class="value error">{{.Summary.Error}}</div>errors</div>
+  <div class="card"><div class="value na">{{.Summary.NA}}</div>not applicable</div>
+  <div class="card"><div class="value skipped">{{.Summary.Skipped}}</div>skipped</div>
+  <div class="card"><div class="value waived">{{.Summary.Waived}}</div>waived</div>
+</div>
+<p>Failures by severity:{{range .Severities}} <span class="sev sev-{{.Severity}}">{{.Severity}}</span> {{.Count}}{{end}}.
+The pass rate counts passed checks among those evaluated ({{plural .Summary.Evaluated "check"}}); not applicable, skipped and waived checks are left out.</p>
+
+<h2>Pass rate per service</h2>
+<table>
+<tr><th>Service</th><th>Pass rate</th><th>Passed</th><th>Failed</th><th>Errors</th><th>N/A</th><th>Skipped</th><th>Waived</th></tr>
+{{range .Services}}<tr><td>{{.Name}}</td><td>{{if .Evaluated}}<span class="bar"><span style="width: {{.Rate}}%"></span></span> {{.Rate}}%{{else}}n/a{{end}}</td><td>{{.Pass}}</td><td>{{.Fail}}</td><td>{{.Error}}</td><td>{{.NA}}</td><td>{{.Skipped}}</td><td>{{.Waived}}</td></tr>
+{{end}}</table>
+
+<h2>Pass rate per host</h2>
+<table>
+<tr><th>Host</th><th>Pass rate</th><th>Passed</th><th>Failed</th><th>Errors</th><th>N/A</th><th>Skipped</th><th>Waived</th></tr>
+{{range .Hosts}}<tr><td>{{.Name}}</td><td>{{if .Evaluated}}<span class="bar"><span style="width: {{.Rate}}%"></span></span> {{.Rate}}%{{else}}n/a{{end}}</td><td>{{.Pass}}</td><td>{{.Fail}}</td><td>{{.Error}}</td><td>{{.NA}}</td><td>{{.Skipped}}</td><td>{{.Waived}}</td></tr>
+{{end}}</table>
+
+{{if .Controls}}<h2>Coverage per {{.Framework}} control</h2>
+<table>
+<tr><th>Control</th><th>Status</th><th>Coverage</th><th>Passed</th><th>Failed</th><th>Errors</th><th>N/A</th><th>Skipped</th><th>Waived</th><th>Checks</th></tr>
+{{range .Controls}}<tr><td>{{.ID}}</td><td class="{{status .Status}}">{{.Status}}</td><td>{{if or .Pass .Fail .Error}}<span class="bar"><span style="width: {{.Coverage}}%"></span></span> {{.Coverage}}%{{else}}n/a{{end}}</td><td>{{.Pass}}</td><td>{{.Fail}}</td><td>{{.Error}}</td><td>{{.NA}}</td><td>{{.Skipped}}</td><td>{{.Waived}}</td><td>{{range .Checks}}<a href="#{{.}}">{{.}}</a> {{end}}</td></tr>
+{{end}}</table>
+
+{{end}}<h2>Findings</h2>
+{{if .Findings}}<p>Click a column header to sort.</p>
+<table id="findings">
+<thead><tr><th class="sortable">Severity</th><th class="sortable">Result</th><th class="sortable">Host</th><th class="sortable">Check</th><th class="sortable">Service</th><th>Description</th><th>Details</th></tr></thead>
+<tbody>
+{{range .Findings}}<tr>
+<td data-sort="{{.Rank}}"><span class="sev sev-{{.Severity}}">{{.Severity}}</span></td>
+<td class="{{status .Result}}">{{.Result}}</td>
+<td>{{.Target}}</td>
+<td><a href="#{{.ID}}">{{.ID}}</a></td>
+<td>{{.Service}}</td>
+<td>{{.Description}}</td>
+<td><pre>{{.Details}}</pre>{{with .Waiver}}<p class="waived">Waiver {{.ID}} ({{.Owner}}) expired on {{.Expires}}.</p>{{end}}{{range .Evidence}}<pre>{{.Location}}{{if .Line}}:{{.Line}}{{end}}: {{.Snippet}}</pre>{{end}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+{{else}}<p>No failed checks.</p>{{end}}
+
+<h2>Checks</h2>
+{{range .Checks}}<div class="check" id="{{.ID}}">
+<h3>{{.ID}}: {{.Description}}</h3>
+<p class="meta">{{.Service}}, severity <span class="sev sev-{{.Severity}}">{{.Severity}}</span></p>
+{{if .Rationale}}<p><strong>Rationale.</strong> {{.Rationale}}</p>{{end}}
+{{if .Remediation}}<p><strong>Remediation.</strong> {{.Remediation}}</p>{{end}}
+</div>
+{{end}}
+
+<script>
+document.querySelectorAll("#findings th.sortable").forEach(function (header, column) {
+  var descending = false;
+  header.addEventListener("click", function () {
+    var body = document.querySelector("#findings tbody");
+    var rows = Array.prototype.slice.call(body.rows);
+    descending = !descending;
+    rows.sort(function (a, b) {
+      var x = a.cells[column].getAttribute("data-sort") || a.cells[column].textContent;
+      var y = b.cells[column].getAttribute("data-sort") || b.cells[column].textContent;
+      var order = isNaN(x) || isNaN(y) ? x.localeCompare(y) : x - y;
+      return descending ? -order : order;
+    });
+    rows.forEach(function (row) { body.appendChild(row); });
+  });
+});
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,137 @@
+// output/junit.go
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gunh0/openstack-security-hub/scan"
+)
+
+// junitSuites is the root element of a JUnit XML report
+type junitSuites struct {
+	XMLName  xml.Name      `xml:"testsuites"`
+	Name     string        `xml:"name,attr"`
+	Tests    int           `xml:"tests,attr"`
+	Failures int           `xml:"failures,attr"`
+	Errors   int           `xml:"errors,attr"`
+	Skipped  int           `xml:"skipped,attr"`
+	Suites   []*junitSuite `xml:"testsuite"`
+}
+
+// junitSuite groups the checks of one service on one target
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Hostname  string      `xml:"hostname,attr,omitempty"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Timestamp string      `xml:"timestamp,attr,omitempty"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit writes records as a JUnit XML report with one testsuite per service and target.
+// FAIL results become failures, ERROR results errors, and NA, skipped or waived checks are skipped.
+func WriteJUnit(w io.Writer, records []Record) error {
+	report := junitSuites{Name: "openstack-security-hub"}
+	suites := map[string]*junitSuite{}
+
+	for _, record := range records {
+		service := record.Service
+		if service == "" {
+			service = "Checks"
+		}
+
+		key := record.Target + "\x00" + service
+		suite, ok := suites[key]
+		if !ok {
+			suite = &junitSuite{Name: service, Hostname: record.Target, Timestamp: record.Timestamp}
+			suites[key] = suite
+			report.Suites = append(report.Suites, suite)
+		}
+
+		testcase := junitCase{Name: caseName(record), Classname: className(record.Target, service)}
+		message := strings.TrimSpace(record.Details)
+		switch record.Result {
+		case "[FAIL]":
+			testcase.Failure = &junitMessage{Message: message, Type: record.Severity, Body: failureBody(record)}
+			suite.Failures++
+		case "[ERROR]", scan.Timeout:
+			testcase.Error = &junitMessage{Message: message, Body: message}
+			suite.Errors++
+		case "[NA]", Skipped:
+			testcase.Skipped = &junitMessage{Message: message}
+			suite.Skipped++
+		case scan.Waived:
+			testcase.Skipped = &junitMessage{Message: "waived: " + waiverString(record.Waiver)}
+			suite.Skipped++
+		}
+
+		suite.Cases = append(suite.Cases, testcase)
+		suite.Tests++
+	}
+
+	for _, suite := range report.Suites {
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Skipped += suite.Skipped
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// caseName names a testcase after the check ID and description
+func caseName(record Record) string {
+	if record.ID == "" {
+		return record.Description
+	}
+	return fmt.Sprintf("[%s] %s", record.ID, record.Description)
+}
+
+// className groups testcases by target and service, e.g. 172.16.0.211:22.Identity
+func className(target, service string) string {
+	if target == "" {
+		return service
+	}
+	return target + "." + service
+}
+
+// failureBody returns the details of a failure followed by its evidence
+func failureBody(record Record) string {
+	body := strings.TrimSpace(record.Details)
+	for _, e := range record.Evidence {
+		if e.Line > 0 {
+			body += fmt.Sprintf("\n%s:%d: %s", e.Location, e.Line, e.Snippet)
+		} else {
+			body += fmt.Sprintf("\n%s: %s", e.Location, e.Snippet)
+		}
+	}
+	return body
+}
@@ -0,0 +1,277 @@
+// output/output.go
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/scan"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats lists the accepted output formats; text is the human readable default
+var Formats = []string{"text", "json", "ndjson", "yaml", "csv", "table", "junit", "sarif", "xccdf", "controls"}
+
+// Record is one check result with the target and check it belongs to
+type Record struct {
+	Target   string   `json:"target,omitempty"`
+	ID       string   `json:"id,omitempty"`
+	Service  string   `json:"service,omitempty"`
+	Severity string   `json:"severity,omitempty"`
+	Controls []string `json:"controls,omitempty"`
+
+	// Waiver is the waiver of a [WAIVED] result, or the expired waiver of a failure
+	Waiver *scan.AppliedWaiver `json:"waiver,omitempty"`
+	checklist.CheckResult
+}
+
+// Skipped is the result of a check that did not apply to its target
+const Skipped = "[SKIPPED]"
+
+// csvHeader lists the CSV columns in order
+var csvHeader = []string{"target", "id", "service", "severity", "result", "description", "details", "evidence", "timestamp", "controls", "waiver"}
+
+// Validate returns an error when format is not one of Formats
+func Validate(format string) error {
+	for _, f := range Formats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown output format %q (use %s)", format, strings.Join(Formats, ", "))
+}
+
+// Records flattens scan reports into one record per result, followed by the skipped checks.
+// Records of reports with a framework are tagged with the controls of that framework.
+func Records(reports []scan.Report) []Record {
+	records := []Record{}
+	for _, report := range reports {
+		for _, result := range report.Results {
+			records = append(records, Record{
+				Target:      report.Target,
+				ID:          result.ID,
+				Service:     result.Service,
+				Severity:    result.Severity,
+				Controls:    controls(result.ID, report.Framework),
+				Waiver:      result.Waiver,
+				CheckResult: result.CheckResult,
+			})
+		}
+		for _, skipped := range report.Skipped {
+			records = append(records, Record{
+				Target:   report.Target,
+				ID:       skipped.ID,
+				Service:  skipped.Service,
+				Controls: controls(skipped.ID, report.Framework),
+				CheckResult: checklist.CheckResult{
+					Description: skipped.Description,
+					Result:      Skipped,
+					Details:     skipped.Reason,
+					Timestamp:   report.FinishedAt,
+				},
+			})
+		}
+	}
+	return records
+}
+
+// controls returns the controls of framework addressed by the check id, or nil without a framework
+func controls(id, framework string) []string {
+	if framework == "" {
+		return nil
+	}
+	if check, ok := scan.Lookup(id); ok {
+		return check.Controls(framework)
+	}
+	return nil
+}
+
+// Render writes records in a machine readable format (json, ndjson, yaml, csv, table, junit, sarif, xccdf or controls)
+func Render(w io.Writer, format string, records []Record) error {
+	switch format {
+	case "json", "yaml":
+		return Encode(w, format, records)
+	case "ndjson":
+		for _, record := range records {
+			if err := WriteLine(w, record); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		return writeCSV(w, records)
+	case "table":
+		return writeTable(w, records)
+	case "junit":
+		return WriteJUnit(w, records)
+	case "sarif":
+		return WriteSARIF(w, records)
+	case "xccdf":
+		return WriteXCCDF(w, records)
+	case "controls":
+		return writeControls(w, records)
+	}
+	return fmt.Errorf("output format %q cannot render records", format)
+}
+
+// Encode writes any value as indented JSON or YAML using its JSON field names
+func Encode(w io.Writer, format string, v interface{}) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case "yaml":
+		content, err := toYAML(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(content)
+		return err
+	}
+	return fmt.Errorf("output format %q cannot encode documents", format)
+}
+
+// WriteLine writes a value as a single JSON line, used to stream ndjson
+func WriteLine(w io.Writer, v interface{}) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// toYAML converts v through JSON so the YAML keys and omitted fields match the JSON output
+func toYAML(v interface{}) ([]byte, error) {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is valid YAML, so it parses into a node tree that keeps the field order
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		return nil, err
+	}
+	blockStyle(&node)
+
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// blockStyle drops the flow and quoting styles inherited from the JSON input
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// waiverString describes a waiver in one line for tabular formats
+func waiverString(waiver *scan.AppliedWaiver) string {
+	if waiver == nil {
+		return ""
+	}
+	if waiver.Expired {
+		return fmt.Sprintf("%s expired %s (%s)", waiver.ID, waiver.Expires, waiver.Owner)
+	}
+	return fmt.Sprintf("%s until %s (%s): %s", waiver.ID, waiver.Expires, waiver.Owner, waiver.Justification)
+}
+
+func writeCSV(w io.Writer, records []Record) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		row := []string{
+			record.Target,
+			record.ID,
+			record.Service,
+			record.Severity,
+			record.Result,
+			record.Description,
+			strings.TrimSpace(record.Details),
+			evidenceString(record.Evidence),
+			record.Timestamp,
+			strings.Join(record.Controls, " "),
+			waiverString(record.Waiver),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeTable prints an aligned summary table, leaving out columns that are empty for every record
+func writeTable(w io.Writer, records []Record) error {
+	columns := []struct {
+		name  string
+		value func(Record) string
+	}{
+		{"TARGET", func(r Record) string { return r.Target }},
+		{"ID", func(r Record) string { return r.ID }},
+		{"SEVERITY", func(r Record) string { return r.Severity }},
+		{"RESULT", func(r Record) string { return r.Result }},
+		{"DESCRIPTION", func(r Record) string { return r.Description }},
+		{"WAIVER", func(r Record) string {
+			if r.Waiver != nil && r.Waiver.Expired {
+				return r.Waiver.ID + " (expired)"
+			}
+			if r.Waiver != nil {
+				return r.Waiver.ID
+			}
+			return ""
+		}},
+	}
+
+	var headers []string
+	var values []func(Record) string
+	for _, column := range columns {
+		for _, record := range records {
+			if column.value(record) != "" {
+				headers = append(headers, column.name)
+				values = append(values, column.value)
+				break
+			}
+		}
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, strings.Join(headers, "\t"))
+	for _, record := range records {
+		row := make([]string, len(values))
+		for i, value := range values {
+			row[i] = strings.ReplaceAll(value(record), "\t", " ")
+		}
+		fmt.Fprintln(table, strings.Join(row, "\t"))
+	}
+	return table.Flush()
+}
+
+// evidenceString joins evidence as location:line: snippet entries
+func evidenceString(evidence []checklist.Evidence) string {
+	entries := make([]string, 0, len(evidence))
+	for _, e := range evidence {
+		if e.Line > 0 {
+			entries = append(entries, fmt.Sprintf("%s:%d: %s", e.Location, e.Line, e.Snippet))
+		} else {
+			entries = append(entries, fmt.Sprintf("%s: %s", e.Location, e.Snippet))
+		}
+	}
+	return strings.Join(entries, "; ")
+}
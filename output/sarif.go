@@ -0,0 +1,217 @@
+// output/sarif.go
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/gunh0/openstack-security-hub/scan"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolName     = "openstack-security-hub"
+	toolURI      = "https://github.com/gunh0/openstack-security-hub"
+)
+
+// sarifLevels maps check severities to SARIF result levels
+var sarifLevels = map[string]string{
+	"critical": "error",
+	"high":     "error",
+	"medium":   "warning",
+	"low":      "note",
+	"info":     "note",
+}
+
+// securitySeverities maps check severities to the numeric security-severity used by code scanning dashboards
+var securitySeverities = map[string]string{
+	"critical": "9.5",
+	"high":     "8.0",
+	"medium":   "5.5",
+	"low":      "3.0",
+	"info":     "0.0",
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string            `json:"id"`
+	ShortDescription     sarifText         `json:"shortDescription"`
+	FullDescription      sarifText         `json:"fullDescription"`
+	Help                 sarifText         `json:"help"`
+	DefaultConfiguration sarifRuleConfig   `json:"defaultConfiguration"`
+	Properties           sarifRuleProperty `json:"properties"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifRuleConfig struct {
+	Level string `json:"level"`
+}
+
+type sarifRuleProperty struct {
+	Tags             []string `json:"tags"`
+	Severity         string   `json:"severity"`
+	SecuritySeverity string   `json:"security-severity"`
+}
+
+type sarifResult struct {
+	RuleID       string             `json:"ruleId"`
+	RuleIndex    int                `json:"ruleIndex"`
+	Level        string             `json:"level"`
+	Message      sarifText          `json:"message"`
+	Locations    []sarifLocation    `json:"locations,omitempty"`
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+	Properties   map[string]string  `json:"properties,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+	Message          *sarifText             `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// WriteSARIF writes records as a SARIF 2.1.0 log. Every registered check is a rule and every
+// FAIL result is a SARIF result located at the remote file and line of its evidence.
+// WAIVED results are suppressed results carrying the justification of their waiver.
+func WriteSARIF(w io.Writer, records []Record) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           toolName,
+			InformationURI: toolURI,
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	ruleIndex := map[string]int{}
+	for _, check := range scan.Checks() {
+		ruleIndex[check.ID] = len(run.Tool.Driver.Rules)
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRuleFor(check))
+	}
+
+	for _, record := range records {
+		index, ok := ruleIndex[record.ID]
+		if (record.Result != "[FAIL]" && record.Result != scan.Waived) || !ok {
+			continue
+		}
+
+		severity := record.Severity
+		if _, known := sarifLevels[severity]; !known {
+			severity = run.Tool.Driver.Rules[index].Properties.Severity
+		}
+
+		result := sarifResult{
+			RuleID:    record.ID,
+			RuleIndex: index,
+			Level:     sarifLevels[severity],
+			Message:   sarifText{Text: strings.TrimSpace(record.Details)},
+		}
+		if result.Message.Text == "" {
+			result.Message.Text = record.Description
+		}
+		if record.Target != "" {
+			result.Properties = map[string]string{"target": record.Target}
+		}
+		if record.Result == scan.Waived && record.Waiver != nil {
+			result.Suppressions = []sarifSuppression{{Kind: "external", Justification: waiverString(record.Waiver)}}
+		}
+		for _, evidence := range record.Evidence {
+			result.Locations = append(result.Locations, sarifLocationFor(evidence.Location, evidence.Line, evidence.Snippet))
+		}
+
+		run.Results = append(run.Results, result)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}})
+}
+
+// sarifRuleFor describes a check as a SARIF rule
+func sarifRuleFor(check scan.Check) sarifRule {
+	severity := (*scan.Profile)(nil).Severity(check)
+	rationale := check.Rationale()
+	if rationale == "" {
+		rationale = check.Description
+	}
+	help := rationale
+	if remediation := check.Remediation(); remediation != "" {
+		help += "\n\nRemediation: " + remediation
+	}
+
+	return sarifRule{
+		ID:                   check.ID,
+		ShortDescription:     sarifText{Text: check.Description},
+		FullDescription:      sarifText{Text: rationale},
+		Help:                 sarifText{Text: help},
+		DefaultConfiguration: sarifRuleConfig{Level: sarifLevels[severity]},
+		Properties: sarifRuleProperty{
+			Tags:             []string{"security", "openstack", strings.ReplaceAll(strings.ToLower(check.Service), " ", "-")},
+			Severity:         severity,
+			SecuritySeverity: securitySeverities[severity],
+		},
+	}
+}
+
+// sarifLocationFor points at a remote file path, or names a logical location such as an API object
+func sarifLocationFor(location string, line int, snippet string) sarifLocation {
+	var result sarifLocation
+	if strings.HasPrefix(location, "/") {
+		result.PhysicalLocation = &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: location}}
+		if line > 0 {
+			result.PhysicalLocation.Region = &sarifRegion{StartLine: line}
+		}
+	} else {
+		result.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: location}}
+	}
+	if snippet != "" {
+		result.Message = &sarifText{Text: snippet}
+	}
+	return result
+}
@@ -0,0 +1,227 @@
+// output/xccdf.go
+package output
+
+import (
+	"encoding/xml"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gunh0/openstack-security-hub/scan"
+)
+
+const (
+	xccdfPrefix = "xccdf_com.github.gunh0"
+
+	// BenchmarkID is the identifier of the XCCDF benchmark that scan TestResults refer to
+	BenchmarkID = xccdfPrefix + "_benchmark_openstack-security-guide"
+)
+
+// xccdfSeverities maps check severities to XCCDF severities, which have no critical level
+var xccdfSeverities = map[string]string{
+	"critical": "high",
+	"high":     "high",
+	"medium":   "medium",
+	"low":      "low",
+	"info":     "info",
+}
+
+// xccdfResults maps check results to XCCDF rule results
+var xccdfResults = map[string]string{
+	"[PASS]":  "pass",
+	"[FAIL]":  "fail",
+	"[ERROR]": "error",
+	"[NA]":    "notapplicable",
+	Skipped:   "notapplicable",
+
+	// Checks stopped at their deadline could not be evaluated
+	scan.Timeout: "error",
+
+	// Waived failures are reported for information, with the waiver as message
+	scan.Waived: "informational",
+}
+
+type xccdfBenchmark struct {
+	XMLName     xml.Name          `xml:"http://checklists.nist.gov/xccdf/1.2 Benchmark"`
+	ID          string            `xml:"id,attr"`
+	Resolved    string            `xml:"resolved,attr"`
+	Status      xccdfStatus       `xml:"status"`
+	Title       string            `xml:"title"`
+	Description string            `xml:"description"`
+	Version     string            `xml:"version"`
+	Groups      []xccdfGroup      `xml:"Group"`
+	TestResults []xccdfTestResult `xml:"TestResult,omitempty"`
+}
+
+type xccdfStatus struct {
+	Date  string `xml:"date,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xccdfGroup struct {
+	ID    string      `xml:"id,attr"`
+	Title string      `xml:"title"`
+	Rules []xccdfRule `xml:"Rule"`
+}
+
+type xccdfRule struct {
+	ID          string `xml:"id,attr"`
+	Selected    bool   `xml:"selected,attr"`
+	Severity    string `xml:"severity,attr"`
+	Title       string `xml:"title"`
+	Description string `xml:"description"`
+	Fixtext     string `xml:"fixtext,omitempty"`
+}
+
+type xccdfTestResult struct {
+	ID          string            `xml:"id,attr"`
+	StartTime   string            `xml:"start-time,attr,omitempty"`
+	EndTime     string            `xml:"end-time,attr"`
+	Benchmark   xccdfBenchmarkRef `xml:"benchmark"`
+	Title       string            `xml:"title"`
+	Target      string            `xml:"target"`
+	RuleResults []xccdfRuleResult `xml:"rule-result"`
+	Score       xccdfScore        `xml:"score"`
+}
+
+type xccdfBenchmarkRef struct {
+	Href string `xml:"href,attr"`
+	ID   string `xml:"id,attr"`
+}
+
+type xccdfRuleResult struct {
+	IDRef    string        `xml:"idref,attr"`
+	Time     string        `xml:"time,attr,omitempty"`
+	Severity string        `xml:"severity,attr,omitempty"`
+	Result   string        `xml:"result"`
+	Message  *xccdfMessage `xml:"message,omitempty"`
+}
+
+type xccdfMessage struct {
+	Severity string `xml:"severity,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type xccdfScore struct {
+	System  string `xml:"system,attr"`
+	Maximum int    `xml:"maximum,attr"`
+	Value   int    `xml:",chardata"`
+}
+
+// xccdfName keeps the characters allowed in the name part of XCCDF 1.2 identifiers
+var xccdfName = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// RuleID returns the XCCDF rule identifier of a check, e.g. xccdf_com.github.gunh0_rule_identity-01-01
+func RuleID(checkID string) string {
+	return xccdfPrefix + "_rule_" + checkID
+}
+
+// WriteXCCDF writes the checklist as an XCCDF 1.2 benchmark with one TestResult per target of records.
+// Without records only the benchmark is written.
+func WriteXCCDF(w io.Writer, records []Record) error {
+	benchmark := xccdfBenchmark{
+		ID:          BenchmarkID,
+		Resolved:    "1",
+		Status:      xccdfStatus{Date: time.Now().UTC().Format("2006-01-02"), Value: "draft"},
+		Title:       "OpenStack Security Guide checklist",
+		Description: "Checks of the OpenStack Security Guide run by OpenStack Security Hub over SSH and the OpenStack APIs.",
+		Version:     "1.0",
+	}
+
+	groups := map[string]int{}
+	for _, check := range scan.Checks() {
+		index, ok := groups[check.Service]
+		if !ok {
+			index = len(benchmark.Groups)
+			groups[check.Service] = index
+			benchmark.Groups = append(benchmark.Groups, xccdfGroup{
+				ID:    xccdfPrefix + "_group_" + xccdfName.ReplaceAllString(strings.ToLower(check.Service), "-"),
+				Title: check.Service,
+			})
+		}
+
+		description := check.Rationale()
+		if description == "" {
+			description = check.Description
+		}
+		benchmark.Groups[index].Rules = append(benchmark.Groups[index].Rules, xccdfRule{
+			ID:          RuleID(check.ID),
+			Selected:    true,
+			Severity:    xccdfSeverities[(*scan.Profile)(nil).Severity(check)],
+			Title:       check.Description,
+			Description: description,
+			Fixtext:     check.Remediation(),
+		})
+	}
+
+	targets := map[string]int{}
+	for _, record := range records {
+		result, ok := xccdfResults[record.Result]
+		if !ok {
+			continue
+		}
+		if _, known := scan.Lookup(record.ID); !known {
+			// Connection errors and other results without a rule cannot be expressed in XCCDF
+			continue
+		}
+
+		index, ok := targets[record.Target]
+		if !ok {
+			index = len(benchmark.TestResults)
+			targets[record.Target] = index
+			benchmark.TestResults = append(benchmark.TestResults, xccdfTestResult{
+				ID:        xccdfPrefix + "_testresult_" + xccdfName.ReplaceAllString(record.Target, "-"),
+				Benchmark: xccdfBenchmarkRef{Href: "#" + BenchmarkID, ID: BenchmarkID},
+				Title:     "OpenStack Security Hub scan of " + record.Target,
+				Target:    record.Target,
+				Score:     xccdfScore{System: "urn:xccdf:scoring:flat"},
+			})
+		}
+		testResult := &benchmark.TestResults[index]
+
+		ruleResult := xccdfRuleResult{
+			IDRef:    RuleID(record.ID),
+			Time:     record.Timestamp,
+			Severity: xccdfSeverities[record.Severity],
+			Result:   result,
+		}
+		if record.Result == scan.Waived && record.Waiver != nil {
+			ruleResult.Message = &xccdfMessage{Severity: "info", Value: "Waived: " + waiverString(record.Waiver)}
+		} else if details := strings.TrimSpace(record.Details); details != "" {
+			ruleResult.Message = &xccdfMessage{Severity: "info", Value: details}
+		}
+		testResult.RuleResults = append(testResult.RuleResults, ruleResult)
+
+		// Timestamps are RFC 3339, so they order as strings
+		if record.Timestamp != "" && (testResult.StartTime == "" || record.Timestamp < testResult.StartTime) {
+			testResult.StartTime = record.Timestamp
+		}
+		if record.Timestamp > testResult.EndTime {
+			testResult.EndTime = record.Timestamp
+		}
+		if result == "pass" || result == "fail" {
+			testResult.Score.Maximum++
+			if result == "pass" {
+				testResult.Score.Value++
+			}
+		}
+	}
+
+	for i := range benchmark.TestResults {
+		if benchmark.TestResults[i].EndTime == "" {
+			benchmark.TestResults[i].EndTime = time.Now().UTC().Format(time.RFC3339)
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(benchmark); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
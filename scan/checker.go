@@ -0,0 +1,121 @@
+package scan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/openstack"
+	"golang.org/x/crypto/ssh"
+)
+
+// Checker runs host checks one at a time outside of a scan, e.g. for the check routes of the API, with
+// the deadlines of a scan: a check still running after timeout, or when ctx is done, is stopped and
+// reported as [TIMEOUT]
+type Checker struct {
+	ctx     context.Context
+	timeout time.Duration
+	profile *Profile
+	conn    *connection
+}
+
+// NewChecker returns a checker running checks over client with profile, which may be nil, and
+// reconnecting to target with connect after a stopped check. The checker owns client; Close closes it.
+func NewChecker(ctx context.Context, target string, client *ssh.Client, connect func(context.Context, string) (*ssh.Client, error), timeout time.Duration, profile *Profile) *Checker {
+	return &Checker{ctx: ctx, timeout: timeout, profile: profile, conn: &connection{ctx: ctx, target: target, client: client, connect: connect}}
+}
+
+// Run runs the check id with fn, or as the profile sets it with Profile.Check
+func (c *Checker) Run(id string, fn func(*ssh.Client) checklist.CheckResult) checklist.CheckResult {
+	checkCtx, cancel := checkContext(c.ctx, c.timeout)
+	defer cancel()
+
+	fn = c.profile.Check(strings.ToLower(id), fn)
+	var checkResult checklist.CheckResult
+	err := c.conn.run(checkCtx, func(client *ssh.Client) {
+		checkResult = fn(client)
+	})
+	switch {
+	case err == nil:
+		return checkResult
+	case checkCtx.Err() != nil:
+		timeout := apiTimeoutResult(c.ctx, id, c.timeout)
+		if timeout.Description == "" {
+			timeout.Description = checkResult.Description
+		}
+		return timeout
+	default:
+		return checklist.CheckResult{Description: checkDescription(id), Result: "[ERROR]", Details: err.Error(), Timestamp: now()}
+	}
+}
+
+// Close closes the SSH connection
+func (c *Checker) Close() {
+	if c.conn.client != nil {
+		c.conn.client.Close()
+	}
+	c.conn.releaseClient()
+}
+
+// CloudChecker runs OpenStack API checks one at a time outside of a scan, with the deadlines of a scan
+// like Checker. As in Cloud, a stopped check is left to run in the background until Close ends its
+// requests.
+type CloudChecker struct {
+	ctx     context.Context
+	timeout time.Duration
+	profile *Profile
+	client  *openstack.Client
+}
+
+// NewCloudChecker returns a checker running checks with client and profile, which may be nil. The checker
+// owns client; Close closes it.
+func NewCloudChecker(ctx context.Context, client *openstack.Client, timeout time.Duration, profile *Profile) *CloudChecker {
+	return &CloudChecker{ctx: ctx, timeout: timeout, profile: profile, client: client}
+}
+
+// Run runs the check id with fn, or as the profile sets it with Profile.CloudCheck
+func (c *CloudChecker) Run(id string, fn func(*openstack.Client) checklist.CheckResult) checklist.CheckResult {
+	checkCtx, cancel := checkContext(c.ctx, c.timeout)
+	defer cancel()
+
+	fn = c.profile.CloudCheck(strings.ToLower(id), fn)
+	done := make(chan checklist.CheckResult, 1)
+	go func() {
+		done <- fn(c.client)
+	}()
+	select {
+	case checkResult := <-done:
+		return checkResult
+	case <-checkCtx.Done():
+		return apiTimeoutResult(c.ctx, id, c.timeout)
+	}
+}
+
+// Close closes the OpenStack client
+func (c *CloudChecker) Close() {
+	c.client.Close()
+}
+
+// apiTimeoutResult reports the check id stopped at the end of ctx, e.g. the deadline of an API request,
+// or at its own after timeout
+func apiTimeoutResult(ctx context.Context, id string, timeout time.Duration) checklist.CheckResult {
+	details := fmt.Sprintf("The check did not finish within %s", timeout)
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled):
+		details = "The check was cancelled"
+	case ctx.Err() != nil || timeout <= 0:
+		details = "The check did not finish before the request deadline"
+	}
+	return checklist.CheckResult{Description: checkDescription(id), Result: Timeout, Details: details, Timestamp: now()}
+}
+
+// checkDescription returns the description of the registered check id, which is case insensitive
+func checkDescription(id string) string {
+	if check, ok := Lookup(strings.ToLower(id)); ok {
+		return check.Description
+	}
+	return ""
+}
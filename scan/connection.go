@@ -0,0 +1,124 @@
+// scan/connection.go
+package scan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"golang.org/x/crypto/ssh"
+)
+
+// Timeout is the result of a check stopped because it ran past its deadline
+const Timeout = "[TIMEOUT]"
+
+// DefaultCheckTimeout is how long a check may run unless CHECK_TIMEOUT says otherwise
+const DefaultCheckTimeout = 5 * time.Minute
+
+// CheckTimeoutFromEnv returns CHECK_TIMEOUT (e.g. 90s, or 0 for no limit), or DefaultCheckTimeout
+func CheckTimeoutFromEnv() (time.Duration, error) {
+	value := os.Getenv("CHECK_TIMEOUT")
+	if value == "" {
+		return DefaultCheckTimeout, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return 0, fmt.Errorf("invalid CHECK_TIMEOUT %q", value)
+	}
+	return timeout, nil
+}
+
+// connection is the SSH connection to a scanned host. A check still running when its context is done
+// is stopped by closing the connection, which ends its session; the next check reconnects.
+type connection struct {
+	// ctx bounds the reconnections and the connections they open
+	ctx     context.Context
+	target  string
+	client  *ssh.Client
+	connect func(ctx context.Context, target string) (*ssh.Client, error)
+
+	// reopened is set once the client given to Host was replaced, so Host closes its own client
+	reopened bool
+
+	// release ends the context of the client opened by a reconnection
+	release context.CancelFunc
+}
+
+// run calls fn with the SSH client until it returns, or until ctx is done. In that case the connection
+// is closed to end the remote command and ctx.Err() is returned. The reconnection after a stopped check
+// is part of the next check, so it is stopped at the deadline of ctx too.
+func (c *connection) run(ctx context.Context, fn func(*ssh.Client)) error {
+	if c.client == nil {
+		// The client outlives the check, so it gets its own context, cancelled while dialing if ctx ends
+		clientCtx, release := context.WithCancel(c.ctx)
+		stopDial := context.AfterFunc(ctx, release)
+		client, err := c.connect(clientCtx, c.target)
+		if !stopDial() {
+			if err == nil {
+				client.Close()
+			}
+			return ctx.Err()
+		}
+		if err != nil {
+			release()
+			return fmt.Errorf("failed to reconnect: %w", err)
+		}
+		c.client, c.reopened, c.release = client, true, release
+	}
+
+	client := c.client
+	stop := context.AfterFunc(ctx, func() {
+		client.Close()
+	})
+	fn(client)
+	if !stop() {
+		c.client = nil
+		c.releaseClient()
+		return ctx.Err()
+	}
+	return nil
+}
+
+// close closes the client opened by a reconnection
+func (c *connection) close() {
+	if c.reopened && c.client != nil {
+		c.client.Close()
+	}
+	c.releaseClient()
+}
+
+// releaseClient ends the context of the client opened by the last reconnection
+func (c *connection) releaseClient() {
+	if c.release != nil {
+		c.release()
+		c.release = nil
+	}
+}
+
+// checkContext returns the context of one check, ending after timeout unless timeout is zero
+func checkContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// stopReason returns why the checks after the end of ctx are skipped
+func stopReason(ctx context.Context) string {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "scan timed out"
+	}
+	return "scan cancelled"
+}
+
+// timeoutResult reports a check stopped at the deadline of the scan, or at its own after timeout
+func timeoutResult(ctx context.Context, check Check, opts Options, timeout time.Duration) Result {
+	details := fmt.Sprintf("The check did not finish within %s", timeout)
+	if ctx.Err() != nil || timeout <= 0 {
+		details = "The check did not finish before the scan deadline"
+	}
+	return result(check, opts, checklist.CheckResult{Description: check.Description, Result: Timeout, Details: details})
+}
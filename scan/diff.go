@@ -0,0 +1,267 @@
+// scan/diff.go
+package scan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gunh0/openstack-security-hub/checklist"
+)
+
+// Kinds of changes between two scans
+const (
+	NewlyFailing = "newly_failing"
+	NewlyPassing = "newly_passing"
+	NewlyErrored = "newly_errored"
+	Added        = "added"
+	Removed      = "removed"
+	Changed      = "changed"
+)
+
+// skippedResult is the status of a skipped check when comparing scans
+const skippedResult = "[SKIPPED]"
+
+// Diff lists the checks whose outcome changed between two scans, per target
+type Diff struct {
+	Summary DiffSummary  `json:"summary"`
+	Targets []TargetDiff `json:"targets"`
+}
+
+// DiffSummary counts the changes of a diff by kind
+type DiffSummary struct {
+	NewlyFailing int `json:"newly_failing"`
+	NewlyPassing int `json:"newly_passing"`
+	NewlyErrored int `json:"newly_errored"`
+	Added        int `json:"added"`
+	Removed      int `json:"removed"`
+	Changed      int `json:"changed"`
+}
+
+// TargetDiff lists the changes of one target
+type TargetDiff struct {
+	Target  string   `json:"target"`
+	Changes []Change `json:"changes"`
+}
+
+// Change is a check whose result, details or evidence differ between two scans.
+// Before and After are empty when the check is missing from one scan.
+type Change struct {
+	ID          string `json:"id"`
+	Service     string `json:"service"`
+	Description string `json:"description"`
+	Kind        string `json:"kind"`
+	Before      string `json:"before,omitempty"`
+	After       string `json:"after,omitempty"`
+
+	DetailsBefore string          `json:"details_before,omitempty"`
+	DetailsAfter  string          `json:"details_after,omitempty"`
+	Evidence      []EvidenceDelta `json:"evidence,omitempty"`
+}
+
+// EvidenceDelta is the evidence found at one location before and after
+type EvidenceDelta struct {
+	Location string `json:"location"`
+	Line     int    `json:"line,omitempty"`
+	Before   string `json:"before,omitempty"`
+	After    string `json:"after,omitempty"`
+}
+
+// outcome is the result of a check in one scan, or its skip reason
+type outcome struct {
+	service     string
+	description string
+	result      string
+	details     string
+	evidence    []checklist.Evidence
+}
+
+// Compare returns the changes from the before reports to the after reports.
+// Checks with the same result, details and evidence are left out.
+func Compare(before, after []Report) Diff {
+	older, newer := outcomes(before), outcomes(after)
+
+	var targets []string
+	for _, reports := range [][]Report{before, after} {
+		for _, report := range reports {
+			if !contains(targets, report.Target) {
+				targets = append(targets, report.Target)
+			}
+		}
+	}
+
+	diff := Diff{Targets: []TargetDiff{}}
+	for _, target := range targets {
+		ids := map[string]bool{}
+		for id := range older[target] {
+			ids[id] = true
+		}
+		for id := range newer[target] {
+			ids[id] = true
+		}
+
+		targetDiff := TargetDiff{Target: target}
+		for id := range ids {
+			a, inBefore := older[target][id]
+			b, inAfter := newer[target][id]
+			change, ok := compareOutcome(id, a, inBefore, b, inAfter)
+			if !ok {
+				continue
+			}
+			diff.Summary.count(change.Kind)
+			targetDiff.Changes = append(targetDiff.Changes, change)
+		}
+		if len(targetDiff.Changes) == 0 {
+			continue
+		}
+
+		sort.Slice(targetDiff.Changes, func(i, j int) bool {
+			x, y := targetDiff.Changes[i].ID, targetDiff.Changes[j].ID
+			if checkOrder(x) != checkOrder(y) {
+				return checkOrder(x) < checkOrder(y)
+			}
+			return x < y
+		})
+		diff.Targets = append(diff.Targets, targetDiff)
+	}
+	return diff
+}
+
+// compareOutcome returns the change of check id, or false when its outcome is unchanged
+func compareOutcome(id string, a outcome, inBefore bool, b outcome, inAfter bool) (Change, bool) {
+	change := Change{ID: id, Service: b.service, Description: b.description, Before: a.result, After: b.result}
+	if !inAfter {
+		change.Service, change.Description = a.service, a.description
+	}
+
+	switch {
+	case !inBefore:
+		change.Kind = Added
+	case !inAfter:
+		change.Kind = Removed
+	case a.result != b.result && b.result == "[FAIL]":
+		change.Kind = NewlyFailing
+	case a.result != b.result && b.result == "[PASS]":
+		change.Kind = NewlyPassing
+	case a.result != b.result && (b.result == "[ERROR]" || b.result == Timeout):
+		change.Kind = NewlyErrored
+	case a.result != b.result || a.details != b.details || !sameEvidence(a.evidence, b.evidence):
+		change.Kind = Changed
+	default:
+		return change, false
+	}
+
+	if inBefore && inAfter {
+		if a.details != b.details {
+			change.DetailsBefore, change.DetailsAfter = a.details, b.details
+		}
+		change.Evidence = evidenceDeltas(a.evidence, b.evidence)
+	}
+	return change, true
+}
+
+// outcomes indexes the results and skipped checks of reports by target and check ID
+func outcomes(reports []Report) map[string]map[string]outcome {
+	index := map[string]map[string]outcome{}
+	for _, report := range reports {
+		checks := map[string]outcome{}
+		for _, result := range report.Results {
+			checks[result.ID] = outcome{
+				service:     result.Service,
+				description: result.Description,
+				result:      result.Result,
+				details:     strings.TrimSpace(result.Details),
+				evidence:    result.Evidence,
+			}
+		}
+		for _, skipped := range report.Skipped {
+			checks[skipped.ID] = outcome{
+				service:     skipped.Service,
+				description: skipped.Description,
+				result:      skippedResult,
+				details:     skipped.Reason,
+			}
+		}
+		index[report.Target] = checks
+	}
+	return index
+}
+
+func sameEvidence(a, b []checklist.Evidence) bool {
+	return len(evidenceDeltas(a, b)) == 0
+}
+
+// evidenceDeltas pairs the evidence of two results by location and line and returns the locations that differ
+func evidenceDeltas(a, b []checklist.Evidence) []EvidenceDelta {
+	type key struct {
+		location string
+		line     int
+	}
+	var keys []key
+	before, after := map[key][]string{}, map[key][]string{}
+	for _, side := range []struct {
+		evidence []checklist.Evidence
+		snippets map[key][]string
+	}{{a, before}, {b, after}} {
+		for _, e := range side.evidence {
+			k := key{e.Location, e.Line}
+			if _, seen := before[k]; !seen {
+				if _, seen := after[k]; !seen {
+					keys = append(keys, k)
+				}
+			}
+			side.snippets[k] = append(side.snippets[k], e.Snippet)
+		}
+	}
+
+	var deltas []EvidenceDelta
+	for _, k := range keys {
+		x, y := strings.Join(before[k], "\n"), strings.Join(after[k], "\n")
+		if x != y {
+			deltas = append(deltas, EvidenceDelta{Location: k.location, Line: k.line, Before: x, After: y})
+		}
+	}
+	return deltas
+}
+
+func (s *DiffSummary) count(kind string) {
+	switch kind {
+	case NewlyFailing:
+		s.NewlyFailing++
+	case NewlyPassing:
+		s.NewlyPassing++
+	case NewlyErrored:
+		s.NewlyErrored++
+	case Added:
+		s.Added++
+	case Removed:
+		s.Removed++
+	case Changed:
+		s.Changed++
+	}
+}
+
+// String summarizes the counts of a diff on one line
+func (s DiffSummary) String() string {
+	return fmt.Sprintf("%d newly failing, %d newly passing, %d newly errored, %d added, %d removed, %d changed",
+		s.NewlyFailing, s.NewlyPassing, s.NewlyErrored, s.Added, s.Removed, s.Changed)
+}
+
+// checkOrder returns the registry position of a check, after every check for unregistered IDs
+func checkOrder(id string) int {
+	for i, check := range registry {
+		if check.ID == id {
+			return i
+		}
+	}
+	return len(registry)
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
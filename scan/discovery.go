@@ -0,0 +1,266 @@
+// scan/discovery.go
+package scan
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Discovery describes what is installed on a scanned host
+type Discovery struct {
+	// Services maps each detected service to how it was found (package, systemd, container or config)
+	Services   map[string]string `json:"services"`
+	Deployment string            `json:"deployment"`
+	Release    string            `json:"release,omitempty"`
+	Versions   map[string]string `json:"versions,omitempty"`
+}
+
+// Has reports whether service was detected on the host
+func (d *Discovery) Has(service string) bool {
+	_, ok := d.Services[service]
+	return ok
+}
+
+// ServiceNames returns the detected services in sorted order
+func (d *Discovery) ServiceNames() []string {
+	names := make([]string, 0, len(d.Services))
+	for name := range d.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// serviceProbe lists the indicators used to detect a service
+type serviceProbe struct {
+	name       string
+	packages   []string
+	units      string // extended regex matched against systemd unit names
+	containers string // extended regex matched against docker/podman container names
+	dirs       []string
+	module     string // python distribution used to read the installed version
+}
+
+var serviceProbes = []serviceProbe{
+	{
+		name:       "keystone",
+		packages:   []string{"keystone", "openstack-keystone", "python3-keystone"},
+		units:      `^(devstack@keystone|keystone)`,
+		containers: `^keystone`,
+		dirs:       []string{"/etc/keystone"},
+		module:     "keystone",
+	},
+	{
+		name:       "horizon",
+		packages:   []string{"openstack-dashboard"},
+		containers: `^horizon`,
+		dirs:       []string{"/etc/openstack-dashboard"},
+		module:     "horizon",
+	},
+	{
+		name:       "barbican",
+		packages:   []string{"barbican-api", "openstack-barbican-api"},
+		units:      `^(devstack@barbican|barbican|openstack-barbican)`,
+		containers: `^barbican`,
+		dirs:       []string{"/etc/barbican"},
+		module:     "barbican",
+	},
+	{
+		name:       "nova",
+		packages:   []string{"nova-api", "nova-compute", "openstack-nova-api", "openstack-nova-compute"},
+		units:      `^(devstack@n-|nova-|openstack-nova-)`,
+		containers: `^nova_`,
+		dirs:       []string{"/etc/nova"},
+		module:     "nova",
+	},
+	{
+		name:       "neutron",
+		packages:   []string{"neutron-server", "openstack-neutron"},
+		units:      `^(devstack@q-|devstack@neutron|neutron-)`,
+		containers: `^neutron_`,
+		dirs:       []string{"/etc/neutron"},
+		module:     "neutron",
+	},
+	{
+		name:       "glance",
+		packages:   []string{"glance-api", "openstack-glance"},
+		units:      `^(devstack@g-|glance-|openstack-glance)`,
+		containers: `^glance`,
+		dirs:       []string{"/etc/glance"},
+		module:     "glance",
+	},
+	{
+		name:       "cinder",
+		packages:   []string{"cinder-api", "cinder-volume", "openstack-cinder"},
+		units:      `^(devstack@c-|cinder-|openstack-cinder)`,
+		containers: `^cinder_`,
+		dirs:       []string{"/etc/cinder"},
+		module:     "cinder",
+	},
+	{
+		name:       "libvirt",
+		packages:   []string{"libvirt-daemon-system", "libvirt-daemon"},
+		units:      `^(libvirtd|virtqemud)`,
+		containers: `^nova_libvirt`,
+		dirs:       []string{"/etc/libvirt"},
+	},
+	{
+		name:       "rabbitmq",
+		packages:   []string{"rabbitmq-server"},
+		units:      `^rabbitmq-server`,
+		containers: `^rabbitmq`,
+		dirs:       []string{"/etc/rabbitmq"},
+	},
+	{
+		name:       "mysql",
+		packages:   []string{"mysql-server", "mariadb-server"},
+		units:      `^(mysql|mysqld|mariadb)\.service`,
+		containers: `^(mariadb|mysql)`,
+		dirs:       []string{"/etc/mysql"},
+	},
+}
+
+// openstackServices are the services that carry oslo configuration files
+var openstackServices = []string{"keystone", "horizon", "barbican", "nova", "neutron", "glance", "cinder"}
+
+// releaseByMajor maps the major version of a service to the OpenStack release it shipped in
+var releaseByMajor = map[string]map[int]string{
+	"keystone": {17: "2020.1", 18: "2020.2", 19: "2021.1", 20: "2021.2", 21: "2022.1", 22: "2022.2", 23: "2023.1", 24: "2023.2", 25: "2024.1", 26: "2024.2", 27: "2025.1", 28: "2025.2"},
+	"nova":     {21: "2020.1", 22: "2020.2", 23: "2021.1", 24: "2021.2", 25: "2022.1", 26: "2022.2", 27: "2023.1", 28: "2023.2", 29: "2024.1", 30: "2024.2", 31: "2025.1", 32: "2025.2"},
+	"glance":   {20: "2020.1", 21: "2020.2", 22: "2021.1", 23: "2021.2", 24: "2022.1", 25: "2022.2", 26: "2023.1", 27: "2023.2", 28: "2024.1", 29: "2024.2", 30: "2025.1", 31: "2025.2"},
+	"neutron":  {16: "2020.1", 17: "2020.2", 18: "2021.1", 19: "2021.2", 20: "2022.1", 21: "2022.2", 22: "2023.1", 23: "2023.2", 24: "2024.1", 25: "2024.2", 26: "2025.1", 27: "2025.2"},
+	"cinder":   {16: "2020.1", 17: "2020.2", 18: "2021.1", 19: "2021.2", 20: "2022.1", 21: "2022.2", 22: "2023.1", 23: "2023.2", 24: "2024.1", 25: "2024.2", 26: "2025.1", 27: "2025.2"},
+}
+
+// discoveryScript builds the shell script printing SERVICE:, VERSION:, RELEASE: and DEPLOYMENT: lines
+func discoveryScript() string {
+	var script strings.Builder
+	script.WriteString(`
+		units=$( (systemctl list-unit-files --no-legend --no-pager; systemctl list-units --all --no-legend --no-pager --plain) 2>/dev/null | awk '{print $1}')
+		containers=$( (docker ps --format '{{.Names}}'; podman ps --format '{{.Names}}') 2>/dev/null)
+		has_pkg() { (dpkg-query -W -f='${Status}' "$1" 2>/dev/null | grep -q "install ok installed") || rpm -q "$1" >/dev/null 2>&1; }
+		py_version() { python3 -c "import importlib.metadata as m; print(m.version('$1'))" 2>/dev/null; }
+	`)
+
+	for _, probe := range serviceProbes {
+		var tests []string
+		for _, pkg := range probe.packages {
+			tests = append(tests, fmt.Sprintf(`has_pkg %q && found=package`, pkg))
+		}
+		if probe.units != "" {
+			tests = append(tests, fmt.Sprintf(`echo "$units" | grep -Eq '%s' && found=systemd`, probe.units))
+		}
+		if probe.containers != "" {
+			tests = append(tests, fmt.Sprintf(`echo "$containers" | grep -Eq '%s' && found=container`, probe.containers))
+		}
+		for _, dir := range probe.dirs {
+			tests = append(tests, fmt.Sprintf(`[ -d %q ] && found=config`, dir))
+		}
+
+		script.WriteString("found=\"\"\n")
+		for _, test := range tests {
+			fmt.Fprintf(&script, "[ -z \"$found\" ] && { %s; }\n", test)
+		}
+		fmt.Fprintf(&script, "[ -n \"$found\" ] && echo \"SERVICE:%s:$found\"\n", probe.name)
+		if probe.module != "" {
+			fmt.Fprintf(&script, "[ -n \"$found\" ] && v=$(py_version %s) && [ -n \"$v\" ] && echo \"VERSION:%s:$v\"\n", probe.module, probe.name)
+		}
+	}
+
+	script.WriteString(`
+		if [ -r /etc/kolla/globals.yml ]; then
+			release=$(awk -F: '/^[[:space:]]*openstack_release[[:space:]]*:/ {gsub(/[ "'\'']/, "", $2); print $2}' /etc/kolla/globals.yml)
+			[ -n "$release" ] && echo "RELEASE:$release"
+		fi
+
+		if [ -d /opt/stack/devstack ] || echo "$units" | grep -q '^devstack@'; then
+			echo "DEPLOYMENT:devstack"
+		elif [ -d /etc/kolla ] || echo "$containers" | grep -q '^kolla_toolbox$'; then
+			echo "DEPLOYMENT:kolla"
+		elif [ -d /etc/openstack_deploy ] || [ -d /openstack/venvs ]; then
+			echo "DEPLOYMENT:openstack-ansible"
+		fi
+		true
+	`)
+
+	return script.String()
+}
+
+// Discover detects installed OpenStack services, the deployment style and the release of a host
+func Discover(client *ssh.Client) (*Discovery, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SSH session: %v", err)
+	}
+	defer session.Close()
+
+	output, err := session.CombinedOutput(discoveryScript())
+	if err != nil {
+		return nil, fmt.Errorf("failed to run discovery: %v", err)
+	}
+
+	return parseDiscovery(string(output)), nil
+}
+
+// parseDiscovery turns the discovery script output into a Discovery
+func parseDiscovery(output string) *Discovery {
+	discovery := &Discovery{
+		Services: map[string]string{},
+		Versions: map[string]string{},
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), ":", 3)
+		switch {
+		case fields[0] == "SERVICE" && len(fields) == 3:
+			discovery.Services[fields[1]] = fields[2]
+		case fields[0] == "VERSION" && len(fields) == 3:
+			discovery.Versions[fields[1]] = fields[2]
+		case fields[0] == "RELEASE" && len(fields) >= 2:
+			// Branch names such as "master" leave the release unknown
+			if release, err := NormalizeRelease(fields[1]); err == nil {
+				discovery.Release = release
+			}
+		case fields[0] == "DEPLOYMENT" && len(fields) >= 2:
+			discovery.Deployment = fields[1]
+		}
+	}
+
+	if discovery.Release == "" {
+		discovery.Release = releaseFromVersions(discovery.Versions)
+	}
+
+	if discovery.Deployment == "" {
+		discovery.Deployment = "unknown"
+		for _, how := range discovery.Services {
+			if how == "package" {
+				discovery.Deployment = "packages"
+				break
+			}
+		}
+	}
+
+	return discovery
+}
+
+// releaseFromVersions derives the release from the first service version with a known mapping
+func releaseFromVersions(versions map[string]string) string {
+	for _, service := range []string{"keystone", "nova", "glance", "neutron", "cinder"} {
+		version, ok := versions[service]
+		if !ok {
+			continue
+		}
+		major, err := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+		if err != nil {
+			continue
+		}
+		if release, ok := releaseByMajor[service][major]; ok {
+			return release
+		}
+	}
+	return ""
+}
@@ -0,0 +1,263 @@
+// scan/framework.go
+package scan
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Framework is a compliance framework whose controls checks are mapped to
+type Framework struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// Frameworks lists the supported compliance frameworks
+var Frameworks = []Framework{
+	{ID: "cis", Title: "CIS Critical Security Controls v8"},
+	{ID: "nist-800-53", Title: "NIST SP 800-53 Rev. 5"},
+	{ID: "iso-27001", Title: "ISO/IEC 27001:2022 Annex A"},
+	{ID: "pci-dss", Title: "PCI DSS v4.0"},
+}
+
+// controlSet maps framework IDs to the controls a family of checks addresses
+type controlSet map[string][]string
+
+var (
+	// Ownership and permissions of configuration and data files
+	fileAccess = controlSet{
+		"cis":         {"3.3", "4.1"},
+		"nist-800-53": {"AC-3", "AC-6", "CM-6"},
+		"iso-27001":   {"A.8.3", "A.8.9"},
+		"pci-dss":     {"2.2.1", "7.2.1"},
+	}
+
+	// Encryption of network traffic between services, users and consoles
+	transportEncryption = controlSet{
+		"cis":         {"3.10"},
+		"nist-800-53": {"SC-8", "SC-8(1)"},
+		"iso-27001":   {"A.8.20", "A.8.24"},
+		"pci-dss":     {"4.2.1"},
+	}
+
+	// Protection of web sessions and cookies
+	sessionProtection = controlSet{
+		"cis":         {"16.10"},
+		"nist-800-53": {"SC-23"},
+		"iso-27001":   {"A.8.26"},
+		"pci-dss":     {"6.2.4"},
+	}
+
+	// Authentication of users and services
+	authentication = controlSet{
+		"cis":         {"5.2", "6.3"},
+		"nist-800-53": {"IA-2", "IA-5"},
+		"iso-27001":   {"A.5.17", "A.8.5"},
+		"pci-dss":     {"8.2.1", "8.3.1"},
+	}
+
+	// Multi-factor authentication of administrators
+	multiFactor = controlSet{
+		"cis":         {"6.5"},
+		"nist-800-53": {"IA-2(1)"},
+		"iso-27001":   {"A.8.5"},
+		"pci-dss":     {"8.4.1"},
+	}
+
+	// Default, shared and anonymous accounts
+	accountManagement = controlSet{
+		"cis":         {"4.7", "5.2"},
+		"nist-800-53": {"AC-2", "IA-5(1)"},
+		"iso-27001":   {"A.5.16", "A.5.17"},
+		"pci-dss":     {"2.2.2", "8.2.2"},
+	}
+
+	// Least privilege and role based access control
+	leastPrivilege = controlSet{
+		"cis":         {"5.4", "6.8"},
+		"nist-800-53": {"AC-6", "AC-6(5)"},
+		"iso-27001":   {"A.5.15", "A.8.2"},
+		"pci-dss":     {"7.2.1", "7.2.2"},
+	}
+
+	// Network exposure of services
+	networkExposure = controlSet{
+		"cis":         {"4.4", "4.8"},
+		"nist-800-53": {"SC-7", "CM-7"},
+		"iso-27001":   {"A.8.20", "A.8.22"},
+		"pci-dss":     {"1.3.1", "1.4.1"},
+	}
+
+	// Isolation between tenants and services sharing a host or a bus
+	isolation = controlSet{
+		"cis":         {"16.10"},
+		"nist-800-53": {"SC-4", "SC-39"},
+		"iso-27001":   {"A.8.22"},
+		"pci-dss":     {"A1.1.1"},
+	}
+
+	// Secrets stored at rest, in logs and in stale copies
+	secretProtection = controlSet{
+		"cis":         {"3.11"},
+		"nist-800-53": {"SC-28", "IA-5(1)"},
+		"iso-27001":   {"A.5.17", "A.8.12"},
+		"pci-dss":     {"3.5.1", "8.3.2"},
+	}
+
+	// Diagnostic output that may disclose sensitive data
+	errorHandling = controlSet{
+		"cis":         {"8.2"},
+		"nist-800-53": {"SI-11", "AU-9"},
+		"iso-27001":   {"A.8.15"},
+		"pci-dss":     {"10.3.1"},
+	}
+
+	// Policy configuration of the OpenStack APIs
+	policyConfiguration = controlSet{
+		"cis":         {"4.1", "6.8"},
+		"nist-800-53": {"AC-3", "CM-6"},
+		"iso-27001":   {"A.5.15", "A.8.9"},
+		"pci-dss":     {"7.2.2"},
+	}
+
+	// Resistance to resource exhaustion
+	denialOfService = controlSet{
+		"nist-800-53": {"SC-5", "SI-10"},
+		"iso-27001":   {"A.8.6"},
+	}
+
+	// Integrity of images offered to every project
+	softwareIntegrity = controlSet{
+		"cis":         {"2.5"},
+		"nist-800-53": {"CM-7(5)", "SI-7"},
+		"iso-27001":   {"A.8.19"},
+		"pci-dss":     {"6.3.2"},
+	}
+
+	// Encryption of stored data
+	dataAtRest = controlSet{
+		"cis":         {"3.11"},
+		"nist-800-53": {"SC-28", "SC-28(1)"},
+		"iso-27001":   {"A.8.24"},
+		"pci-dss":     {"3.5.1"},
+	}
+)
+
+// checkControls maps check IDs or glob patterns to the control sets they address
+var checkControls = map[string][]controlSet{
+	"identity-01-*":    {fileAccess},
+	"identity-02-*":    {fileAccess},
+	"identity-03":      {transportEncryption},
+	"identity-05":      {denialOfService},
+	"identity-06":      {authentication, accountManagement},
+	"dashboard-01":     {fileAccess},
+	"dashboard-0[4-6]": {sessionProtection},
+	"dashboard-05":     {transportEncryption},
+	"key-manager-01-*": {fileAccess},
+	"key-manager-03":   {authentication},
+	"messaging-01":     {transportEncryption},
+	"messaging-02":     {accountManagement},
+	"messaging-03":     {fileAccess, secretProtection},
+	"messaging-04":     {networkExposure},
+	"messaging-05":     {isolation},
+	"database-01":      {transportEncryption},
+	"database-02":      {leastPrivilege},
+	"database-03":      {accountManagement},
+	"database-04":      {fileAccess, secretProtection},
+	"database-05":      {networkExposure},
+	"database-06":      {transportEncryption},
+	"database-07":      {accountManagement},
+	"hypervisor-01":    {networkExposure, authentication},
+	"hypervisor-0[23]": {isolation},
+	"hypervisor-04":    {fileAccess},
+	"hypervisor-05":    {isolation},
+	"hypervisor-06":    {transportEncryption},
+	"leakage-01":       {fileAccess, secretProtection},
+	"leakage-02":       {secretProtection, errorHandling},
+	"leakage-03":       {secretProtection},
+	"leakage-04":       {errorHandling},
+	"policy-*":         {policyConfiguration},
+	"policy-01":        {leastPrivilege},
+	"cloud-01":         {leastPrivilege},
+	"cloud-02":         {multiFactor},
+	"cloud-03":         {networkExposure},
+	"cloud-04":         {softwareIntegrity},
+	"cloud-05":         {dataAtRest},
+	"cloud-06":         {networkExposure},
+}
+
+// LookupFramework returns the framework with the given ID, ignoring case
+func LookupFramework(id string) (Framework, error) {
+	for _, framework := range Frameworks {
+		if strings.EqualFold(framework.ID, id) {
+			return framework, nil
+		}
+	}
+
+	ids := make([]string, 0, len(Frameworks))
+	for _, framework := range Frameworks {
+		ids = append(ids, framework.ID)
+	}
+	return Framework{}, fmt.Errorf("unknown framework %q (use %s)", id, strings.Join(ids, ", "))
+}
+
+// Controls returns the sorted controls of framework that the check addresses
+func (c Check) Controls(framework string) []string {
+	seen := map[string]bool{}
+	var controls []string
+	for pattern, sets := range checkControls {
+		if ok, _ := path.Match(pattern, c.ID); !ok {
+			continue
+		}
+		for _, set := range sets {
+			for _, control := range set[framework] {
+				if !seen[control] {
+					seen[control] = true
+					controls = append(controls, control)
+				}
+			}
+		}
+	}
+
+	sort.Slice(controls, func(i, j int) bool {
+		return CompareControls(controls[i], controls[j]) < 0
+	})
+	return controls
+}
+
+// CompareControls orders control IDs naturally, so AC-3 comes before AC-17 and 3.3 before 3.10
+func CompareControls(a, b string) int {
+	for a != "" && b != "" {
+		x, restA := controlToken(a)
+		y, restB := controlToken(b)
+		if x != y {
+			nx, errX := strconv.Atoi(x)
+			ny, errY := strconv.Atoi(y)
+			if errX == nil && errY == nil {
+				if nx < ny {
+					return -1
+				}
+				return 1
+			}
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+		a, b = restA, restB
+	}
+	return len(a) - len(b)
+}
+
+// controlToken splits the leading run of digits or non-digits off s
+func controlToken(s string) (string, string) {
+	digit := s[0] >= '0' && s[0] <= '9'
+	i := 1
+	for i < len(s) && (s[i] >= '0' && s[i] <= '9') == digit {
+		i++
+	}
+	return s[:i], s[i:]
+}
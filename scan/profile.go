@@ -0,0 +1,245 @@
+// scan/profile.go
+package scan
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/openstack"
+	"golang.org/x/crypto/ssh"
+	"gopkg.in/yaml.v3"
+)
+
+// Severities lists the accepted severity levels from lowest to highest
+var Severities = []string{"info", "low", "medium", "high", "critical"}
+
+// defaultSeverity is used for checks that do not declare one
+const defaultSeverity = "medium"
+
+// Profile customizes the expected values, enablement and severity of checks for a deployment
+type Profile struct {
+	Name   string                   `yaml:"name"`
+	Checks map[string]CheckOverride `yaml:"checks"`
+}
+
+// CheckOverride is the profile entry of a check ID or glob pattern such as identity-01-*
+type CheckOverride struct {
+	Enabled  *bool             `yaml:"enabled"`
+	Severity string            `yaml:"severity"`
+	Params   map[string]string `yaml:"params"`
+}
+
+// LoadProfile reads and validates a YAML profile file
+func LoadProfile(file string) (*Profile, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile: %v", err)
+	}
+
+	profile, err := ParseProfile(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid profile %s: %v", file, err)
+	}
+	if profile.Name == "" {
+		profile.Name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	}
+
+	return profile, nil
+}
+
+// LoadNamedProfile loads the profile name.yaml from dir, rejecting names that would leave it
+func LoadNamedProfile(dir, name string) (*Profile, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return nil, fmt.Errorf("invalid profile name %q", name)
+	}
+	return LoadProfile(filepath.Join(dir, name+".yaml"))
+}
+
+// ParseProfile parses a YAML profile and checks it against the registered checks
+func ParseProfile(content []byte) (*Profile, error) {
+	var profile Profile
+	if err := yaml.Unmarshal(content, &profile); err != nil {
+		return nil, err
+	}
+
+	for pattern, override := range profile.Checks {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("bad check pattern %q: %v", pattern, err)
+		}
+		if override.Severity != "" && !validSeverity(override.Severity) {
+			return nil, fmt.Errorf("%s: unknown severity %q (use %s)", pattern, override.Severity, strings.Join(Severities, ", "))
+		}
+
+		// A pattern only overrides the parameters of the matched checks that have them, e.g. the owner
+		// of identity-* applies to the ownership checks, so each parameter must exist on one of them
+		matched := false
+		declared := map[string]bool{}
+		for _, check := range registry {
+			if ok, _ := path.Match(pattern, check.ID); !ok {
+				continue
+			}
+			matched = true
+			for name := range override.Params {
+				if hasParam(check, name) {
+					declared[name] = true
+				} else if check.ID == pattern {
+					return nil, fmt.Errorf("%s: check %s has no parameter %q", pattern, check.ID, name)
+				}
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("%q does not match any check", pattern)
+		}
+		for name := range override.Params {
+			if !declared[name] {
+				return nil, fmt.Errorf("%s: no matching check has a parameter %q", pattern, name)
+			}
+		}
+	}
+
+	return &profile, nil
+}
+
+// overrides returns the entries matching id, glob patterns first and the exact ID last
+func (p *Profile) overrides(id string) []CheckOverride {
+	if p == nil {
+		return nil
+	}
+
+	var patterns []string
+	for pattern := range p.Checks {
+		if ok, _ := path.Match(pattern, id); ok && pattern != id {
+			patterns = append(patterns, pattern)
+		}
+	}
+	// Longer patterns are more specific and win over shorter ones
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) < len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+
+	var overrides []CheckOverride
+	for _, pattern := range patterns {
+		overrides = append(overrides, p.Checks[pattern])
+	}
+	if override, ok := p.Checks[id]; ok {
+		overrides = append(overrides, override)
+	}
+	return overrides
+}
+
+// name returns the profile name for reports, or "" without a profile
+func (p *Profile) name() string {
+	if p == nil {
+		return ""
+	}
+	return p.Name
+}
+
+// Enabled reports whether the profile leaves check enabled
+func (p *Profile) Enabled(check Check) bool {
+	enabled := true
+	for _, override := range p.overrides(check.ID) {
+		if override.Enabled != nil {
+			enabled = *override.Enabled
+		}
+	}
+	return enabled
+}
+
+// Severity returns the severity of check after profile overrides
+func (p *Profile) Severity(check Check) string {
+	severity := check.Severity
+	if severity == "" {
+		severity = defaultSeverity
+	}
+	for _, override := range p.overrides(check.ID) {
+		if override.Severity != "" {
+			severity = strings.ToLower(override.Severity)
+		}
+	}
+	return severity
+}
+
+// Params applies the profile parameters of check on top of the release defaults. Parameters the check
+// does not declare, set by a pattern matching other checks too, are left out.
+func (p *Profile) Params(check Check, params checklist.Params) checklist.Params {
+	for _, override := range p.overrides(check.ID) {
+		for name, value := range override.Params {
+			if hasParam(check, name) {
+				params[name] = value
+			}
+		}
+	}
+	return params
+}
+
+// Check returns fn, the single check id outside of a scan, with the profile applied: a check disabled
+// by the profile is reported as skipped, and a registered check taking expected values runs with the
+// values of the profile on top of the defaults of the latest release
+func (p *Profile) Check(id string, fn func(*ssh.Client) checklist.CheckResult) func(*ssh.Client) checklist.CheckResult {
+	check, ok := Lookup(id)
+	if p == nil || !ok {
+		return fn
+	}
+	return func(client *ssh.Client) checklist.CheckResult {
+		if !p.Enabled(check) {
+			return p.disabled(check)
+		}
+		if check.RunParams != nil {
+			return check.RunParams(client, p.Params(check, releaseParams(check, "")))
+		}
+		return fn(client)
+	}
+}
+
+// CloudCheck returns fn, the single OpenStack API check id outside of a scan, reported as skipped when
+// the profile disables it
+func (p *Profile) CloudCheck(id string, fn func(*openstack.Client) checklist.CheckResult) func(*openstack.Client) checklist.CheckResult {
+	check, ok := Lookup(id)
+	if p == nil || !ok {
+		return fn
+	}
+	return func(client *openstack.Client) checklist.CheckResult {
+		if !p.Enabled(check) {
+			return p.disabled(check)
+		}
+		return fn(client)
+	}
+}
+
+// disabled is the result of a single check disabled by the profile
+func (p *Profile) disabled(check Check) checklist.CheckResult {
+	return checklist.CheckResult{
+		Description: check.Description,
+		Result:      "[SKIPPED]",
+		Details:     fmt.Sprintf("disabled by profile %s", p.Name),
+		Timestamp:   now(),
+	}
+}
+
+// hasParam reports whether check declares the parameter name in its defaults
+func hasParam(check Check, name string) bool {
+	for _, defaults := range check.Defaults {
+		if _, ok := defaults.Params[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func validSeverity(severity string) bool {
+	for _, s := range Severities {
+		if strings.EqualFold(s, severity) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+// scan/rationale.go
+package scan
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rationales shared by the checks of one family
+const (
+	ownershipRationale          = "Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user and group ownership of such critical configuration files must be set to that component owner. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly."
+	identityPermissionRationale = "Configuration files and the /etc/keystone directory hold secrets such as database credentials and signing keys. If other users can read them they can obtain these secrets, and if they can be modified by the group or other users the behaviour of the service can be changed. Similar to the ownership checks, strict access permissions must be set: files must not be writable by the group nor accessible by other users."
+	barbicanOwnershipRationale  = "Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally, modifies or deletes any of the parameters or the file itself then it would cause severe availability issues resulting in a denial of service to the other end users. User ownership of such critical configuration files must be set to root and group ownership must be set to barbican. Additionally, the containing directory should have the same ownership to ensure that new files are owned correctly."
+)
+
+// rationales explains why each check matters, as in the OpenStack Security Guide. They are also the
+// descriptions of the check routes in the served API documentation.
+var rationales = map[string]string{
+	"identity-01-01":    ownershipRationale,
+	"identity-01-02":    ownershipRationale,
+	"identity-01-03":    ownershipRationale,
+	"identity-01-04":    ownershipRationale,
+	"identity-01-05":    ownershipRationale,
+	"identity-01-06":    ownershipRationale,
+	"identity-01-07":    ownershipRationale,
+	"identity-01-08":    ownershipRationale,
+	"identity-02-01":    identityPermissionRationale,
+	"identity-02-02":    identityPermissionRationale,
+	"identity-02-03":    identityPermissionRationale,
+	"identity-02-04":    identityPermissionRationale,
+	"identity-02-05":    identityPermissionRationale,
+	"identity-02-06":    identityPermissionRationale,
+	"identity-02-07":    identityPermissionRationale,
+	"identity-02-08":    identityPermissionRationale,
+	"identity-03":       "Keystone issues the tokens used to access every other service. Without TLS, user credentials and tokens sent to the Identity API can be captured on the network and replayed. The Identity API must be served over HTTPS, either by Keystone itself or by the web server or load balancer in front of it.",
+	"identity-05":       "The max_request_body_size option limits the size of each request in bytes. If it is unset or very large, an attacker can send arbitrarily large requests and exhaust the memory of the Identity service, causing a denial of service. It should be kept at its default of 114688.",
+	"identity-06":       "The admin token is a shared secret that bypasses authentication and grants administrative rights on Keystone. It is only intended for bootstrapping and must be disabled by removing admin_token from the [DEFAULT] section of keystone.conf and the admin_token_auth filter from the paste pipeline.",
+	"dashboard-01":      "Configuration files contain critical parameters and information required for smooth functioning of the component. If an unprivileged user, either intentionally or accidentally modifies or deletes any of the parameters or the file itself then it would cause severe availability issues causing a denial of service to the other end users. Thus user ownership of such critical configuration files must be set to root and group ownership must be set to horizon.",
+	"dashboard-04":      "CSRF (Cross-site request forgery) is an attack which forces an end user to execute unauthorized commands on a web application in which he/she is currently authenticated. A successful CSRF exploit can compromise end user data and operations. If the targeted end user has admin privileges, this can compromise the entire web application.",
+	"dashboard-05":      "The “SECURE” cookie attribute instructs web browsers to only send the cookie through an encrypted HTTPS (SSL/TLS) connection. This session protection mechanism is mandatory to prevent the disclosure of the session ID through MitM (Man-in-the-Middle) attacks. It ensures that an attacker cannot simply capture the session ID from web browser traffic.",
+	"dashboard-06":      "The “HTTPONLY” cookie attribute instructs web browsers not to allow scripts (e.g. JavaScript or VBscript) an ability to access the cookies via the DOM document.cookie object. This session ID protection is mandatory to prevent session ID stealing through XSS attacks.",
+	"key-manager-01-01": barbicanOwnershipRationale,
+	"key-manager-01-02": barbicanOwnershipRationale,
+	"key-manager-03":    "OpenStack supports various authentication strategies like noauth and keystone. If the noauth strategy is used then the users can interact with OpenStack services without any authentication. This could be a potential risk since an attacker might gain unauthorized access to the OpenStack components. We strongly recommend that all services must be authenticated with keystone using their service accounts.",
+	"messaging-01":      "Every OpenStack service connects to the message bus using the transport_url option, which carries the service's RabbitMQ credentials. If the connection is not protected with TLS, an attacker with access to the management network can capture credentials and tamper with RPC messages exchanged between services. TLS must be enabled with ssl=true in the transport_url or with the ssl option of the [oslo_messaging_rabbit] section (rabbit_use_ssl in older releases).",
+	"messaging-02":      "RabbitMQ ships with a default guest account whose password is publicly known. If the account is left in place, anyone able to reach the broker may read and inject messages for all services. The guest account must be deleted and each service must use its own dedicated account.",
+	"messaging-03":      "RabbitMQ configuration files and the Erlang cookie allow full control over the broker and the cluster. If an unprivileged user can read or modify them, they can take over the message bus. The files must be owned by root or rabbitmq, must not be accessible by other users, and the Erlang cookie must only be readable by its owner.",
+	"messaging-04":      "The rabbitmq_management plugin exposes an HTTP API and web UI that allows administrative operations on the broker. If it listens on all interfaces it may be reachable from untrusted networks. The plugin should be disabled when not required, otherwise it must be bound to the management network and protected with TLS.",
+	"messaging-05":      "When all services share the same virtual host, a compromise of a single service's credentials gives access to the queues of every other service. Using a separate virtual host per service limits the impact of leaked credentials and isolates the message flows of each component.",
+	"database-01":       "Services authenticate to the database with credentials stored in the [database] connection option. If the connection is not encrypted, these credentials and all data exchanged with the database can be captured by an attacker on the network. Every connection to a remote database server must enable TLS by setting ssl_ca in the connection string.",
+	"database-02":       "The database root account has full privileges over all databases. If a service connects as root, a compromise of that service gives the attacker control over the data of every other service. Each service must use a dedicated database account with privileges limited to its own database.",
+	"database-03":       "When several services share the same database user or password, leaking the configuration file of one service exposes the databases of all the others. Each service must use a unique database user and password.",
+	"database-04":       "MySQL option files may contain server settings and client passwords, for example in /root/.my.cnf or /etc/mysql/debian.cnf. If they are world-readable, any local user can read the stored credentials or learn details of the server configuration. Option files must not be readable by other users, and files holding passwords must only be readable by their owner.",
+	"database-05":       "By default the database server may listen on all network interfaces. The database should only be reachable from the management network used by the OpenStack services, so bind-address must be set to a specific internal address.",
+	"database-06":       "Even if clients are configured to use TLS, the server accepts unencrypted connections unless require_secure_transport is enabled. Enabling it ensures that no client can connect to the database without TLS.",
+	"database-07":       "Anonymous accounts allow anyone to connect to the database server without a user name. These accounts are created by some default installations and must be removed.",
+	"hypervisor-01":     "libvirtd can accept remote management connections over plain TCP. If listen_tcp is enabled, especially with auth_tcp set to none, anyone who can reach the compute node can take full control of all guests. Remote access must be disabled or limited to the TLS listener with authentication.",
+	"hypervisor-02":     "sVirt uses SELinux or AppArmor to confine each QEMU process so that a guest breaking out of the emulator cannot access the resources of other guests or the host. The security_driver option in qemu.conf must not be set to none and a mandatory access control framework must be active on the host.",
+	"hypervisor-03":     "Even if sVirt is configured, guests started before a configuration change or with an explicit override may run unconfined. Each running QEMU process must carry an svirt SELinux label or a libvirt AppArmor profile.",
+	"hypervisor-04":     "The instances directory holds the disk images, console logs and configuration of every guest on the compute node. If other users can read or modify these files, they can access tenant data or tamper with instances. The directory must be owned by nova and instance files must not be accessible by other users.",
+	"hypervisor-05":     "Kernel Samepage Merging deduplicates identical memory pages across guests. Shared pages enable side-channel attacks in which one tenant can infer memory contents of another. In multi-tenant clouds KSM should be disabled.",
+	"hypervisor-06":     "Graphical consoles give full keyboard and screen access to an instance. Without TLS, console traffic between the proxy and the compute node, including passwords typed by users, can be captured on the network. VNC must use the VeNCrypt auth scheme with vnc_tls enabled, and SPICE must require secure channels with spice_tls enabled.",
+	"leakage-01":        "OpenStack services keep passwords, tokens, transport_url and database credentials and sometimes private keys in plaintext configuration files. If these files are readable by other users, any local account can obtain the credentials of the service and of the infrastructure behind it. Every file holding a secret must not be world-readable. The location of each secret is reported with a redacted snippet so it can be reviewed.",
+	"leakage-02":        "At DEBUG level, or through misbehaving middleware, services may write request bodies containing passwords, X-Auth-Token headers or URLs with embedded credentials to their logs. Logs are often shipped to central systems and kept for a long time, so they must never contain secrets.",
+	"leakage-03":        "Editors and package managers leave copies such as keystone.conf.bak, .orig, .dpkg-old or .rpmsave next to the live configuration. These copies contain the same secrets but are often created with default permissions and forgotten during credential rotation. Backup copies must be removed from the configuration directories.",
+	"leakage-04":        "With debug = True services log request and response details that may contain tokens, passwords and internal data. Debug logging must be disabled on production deployments.",
+	"policy-01":         "Policy files override the default access rules of each API. An empty rule or @ allows any user with a valid token, and granting role:member or role:reader on administrative APIs lets ordinary project members manage users, projects or infrastructure. Administrative rules must be restricted to admin roles and alias rules such as admin_required must never be always allowed.",
+	"policy-02":         "Since the introduction of policy in code, every service ships secure defaults with scope and role based rules. Overrides that reference legacy aliases such as rule:admin_or_owner or is_admin:True pin the deprecated defaults and keep the cloud on the old, less granular model. JSON policy files are also deprecated in favour of policy.yaml. Overrides should be removed unless strictly required.",
+	"policy-03":         "The new secure RBAC defaults and token scope checks are only enforced when enforce_scope and enforce_new_defaults are enabled in the [oslo_policy] section of each service. Without them, the deprecated rules remain active and system scoped and project scoped tokens are not distinguished.",
+	"cloud-01":          "The admin role is global in most OpenStack services, and every additional project in which a user holds it widens the impact of a stolen credential. Users holding the admin role in many projects should be reviewed and replaced by dedicated administrative accounts or scoped roles.",
+	"cloud-02":          "Keystone supports per-user multi-factor authentication rules through the multi_factor_auth_enabled and multi_factor_auth_rules user options. Administrative accounts must require a second factor such as TOTP so that a leaked password alone cannot be used to obtain an admin token.",
+	"cloud-03":          "Security group rules that allow SSH (22) or RDP (3389) from 0.0.0.0/0 or ::/0 expose instances to brute force and exploitation from the whole internet. Remote administration ports must be restricted to trusted source networks.",
+	"cloud-04":          "Public images are visible to and bootable by every project. An image made public by a tenant may contain malware, backdoors or leaked data. Only images curated by the cloud administrators should be public.",
+	"cloud-05":          "Block storage volumes hold tenant data on shared storage backends. Without volume encryption, anyone with access to the storage backend or to discarded disks can read the data. Volumes should be created from encrypted volume types.",
+	"cloud-06":          "A floating IP makes an instance reachable from external networks. If the instance port has no security group or has port security disabled, all of its services are exposed without any filtering.",
+}
+
+// Rationale returns why the check matters, or "" when it is not documented
+func (c Check) Rationale() string {
+	return rationales[c.ID]
+}
+
+// remediations explains how to fix a failing check; ownership and permission checks derive it from their expected values
+var remediations = map[string]string{
+	"identity-03":       "Serve the Identity API over HTTPS: terminate TLS in the web server or load balancer in front of keystone, and use https:// endpoints in the service catalog and in every [keystone_authtoken] www_authenticate_uri and auth_url.",
+	"identity-05":       "Set max_request_body_size = 114688 in the [oslo_middleware] section of /etc/keystone/keystone.conf, or remove the override to use the default.",
+	"identity-06":       "Remove admin_token from the [DEFAULT] section of /etc/keystone/keystone.conf and remove admin_token_auth from the pipelines in /etc/keystone/keystone-paste.ini. Use keystone-manage bootstrap to create the initial administrator instead.",
+	"dashboard-01":      "Run chown root:horizon /etc/openstack-dashboard/local_settings.py.",
+	"dashboard-04":      "Set CSRF_COOKIE_SECURE = True in /etc/openstack-dashboard/local_settings.py and serve Horizon over HTTPS.",
+	"dashboard-05":      "Set SESSION_COOKIE_SECURE = True in /etc/openstack-dashboard/local_settings.py and serve Horizon over HTTPS.",
+	"dashboard-06":      "Set SESSION_COOKIE_HTTPONLY = True in /etc/openstack-dashboard/local_settings.py.",
+	"key-manager-01-01": "Run chown root:barbican /etc/barbican/barbican.conf /etc/barbican.",
+	"key-manager-01-02": "Run chown root:barbican /etc/barbican/barbican-api-paste.ini /etc/barbican.",
+	"key-manager-03":    "Set auth_strategy = keystone in the [DEFAULT] section of /etc/barbican/barbican.conf and configure the [keystone_authtoken] section with the barbican service account.",
+	"messaging-01":      "Enable TLS towards RabbitMQ: add ?ssl=true to every transport_url, or set ssl = true in the [oslo_messaging_rabbit] section of each service, and configure TLS listeners on the broker.",
+	"messaging-02":      "Delete the default account with rabbitmqctl delete_user guest and make sure no transport_url uses guest credentials.",
+	"messaging-03":      "Set the owner of the RabbitMQ configuration files to root or rabbitmq with mode 640 or stricter, and restrict the Erlang cookie (/var/lib/rabbitmq/.erlang.cookie) to mode 600 owned by rabbitmq.",
+	"messaging-04":      "Disable the plugin with rabbitmq-plugins disable rabbitmq_management, or bind management.tcp.ip to the management network and enable management.ssl listeners.",
+	"messaging-05":      "Create a virtual host per service with rabbitmqctl add_vhost, grant each service account permissions on its own virtual host only, and reference it at the end of the service's transport_url.",
+	"database-01":       "Append ?ssl_ca=/path/to/ca.pem (and ssl_cert/ssl_key for client certificates) to the connection option of the [database] section of each service.",
+	"database-02":       "Create a dedicated database user for each service with privileges on its own database only, and replace root in the [database] connection option.",
+	"database-03":       "Create a unique database user and password for each service and update the [database] connection option of each service.",
+	"database-04":       "Run chmod 640 on MySQL option files, and chmod 600 on files holding passwords such as /root/.my.cnf and /etc/mysql/debian.cnf.",
+	"database-05":       "Set bind-address in the [mysqld] section to the management network address of the database server.",
+	"database-06":       "Set require_secure_transport = ON in the [mysqld] section and restart the database server.",
+	"database-07":       "Remove anonymous accounts with DROP USER ''@'localhost' (and every other host), or run mysql_secure_installation.",
+	"hypervisor-01":     "Set listen_tcp = 0 in /etc/libvirt/libvirtd.conf, or disable the libvirtd-tcp socket, and use the TLS listener with authentication for remote management.",
+	"hypervisor-02":     "Set security_driver to selinux or apparmor in /etc/libvirt/qemu.conf and enable SELinux or AppArmor on the host.",
+	"hypervisor-03":     "Restart or migrate the unconfined instances after enabling sVirt, and remove per-domain <seclabel type='none'/> overrides.",
+	"hypervisor-04":     "Run chown nova:nova /var/lib/nova/instances, chmod 755 or stricter on the directory, and remove access for other users from the instance files.",
+	"hypervisor-05":     "Disable KSM with echo 0 > /sys/kernel/mm/ksm/run and disable the ksm and ksmtuned services.",
+	"hypervisor-06":     "Set vnc_tls = 1 in /etc/libvirt/qemu.conf with auth_schemes = vencrypt in the [vnc] section of nova.conf, and spice_tls = 1 with require_secure = True in the [spice] section.",
+	"leakage-01":        "Restrict every configuration file holding secrets to mode 640 or stricter, owned by root and the service group.",
+	"leakage-02":        "Disable debug logging, remove the secrets from existing logs, and rotate the exposed credentials and tokens.",
+	"leakage-03":        "Delete the backup copies from the configuration directories and rotate any credential they still contain.",
+	"leakage-04":        "Set debug = False in the [DEFAULT] section of every service configuration file.",
+	"policy-01":         "Restrict administrative rules to admin roles, and never set alias rules such as admin_required to an empty rule or @.",
+	"policy-02":         "Remove overrides that use deprecated aliases such as rule:admin_or_owner or is_admin:True, and migrate policy.json files to policy.yaml with oslopolicy-convert-json-to-yaml.",
+	"policy-03":         "Set enforce_scope = True and enforce_new_defaults = True in the [oslo_policy] section of each service.",
+	"cloud-01":          "Remove the admin role from users that hold it in many projects, and use dedicated administrative accounts or project scoped roles instead.",
+	"cloud-02":          "Enable multi_factor_auth_enabled with TOTP rules for every administrative user, e.g. openstack user set --enable-multi-factor-auth --multi-factor-auth-rule password,totp <user>.",
+	"cloud-03":          "Replace the 0.0.0.0/0 and ::/0 rules for ports 22 and 3389 with rules limited to trusted source networks, or use a bastion host.",
+	"cloud-04":          "Make tenant images private or shared with openstack image set --private, and keep only curated images public.",
+	"cloud-05":          "Create an encrypted volume type backed by Barbican, make it the default volume type, and migrate existing volumes by retyping them.",
+	"cloud-06":          "Enable port security and attach restrictive security groups to every port with a floating IP.",
+}
+
+// Remediation returns how to fix a failing check, or "" when it is not documented
+func (c Check) Remediation() string {
+	if remediation, ok := remediations[c.ID]; ok {
+		return remediation
+	}
+
+	params := releaseParams(c, "")
+	path := params.Get("path", "")
+	switch {
+	case path != "" && params.Get("owner", "") != "":
+		return fmt.Sprintf("Run chown %s %s.", strings.Replace(params.Get("owner", ""), " ", ":", 1), path)
+	case path != "" && params.Get("mode", "") != "":
+		return fmt.Sprintf("Run chmod %s %s, or set stricter permissions.", params.Get("mode", ""), path)
+	}
+	return ""
+}
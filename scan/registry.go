@@ -0,0 +1,148 @@
+// scan/registry.go
+package scan
+
+import (
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/checklist/cloud"
+	"github.com/gunh0/openstack-security-hub/checklist/dashboard"
+	"github.com/gunh0/openstack-security-hub/checklist/database"
+	"github.com/gunh0/openstack-security-hub/checklist/hypervisor"
+	"github.com/gunh0/openstack-security-hub/checklist/identity"
+	"github.com/gunh0/openstack-security-hub/checklist/leakage"
+	"github.com/gunh0/openstack-security-hub/checklist/messaging"
+	"github.com/gunh0/openstack-security-hub/checklist/policy"
+	"github.com/gunh0/openstack-security-hub/checklist/secrets"
+	"github.com/gunh0/openstack-security-hub/openstack"
+	"golang.org/x/crypto/ssh"
+)
+
+// Check is a registered security check and the services it applies to
+type Check struct {
+	ID          string
+	Service     string
+	Description string
+
+	// Severity is the default severity of a failure (info, low, medium, high or critical); empty means medium
+	Severity string
+
+	// Requires lists the discovered services of which at least one must be present
+	Requires []string
+
+	// Releases limits the check to the releases it applies to
+	Releases ReleaseRange
+
+	// Defaults holds the expected values passed to RunParams, ordered from oldest to newest release
+	Defaults []ReleaseDefaults
+
+	// Run executes a host check over SSH, RunParams one that takes expected values,
+	// and RunCloud a cloud check over the OpenStack APIs
+	Run       func(*ssh.Client) checklist.CheckResult
+	RunParams func(*ssh.Client, checklist.Params) checklist.CheckResult
+	RunCloud  func(*openstack.Client) checklist.CheckResult
+}
+
+// IsCloud reports whether the check runs against the OpenStack APIs instead of a host
+func (c Check) IsCloud() bool {
+	return c.RunCloud != nil
+}
+
+// pkiReleases limits the checks of the PKI token signing files to the releases that used them
+var pkiReleases = ReleaseRange{Until: "2016.2", Reason: "PKI tokens and their signing certificates were removed in Ocata"}
+
+// policyFileDefaults select the default policy file, which moved from JSON to YAML in Wallaby
+var policyFileDefaults = []ReleaseDefaults{
+	{Since: "2021.1", Params: checklist.Params{"path": "/etc/keystone/policy.yaml"}},
+}
+
+// ownershipDefaults are the expected values of an Identity file ownership check
+func ownershipDefaults(path string) []ReleaseDefaults {
+	return []ReleaseDefaults{{Params: checklist.Params{"path": path, "owner": "keystone keystone"}}}
+}
+
+// permissionDefaults are the expected values of an Identity file permission check
+func permissionDefaults(path, mode string) []ReleaseDefaults {
+	return []ReleaseDefaults{{Params: checklist.Params{"path": path, "mode": mode}}}
+}
+
+var registry = []Check{
+	{ID: "identity-01-01", Service: "Identity", Description: "Is user/group ownership of /etc/keystone/keystone.conf set to keystone?", Requires: []string{"keystone"}, Defaults: ownershipDefaults("/etc/keystone/keystone.conf"), RunParams: identity.CheckOwnershipWithParams},
+	{ID: "identity-01-02", Service: "Identity", Description: "Is user/group ownership of /etc/keystone/keystone-paste.ini set to keystone?", Requires: []string{"keystone"}, Defaults: ownershipDefaults("/etc/keystone/keystone-paste.ini"), RunParams: identity.CheckOwnershipWithParams},
+	{ID: "identity-01-03", Service: "Identity", Description: "Is user/group ownership of the Identity policy file set to keystone?", Requires: []string{"keystone"}, Defaults: append(ownershipDefaults("/etc/keystone/policy.json"), policyFileDefaults...), RunParams: identity.CheckOwnershipWithParams},
+	{ID: "identity-01-04", Service: "Identity", Description: "Is user/group ownership of /etc/keystone/logging.conf set to keystone?", Requires: []string{"keystone"}, Defaults: ownershipDefaults("/etc/keystone/logging.conf"), RunParams: identity.CheckOwnershipWithParams},
+	{ID: "identity-01-05", Service: "Identity", Description: "Is user/group ownership of /etc/keystone/ssl/certs/signing_cert.pem set to keystone?", Requires: []string{"keystone"}, Releases: pkiReleases, Defaults: ownershipDefaults("/etc/keystone/ssl/certs/signing_cert.pem"), RunParams: identity.CheckOwnershipWithParams},
+	{ID: "identity-01-06", Service: "Identity", Description: "Is user/group ownership of /etc/keystone/ssl/private/signing_key.pem set to keystone?", Requires: []string{"keystone"}, Releases: pkiReleases, Defaults: ownershipDefaults("/etc/keystone/ssl/private/signing_key.pem"), RunParams: identity.CheckOwnershipWithParams},
+	{ID: "identity-01-07", Service: "Identity", Description: "Is user/group ownership of /etc/keystone/ssl/certs/ca.pem set to keystone?", Requires: []string{"keystone"}, Releases: pkiReleases, Defaults: ownershipDefaults("/etc/keystone/ssl/certs/ca.pem"), RunParams: identity.CheckOwnershipWithParams},
+	{ID: "identity-01-08", Service: "Identity", Description: "Is user/group ownership of /etc/keystone set to keystone?", Requires: []string{"keystone"}, Defaults: ownershipDefaults("/etc/keystone"), RunParams: identity.CheckOwnershipWithParams},
+	{ID: "identity-02-01", Service: "Identity", Description: "Are strict permissions set for /etc/keystone/keystone.conf?", Requires: []string{"keystone"}, Defaults: permissionDefaults("/etc/keystone/keystone.conf", "640"), RunParams: identity.CheckPermissionsWithParams},
+	{ID: "identity-02-02", Service: "Identity", Description: "Are strict permissions set for /etc/keystone/keystone-paste.ini?", Requires: []string{"keystone"}, Defaults: permissionDefaults("/etc/keystone/keystone-paste.ini", "640"), RunParams: identity.CheckPermissionsWithParams},
+	{ID: "identity-02-03", Service: "Identity", Description: "Are strict permissions set for the Identity policy file?", Requires: []string{"keystone"}, Defaults: append(permissionDefaults("/etc/keystone/policy.json", "640"), policyFileDefaults...), RunParams: identity.CheckPermissionsWithParams},
+	{ID: "identity-02-04", Service: "Identity", Description: "Are strict permissions set for /etc/keystone/logging.conf?", Requires: []string{"keystone"}, Defaults: permissionDefaults("/etc/keystone/logging.conf", "640"), RunParams: identity.CheckPermissionsWithParams},
+	{ID: "identity-02-05", Service: "Identity", Description: "Are strict permissions set for /etc/keystone/ssl/certs/signing_cert.pem?", Requires: []string{"keystone"}, Releases: pkiReleases, Defaults: permissionDefaults("/etc/keystone/ssl/certs/signing_cert.pem", "640"), RunParams: identity.CheckPermissionsWithParams},
+	{ID: "identity-02-06", Service: "Identity", Description: "Are strict permissions set for /etc/keystone/ssl/private/signing_key.pem?", Requires: []string{"keystone"}, Releases: pkiReleases, Defaults: permissionDefaults("/etc/keystone/ssl/private/signing_key.pem", "640"), RunParams: identity.CheckPermissionsWithParams},
+	{ID: "identity-02-07", Service: "Identity", Description: "Are strict permissions set for /etc/keystone/ssl/certs/ca.pem?", Requires: []string{"keystone"}, Releases: pkiReleases, Defaults: permissionDefaults("/etc/keystone/ssl/certs/ca.pem", "640"), RunParams: identity.CheckPermissionsWithParams},
+	{ID: "identity-02-08", Service: "Identity", Description: "Are strict permissions set for /etc/keystone?", Requires: []string{"keystone"}, Defaults: permissionDefaults("/etc/keystone", "750"), RunParams: identity.CheckPermissionsWithParams},
+	{ID: "identity-03", Service: "Identity", Description: "Is TLS enabled for Identity?", Requires: []string{"keystone"}, Run: identity.CheckIdentity03},
+	{ID: "identity-05", Service: "Identity", Description: "Is max_request_body_size set to default (114688)?", Severity: "low", Requires: []string{"keystone"}, Defaults: []ReleaseDefaults{{Params: checklist.Params{"default_size": "114688", "max_size": "10485760"}}}, RunParams: identity.CheckIdentity05WithParams},
+	{ID: "identity-06", Service: "Identity", Description: "Disable admin token in /etc/keystone/keystone.conf", Severity: "high", Requires: []string{"keystone"}, Run: identity.CheckIdentity06},
+
+	{ID: "dashboard-01", Service: "Dashboard", Description: "Is user/group of config files set to root/horizon?", Requires: []string{"horizon"}, Defaults: []ReleaseDefaults{{Params: checklist.Params{"owner": "root horizon"}}}, RunParams: dashboard.CheckDashboard01WithParams},
+	{ID: "dashboard-04", Service: "Dashboard", Description: "Is CSRF_COOKIE_SECURE parameter set to True?", Requires: []string{"horizon"}, Run: dashboard.CheckDashboard04},
+	{ID: "dashboard-05", Service: "Dashboard", Description: "Is SESSION_COOKIE_SECURE parameter set to True?", Requires: []string{"horizon"}, Run: dashboard.CheckDashboard05},
+	{ID: "dashboard-06", Service: "Dashboard", Description: "Is SESSION_COOKIE_HTTPONLY parameter set to True?", Severity: "low", Requires: []string{"horizon"}, Run: dashboard.CheckDashboard06},
+
+	{ID: "key-manager-01-01", Service: "Secrets Management", Description: "Is the ownership of config files set to root/barbican? (/etc/barbican/barbican.conf)", Requires: []string{"barbican"}, Run: secrets.CheckKeyManager0101},
+	{ID: "key-manager-01-02", Service: "Secrets Management", Description: "Is the ownership of config files set to root/barbican? (/etc/barbican/barbican-api-paste.ini)", Requires: []string{"barbican"}, Run: secrets.CheckKeyManager0102},
+	{ID: "key-manager-03", Service: "Secrets Management", Description: "Is OpenStack Identity used for authentication?", Requires: []string{"barbican"}, Run: secrets.CheckKeyManager03},
+
+	{ID: "messaging-01", Service: "Messaging", Description: "Is TLS enabled for every service's transport_url?", Requires: openstackServices, Run: messaging.CheckMessaging01},
+	{ID: "messaging-02", Service: "Messaging", Description: "Is the RabbitMQ guest account disabled?", Severity: "high", Requires: append([]string{"rabbitmq"}, openstackServices...), Run: messaging.CheckMessaging02},
+	{ID: "messaging-03", Service: "Messaging", Description: "Are strict ownership and permissions set for RabbitMQ configuration files?", Requires: []string{"rabbitmq"}, Run: messaging.CheckMessaging03},
+	{ID: "messaging-04", Service: "Messaging", Description: "Is the RabbitMQ management plugin restricted?", Requires: []string{"rabbitmq"}, Run: messaging.CheckMessaging04},
+	{ID: "messaging-05", Service: "Messaging", Description: "Does each service use a separate RabbitMQ virtual host?", Severity: "low", Requires: openstackServices, Run: messaging.CheckMessaging05},
+
+	{ID: "database-01", Service: "Database", Description: "Is TLS used for every service's database connection?", Requires: openstackServices, Run: database.CheckDatabase01},
+	{ID: "database-02", Service: "Database", Description: "Do services avoid connecting to the database as root?", Severity: "high", Requires: openstackServices, Run: database.CheckDatabase02},
+	{ID: "database-03", Service: "Database", Description: "Does each service use its own database credentials?", Requires: openstackServices, Run: database.CheckDatabase03},
+	{ID: "database-04", Service: "Database", Description: "Are MySQL option files (my.cnf) protected from other users?", Requires: []string{"mysql"}, Run: database.CheckDatabase04},
+	{ID: "database-05", Service: "Database", Description: "Is the database server bind-address restricted?", Requires: []string{"mysql"}, Run: database.CheckDatabase05},
+	{ID: "database-06", Service: "Database", Description: "Is require_secure_transport enabled on the database server?", Requires: []string{"mysql"}, Run: database.CheckDatabase06},
+	{ID: "database-07", Service: "Database", Description: "Are anonymous database users removed?", Severity: "high", Requires: []string{"mysql"}, Run: database.CheckDatabase07},
+
+	{ID: "hypervisor-01", Service: "Hypervisor", Description: "Is remote access to libvirtd restricted to authenticated TLS connections?", Severity: "high", Requires: []string{"libvirt"}, Run: hypervisor.CheckHypervisor01},
+	{ID: "hypervisor-02", Service: "Hypervisor", Description: "Is a QEMU security_driver (SELinux/AppArmor) enabled?", Requires: []string{"libvirt"}, Run: hypervisor.CheckHypervisor02},
+	{ID: "hypervisor-03", Service: "Hypervisor", Description: "Are running QEMU processes confined by sVirt?", Requires: []string{"libvirt"}, Run: hypervisor.CheckHypervisor03},
+	{ID: "hypervisor-04", Service: "Hypervisor", Description: "Are strict permissions set for /var/lib/nova/instances?", Requires: []string{"nova"}, Run: hypervisor.CheckHypervisor04},
+	{ID: "hypervisor-05", Service: "Hypervisor", Description: "Is Kernel Samepage Merging (KSM) disabled?", Requires: []string{"libvirt"}, Run: hypervisor.CheckHypervisor05},
+	{ID: "hypervisor-06", Service: "Hypervisor", Description: "Is TLS enabled for VNC/SPICE consoles?", Requires: []string{"nova", "libvirt"}, Run: hypervisor.CheckHypervisor06},
+
+	{ID: "leakage-01", Service: "Secret Leakage", Description: "Are plaintext secrets in service configuration files protected?", Severity: "high", Requires: openstackServices, Run: leakage.CheckLeakage01},
+	{ID: "leakage-02", Service: "Secret Leakage", Description: "Are service logs free of plaintext secrets?", Severity: "high", Requires: openstackServices, Run: leakage.CheckLeakage02},
+	{ID: "leakage-03", Service: "Secret Leakage", Description: "Are there no stale backup copies of configuration files?", Severity: "low", Requires: openstackServices, Run: leakage.CheckLeakage03},
+	{ID: "leakage-04", Service: "Secret Leakage", Description: "Is debug logging disabled for every service?", Requires: openstackServices, Run: leakage.CheckLeakage04},
+
+	{ID: "policy-01", Service: "Policy", Description: "Are policy files free of overly permissive rules?", Severity: "high", Requires: openstackServices, Run: policy.CheckPolicy01},
+	{ID: "policy-02", Service: "Policy", Description: "Are policy files free of deprecated rule overrides?", Requires: openstackServices, Defaults: []ReleaseDefaults{{Params: checklist.Params{"json_deprecated": "false"}}, {Since: "2021.1", Params: checklist.Params{"json_deprecated": "true"}}}, RunParams: policy.CheckPolicy02WithParams},
+	{ID: "policy-03", Service: "Policy", Description: "Are enforce_scope and enforce_new_defaults enabled in [oslo_policy]?", Requires: openstackServices, Releases: ReleaseRange{Since: "2020.1", Reason: "enforce_new_defaults was introduced in Ussuri"}, Defaults: []ReleaseDefaults{{Params: checklist.Params{"enabled_by_default": "false"}}, {Since: "2024.1", Params: checklist.Params{"enabled_by_default": "true"}}}, RunParams: policy.CheckPolicy03WithParams},
+
+	{ID: "cloud-01", Service: "Cloud API", Description: "Is the admin role limited to a few projects per user?", RunCloud: cloud.CheckCloud01},
+	{ID: "cloud-02", Service: "Cloud API", Description: "Is multi-factor authentication enforced for administrators?", Severity: "high", RunCloud: cloud.CheckCloud02},
+	{ID: "cloud-03", Service: "Cloud API", Description: "Do security groups block SSH/RDP from 0.0.0.0/0?", Severity: "high", RunCloud: cloud.CheckCloud03},
+	{ID: "cloud-04", Service: "Cloud API", Description: "Are public images published by the cloud administrators only?", RunCloud: cloud.CheckCloud04},
+	{ID: "cloud-05", Service: "Cloud API", Description: "Are all volumes encrypted?", RunCloud: cloud.CheckCloud05},
+	{ID: "cloud-06", Service: "Cloud API", Description: "Are instances with floating IPs protected by security groups?", RunCloud: cloud.CheckCloud06},
+}
+
+// Checks returns every registered check in report order
+func Checks() []Check {
+	return registry
+}
+
+// Lookup returns the registered check with the given ID
+func Lookup(id string) (Check, bool) {
+	for _, check := range registry {
+		if check.ID == id {
+			return check, true
+		}
+	}
+	return Check{}, false
+}
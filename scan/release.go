@@ -0,0 +1,131 @@
+// scan/release.go
+package scan
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gunh0/openstack-security-hub/checklist"
+)
+
+// releaseNames maps release code names to their year based version
+var releaseNames = map[string]string{
+	"kilo":      "2015.1",
+	"liberty":   "2015.2",
+	"mitaka":    "2016.1",
+	"newton":    "2016.2",
+	"ocata":     "2017.1",
+	"pike":      "2017.2",
+	"queens":    "2018.1",
+	"rocky":     "2018.2",
+	"stein":     "2019.1",
+	"train":     "2019.2",
+	"ussuri":    "2020.1",
+	"victoria":  "2020.2",
+	"wallaby":   "2021.1",
+	"xena":      "2021.2",
+	"yoga":      "2022.1",
+	"zed":       "2022.2",
+	"antelope":  "2023.1",
+	"bobcat":    "2023.2",
+	"caracal":   "2024.1",
+	"dalmatian": "2024.2",
+	"epoxy":     "2025.1",
+	"flamingo":  "2025.2",
+	"gazpacho":  "2026.1",
+}
+
+// ReleaseRange limits a check to the releases from Since to Until inclusive; empty bounds are open
+type ReleaseRange struct {
+	Since  string
+	Until  string
+	Reason string
+}
+
+// ReleaseDefaults are the expected values of a check from release Since onwards
+type ReleaseDefaults struct {
+	Since  string
+	Params checklist.Params
+}
+
+// NormalizeRelease returns the year based version (e.g. 2024.1) of a release given by version or code name
+func NormalizeRelease(release string) (string, error) {
+	release = strings.ToLower(strings.TrimSpace(release))
+	if version, ok := releaseNames[release]; ok {
+		return version, nil
+	}
+	if _, _, ok := parseRelease(release); ok {
+		return release, nil
+	}
+	return "", fmt.Errorf("unknown OpenStack release %q (use e.g. 2024.1 or caracal)", release)
+}
+
+// parseRelease splits a year based version into year and sequence
+func parseRelease(release string) (int, int, bool) {
+	year, seq, found := strings.Cut(release, ".")
+	if !found {
+		return 0, 0, false
+	}
+	y, err := strconv.Atoi(year)
+	if err != nil || y < 2010 {
+		return 0, 0, false
+	}
+	s, err := strconv.Atoi(seq)
+	if err != nil || s < 1 || s > 2 {
+		return 0, 0, false
+	}
+	return y, s, true
+}
+
+// compareReleases returns -1, 0 or 1 when a is older, equal or newer than b
+func compareReleases(a, b string) int {
+	ay, as, _ := parseRelease(a)
+	by, bs, _ := parseRelease(b)
+	switch {
+	case ay != by:
+		if ay < by {
+			return -1
+		}
+		return 1
+	case as != bs:
+		if as < bs {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// releaseReason returns why check does not apply to release, or "" when it does.
+// An unknown release is treated as the latest one.
+func releaseReason(check Check, release string) string {
+	if release == "" {
+		if check.Releases.Until == "" {
+			return ""
+		}
+		return fmt.Sprintf("only applies up to %s and the release is unknown (assuming latest): %s", check.Releases.Until, check.Releases.Reason)
+	}
+	if check.Releases.Since != "" && compareReleases(release, check.Releases.Since) < 0 {
+		return fmt.Sprintf("only applies from %s, release is %s: %s", check.Releases.Since, release, check.Releases.Reason)
+	}
+	if check.Releases.Until != "" && compareReleases(release, check.Releases.Until) > 0 {
+		return fmt.Sprintf("only applies up to %s, release is %s: %s", check.Releases.Until, release, check.Releases.Reason)
+	}
+	return ""
+}
+
+// releaseParams merges the defaults of check that apply to release, newest last.
+// An unknown release gets the defaults of the latest one.
+func releaseParams(check Check, release string) checklist.Params {
+	params := checklist.Params{}
+	for _, defaults := range check.Defaults {
+		if release != "" && defaults.Since != "" && compareReleases(release, defaults.Since) < 0 {
+			continue
+		}
+		for name, value := range defaults.Params {
+			params[name] = value
+		}
+	}
+	return params
+}
@@ -0,0 +1,441 @@
+// scan/scan.go
+package scan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/openstack"
+	"github.com/gunh0/openstack-security-hub/util"
+	"golang.org/x/crypto/ssh"
+)
+
+// CloudTarget is the report target name used for the OpenStack API checks
+const CloudTarget = "cloud"
+
+// Version is the version of OpenStack Security Hub, set at build time with
+// -ldflags "-X github.com/gunh0/openstack-security-hub/scan.Version=..."
+var Version = "dev"
+
+// Report is the outcome of scanning one target
+type Report struct {
+	Target     string     `json:"target"`
+	Discovery  *Discovery `json:"discovery,omitempty"`
+	Release    string     `json:"release,omitempty"`
+	Profile    string     `json:"profile,omitempty"`
+	Framework  string     `json:"framework,omitempty"`
+	Results    []Result   `json:"results"`
+	Skipped    []Skipped  `json:"skipped,omitempty"`
+	StartedAt  string     `json:"started_at"`
+	FinishedAt string     `json:"finished_at"`
+}
+
+// Result is a check result tagged with the check that produced it
+type Result struct {
+	ID       string `json:"id"`
+	Service  string `json:"service"`
+	Severity string `json:"severity"`
+
+	// Controls lists the controls of the selected framework that the check addresses
+	Controls []string `json:"controls,omitempty"`
+
+	// Waiver is the waiver that turned a failure into [WAIVED], or the expired waiver of a failure
+	Waiver *AppliedWaiver `json:"waiver,omitempty"`
+	checklist.CheckResult
+}
+
+// Skipped records a check that was not run and why
+type Skipped struct {
+	ID          string `json:"id"`
+	Service     string `json:"service"`
+	Description string `json:"description"`
+	Reason      string `json:"reason"`
+}
+
+// Event types reported to Options.Progress
+const (
+	EventConnected        = "connected"
+	EventConnectionFailed = "connection_failed"
+	EventCheckStarted     = "check_started"
+	EventResult           = "result"
+	EventSkipped          = "skipped"
+	EventDisconnected     = "disconnected"
+	EventTargetFinished   = "target_finished"
+)
+
+// Event reports the progress of a scan: a connection to a target, a check starting, a check result
+// or skipped check of a target, or a finished target
+type Event struct {
+	Type   string `json:"type"`
+	Target string `json:"target,omitempty"`
+
+	// Check and Description identify the check of check_started events
+	Check       string `json:"check,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Result  *Result  `json:"result,omitempty"`
+	Skipped *Skipped `json:"skipped,omitempty"`
+
+	// Error explains a connection_failed event
+	Error string `json:"error,omitempty"`
+}
+
+// Options controls which checks are run
+type Options struct {
+	// NoDiscovery runs every host check regardless of the services found on the host
+	NoDiscovery bool
+
+	// Release overrides the detected release (e.g. 2024.1) when selecting checks and expected values
+	Release string
+
+	// Profile overrides expected values, enablement and severity of checks
+	Profile *Profile
+
+	// Framework limits the scan to the checks mapped to a compliance framework (e.g. nist-800-53)
+	// and tags each result with the controls it addresses
+	Framework string
+
+	// Checks limits the scan to the checks matching one of these IDs or glob patterns (e.g. identity-01-*)
+	Checks []string
+
+	// Waivers turn the failures they cover into [WAIVED] until they expire
+	Waivers []Waiver
+
+	// CheckTimeout stops a check, and the discovery of a host, still running after this long; zero means no limit
+	CheckTimeout time.Duration
+
+	// Connect opens the SSH connection to a target, e.g. a named target; the client must be closed once
+	// ctx is done. Nil connects to targets as host:port with SSH_USER and SSH_PASSWORD.
+	Connect func(ctx context.Context, target string) (*ssh.Client, error)
+
+	// Progress is called for every connection, check and finished target as the scan advances
+	Progress func(Event)
+}
+
+// ValidateChecks returns an error when a check ID or pattern matches no registered check
+func ValidateChecks(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid check pattern %q: %v", pattern, err)
+		}
+		found := false
+		for _, check := range registry {
+			if ok, _ := path.Match(pattern, check.ID); ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no check matches %q", pattern)
+		}
+	}
+	return nil
+}
+
+// Run scans every host over SSH, then the OpenStack APIs when credentials are configured.
+// Once ctx is done the running check is stopped, the remaining checks are skipped and the remaining targets left out.
+func Run(ctx context.Context, hosts []string, opts Options) []Report {
+	reports := []Report{}
+
+	for _, host := range hosts {
+		if ctx.Err() != nil {
+			return reports
+		}
+
+		client, err := opts.connect(ctx, host)
+		if ctx.Err() != nil {
+			return reports
+		}
+		if err != nil {
+			opts.emit(Event{Type: EventConnectionFailed, Target: host, Error: err.Error()})
+			report := Report{
+				Target:    host,
+				Profile:   opts.Profile.name(),
+				Framework: opts.Framework,
+				StartedAt: now(),
+			}
+			opts.addResult(&report, errorResult("connection", "SSH connection", fmt.Sprintf("Failed to connect to server: %v", err)))
+			report.FinishedAt = now()
+			reports = append(reports, report)
+			opts.emit(Event{Type: EventTargetFinished, Target: host})
+			continue
+		}
+		opts.emit(Event{Type: EventConnected, Target: host})
+		reports = append(reports, Host(ctx, host, client, opts))
+		client.Close()
+		opts.emit(Event{Type: EventDisconnected, Target: host})
+		opts.emit(Event{Type: EventTargetFinished, Target: host})
+	}
+
+	if ctx.Err() != nil || !opts.selectsCloud() {
+		return reports
+	}
+
+	cloudClient, err := util.GetOpenStackClient()
+	if err != nil {
+		opts.emit(Event{Type: EventConnectionFailed, Target: CloudTarget, Error: err.Error()})
+		reports = append(reports, CloudSkipped(fmt.Sprintf("OpenStack API not available: %v", err), opts))
+	} else {
+		opts.emit(Event{Type: EventConnected, Target: CloudTarget})
+		reports = append(reports, Cloud(ctx, cloudClient, opts))
+		cloudClient.Close()
+		opts.emit(Event{Type: EventDisconnected, Target: CloudTarget})
+	}
+	opts.emit(Event{Type: EventTargetFinished, Target: CloudTarget})
+	return reports
+}
+
+// Host discovers the services of a host and runs the applicable host checks over SSH.
+// A check running past opts.CheckTimeout is reported as [TIMEOUT]; the following checks reconnect to target.
+func Host(ctx context.Context, target string, client *ssh.Client, opts Options) Report {
+	report := Report{Target: target, Profile: opts.Profile.name(), Framework: opts.Framework, StartedAt: now()}
+	conn := &connection{ctx: ctx, target: target, client: client, connect: opts.connect}
+	defer conn.close()
+
+	var discovery *Discovery
+	if !opts.NoDiscovery {
+		discoverCtx, cancel := checkContext(ctx, opts.CheckTimeout)
+		var err error
+		if runErr := conn.run(discoverCtx, func(client *ssh.Client) {
+			discovery, err = Discover(client)
+		}); runErr != nil {
+			err = runErr
+		}
+		cancel()
+		if err != nil {
+			r := errorResult("discovery", "Service discovery", err.Error())
+			if errors.Is(err, context.DeadlineExceeded) {
+				r.Result = Timeout
+				r.Details = "Service discovery did not finish in time"
+			} else if errors.Is(err, context.Canceled) {
+				r.Details = "Service discovery stopped: " + stopReason(ctx)
+			}
+			opts.addResult(&report, r)
+			report.FinishedAt = now()
+			return report
+		}
+		report.Discovery = discovery
+	}
+
+	report.Release = opts.Release
+	if report.Release == "" && discovery != nil {
+		report.Release = discovery.Release
+	}
+
+	for _, check := range registry {
+		if check.IsCloud() || !opts.selects(check) {
+			continue
+		}
+		if ctx.Err() != nil {
+			opts.addSkipped(&report, skip(check, stopReason(ctx)))
+			continue
+		}
+		if discovery != nil {
+			if reason := notApplicable(check, discovery); reason != "" {
+				opts.addSkipped(&report, skip(check, reason))
+				continue
+			}
+		}
+		if reason := releaseReason(check, report.Release); reason != "" {
+			opts.addSkipped(&report, skip(check, reason))
+			continue
+		}
+		if !opts.Profile.Enabled(check) {
+			opts.addSkipped(&report, skip(check, fmt.Sprintf("disabled by profile %s", report.Profile)))
+			continue
+		}
+		params := opts.Profile.Params(check, releaseParams(check, report.Release))
+		opts.emit(Event{Type: EventCheckStarted, Target: target, Check: check.ID, Description: check.Description})
+
+		checkCtx, cancel := checkContext(ctx, opts.CheckTimeout)
+		var checkResult checklist.CheckResult
+		err := conn.run(checkCtx, func(client *ssh.Client) {
+			checkResult = runHostCheck(check, client, params)
+		})
+		cancel()
+		switch {
+		case err == nil:
+			opts.addResult(&report, result(check, opts, checkResult))
+		case errors.Is(err, context.DeadlineExceeded):
+			opts.addResult(&report, timeoutResult(ctx, check, opts, opts.CheckTimeout))
+		case errors.Is(err, context.Canceled):
+			opts.addSkipped(&report, skip(check, stopReason(ctx)))
+		default:
+			opts.addResult(&report, result(check, opts, checklist.CheckResult{Description: check.Description, Result: "[ERROR]", Details: err.Error()}))
+		}
+	}
+
+	report.FinishedAt = now()
+	return report
+}
+
+// Cloud runs the OpenStack API checks
+func Cloud(ctx context.Context, client *openstack.Client, opts Options) Report {
+	report := Report{Target: CloudTarget, Release: opts.Release, Profile: opts.Profile.name(), Framework: opts.Framework, StartedAt: now()}
+
+	for _, check := range registry {
+		if !check.IsCloud() || !opts.selects(check) {
+			continue
+		}
+		if ctx.Err() != nil {
+			opts.addSkipped(&report, skip(check, stopReason(ctx)))
+			continue
+		}
+		if reason := releaseReason(check, report.Release); reason != "" {
+			opts.addSkipped(&report, skip(check, reason))
+			continue
+		}
+		if !opts.Profile.Enabled(check) {
+			opts.addSkipped(&report, skip(check, fmt.Sprintf("disabled by profile %s", report.Profile)))
+			continue
+		}
+		opts.emit(Event{Type: EventCheckStarted, Target: CloudTarget, Check: check.ID, Description: check.Description})
+
+		// A check cannot be interrupted; a check past its deadline is left to run in the background
+		// until its requests time out or the caller closes client, which fails them
+		checkCtx, cancel := checkContext(ctx, opts.CheckTimeout)
+		done := make(chan checklist.CheckResult, 1)
+		go func(check Check) {
+			done <- check.RunCloud(client)
+		}(check)
+		select {
+		case checkResult := <-done:
+			opts.addResult(&report, result(check, opts, checkResult))
+		case <-checkCtx.Done():
+			if errors.Is(checkCtx.Err(), context.DeadlineExceeded) {
+				opts.addResult(&report, timeoutResult(ctx, check, opts, opts.CheckTimeout))
+			} else {
+				opts.addSkipped(&report, skip(check, stopReason(ctx)))
+			}
+		}
+		cancel()
+	}
+
+	report.FinishedAt = now()
+	return report
+}
+
+// CloudSkipped reports every selected OpenStack API check as skipped for reason
+func CloudSkipped(reason string, opts Options) Report {
+	report := Report{Target: CloudTarget, Release: opts.Release, Profile: opts.Profile.name(), Framework: opts.Framework, StartedAt: now()}
+
+	for _, check := range registry {
+		if check.IsCloud() && opts.selects(check) {
+			opts.addSkipped(&report, skip(check, reason))
+		}
+	}
+
+	report.FinishedAt = report.StartedAt
+	return report
+}
+
+// notApplicable returns why check does not apply to the discovered host, or "" when it does
+func notApplicable(check Check, discovery *Discovery) string {
+	for _, service := range check.Requires {
+		if discovery.Has(service) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("none of the required services found on host (%s)", strings.Join(check.Requires, ", "))
+}
+
+// runHostCheck runs check with the given expected values
+func runHostCheck(check Check, client *ssh.Client, params checklist.Params) checklist.CheckResult {
+	if check.RunParams != nil {
+		return check.RunParams(client, params)
+	}
+	return check.Run(client)
+}
+
+// selects reports whether check matches the selected checks and is mapped to the selected framework
+func (o Options) selects(check Check) bool {
+	if o.Framework != "" && len(check.Controls(o.Framework)) == 0 {
+		return false
+	}
+	if len(o.Checks) == 0 {
+		return true
+	}
+	for _, pattern := range o.Checks {
+		if ok, _ := path.Match(pattern, check.ID); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// selectsCloud reports whether any OpenStack API check is selected
+func (o Options) selectsCloud() bool {
+	for _, check := range registry {
+		if check.IsCloud() && o.selects(check) {
+			return true
+		}
+	}
+	return false
+}
+
+// connect opens the SSH connection to target with Connect, or as host:port with the credentials from the environment
+func (o Options) connect(ctx context.Context, target string) (*ssh.Client, error) {
+	if o.Connect != nil {
+		return o.Connect(ctx, target)
+	}
+	return util.GetSSHClientForHostContext(ctx, target)
+}
+
+func (o Options) emit(event Event) {
+	if o.Progress != nil {
+		o.Progress(event)
+	}
+}
+
+// addResult applies the waivers to r, then appends it to report and reports it to Progress
+func (o Options) addResult(report *Report, r Result) {
+	waive(o.Waivers, report.Target, &r)
+	report.Results = append(report.Results, r)
+	o.emit(Event{Type: EventResult, Target: report.Target, Result: &r})
+}
+
+// addSkipped appends skipped to report and reports it to Progress
+func (o Options) addSkipped(report *Report, skipped Skipped) {
+	report.Skipped = append(report.Skipped, skipped)
+	o.emit(Event{Type: EventSkipped, Target: report.Target, Skipped: &skipped})
+}
+
+func result(check Check, opts Options, checkResult checklist.CheckResult) Result {
+	if checkResult.Timestamp == "" {
+		checkResult.Timestamp = now()
+	}
+
+	r := Result{ID: check.ID, Service: check.Service, Severity: opts.Profile.Severity(check), CheckResult: checkResult}
+	if opts.Framework != "" {
+		r.Controls = check.Controls(opts.Framework)
+	}
+	return r
+}
+
+// errorResult reports a failure of the scan itself rather than of a check
+func errorResult(id, description, details string) Result {
+	return Result{
+		ID:       id,
+		Service:  "Scan",
+		Severity: "high",
+		CheckResult: checklist.CheckResult{
+			Description: description,
+			Result:      "[ERROR]",
+			Details:     details,
+			Timestamp:   now(),
+		},
+	}
+}
+
+func skip(check Check, reason string) Skipped {
+	return Skipped{ID: check.ID, Service: check.Service, Description: check.Description, Reason: reason}
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
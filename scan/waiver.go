@@ -0,0 +1,197 @@
+// scan/waiver.go
+package scan
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Waived is the result of a failed check whose risk was accepted by an active waiver
+const Waived = "[WAIVED]"
+
+// Waiver accepts the failure of a check on some hosts until it expires
+type Waiver struct {
+	ID string `yaml:"id" json:"id"`
+
+	// Check is a check ID or glob pattern such as identity-01-*
+	Check string `yaml:"check" json:"check"`
+
+	// Hosts are the targets the waiver applies to, as hosts, host:port or glob patterns; empty means every target
+	Hosts []string `yaml:"hosts,omitempty" json:"hosts,omitempty"`
+
+	Justification string `yaml:"justification" json:"justification"`
+	Owner         string `yaml:"owner" json:"owner"`
+
+	// Expires is the last day the waiver applies, as YYYY-MM-DD
+	Expires string `yaml:"expires" json:"expires"`
+}
+
+// WaiverFile is the YAML document holding waivers
+type WaiverFile struct {
+	Waivers []Waiver `yaml:"waivers"`
+}
+
+// AppliedWaiver records the waiver matching a failed result. An expired waiver leaves the result failed.
+type AppliedWaiver struct {
+	ID            string `json:"id"`
+	Justification string `json:"justification"`
+	Owner         string `json:"owner"`
+	Expires       string `json:"expires"`
+	Expired       bool   `json:"expired,omitempty"`
+}
+
+// WaiverFilePath returns WAIVER_FILE, or waivers.yaml in the working directory
+func WaiverFilePath() string {
+	if file := os.Getenv("WAIVER_FILE"); file != "" {
+		return file
+	}
+	return "waivers.yaml"
+}
+
+// LoadWaivers reads and validates a YAML waiver file; a missing file has no waivers
+func LoadWaivers(file string) ([]Waiver, error) {
+	content, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read waivers: %v", err)
+	}
+
+	var waiverFile WaiverFile
+	if err := yaml.Unmarshal(content, &waiverFile); err != nil {
+		return nil, fmt.Errorf("invalid waivers %s: %v", file, err)
+	}
+	ids := map[string]bool{}
+	for i := range waiverFile.Waivers {
+		waiver := &waiverFile.Waivers[i]
+		if waiver.ID == "" {
+			waiver.ID = fmt.Sprintf("%s-%d", waiver.Check, i+1)
+		}
+		if ids[waiver.ID] {
+			return nil, fmt.Errorf("invalid waivers %s: duplicate waiver id %q", file, waiver.ID)
+		}
+		ids[waiver.ID] = true
+		if err := waiver.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid waivers %s: %v", file, err)
+		}
+	}
+	return waiverFile.Waivers, nil
+}
+
+// SaveWaivers writes waivers to a YAML waiver file
+func SaveWaivers(file string, waivers []Waiver) error {
+	content, err := yaml.Marshal(WaiverFile{Waivers: waivers})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, content, 0644)
+}
+
+// NewWaiverID returns a random waiver ID
+func NewWaiverID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return "waiver-" + hex.EncodeToString(b)
+}
+
+// Validate checks that the waiver names a registered check, a justification, an owner and an expiry date
+func (w Waiver) Validate() error {
+	if w.Check == "" {
+		return fmt.Errorf("waiver %s: check is required", w.ID)
+	}
+	if err := ValidateChecks([]string{w.Check}); err != nil {
+		return fmt.Errorf("waiver %s: %v", w.ID, err)
+	}
+	for _, host := range w.Hosts {
+		if _, err := path.Match(host, ""); err != nil {
+			return fmt.Errorf("waiver %s: bad host pattern %q: %v", w.ID, host, err)
+		}
+	}
+	if w.Justification == "" {
+		return fmt.Errorf("waiver %s: justification is required", w.ID)
+	}
+	if w.Owner == "" {
+		return fmt.Errorf("waiver %s: owner is required", w.ID)
+	}
+	if _, err := time.Parse("2006-01-02", w.Expires); err != nil {
+		return fmt.Errorf("waiver %s: expires must be a date as YYYY-MM-DD", w.ID)
+	}
+	return nil
+}
+
+// Expired reports whether the waiver no longer applies at now; it applies through its expiry day in UTC
+func (w Waiver) Expired(now time.Time) bool {
+	expires, err := time.Parse("2006-01-02", w.Expires)
+	if err != nil {
+		return true
+	}
+	return !now.UTC().Before(expires.AddDate(0, 0, 1))
+}
+
+// Status returns active or expired at now
+func (w Waiver) Status(now time.Time) string {
+	if w.Expired(now) {
+		return "expired"
+	}
+	return "active"
+}
+
+// Matches reports whether the waiver covers check id on target
+func (w Waiver) Matches(target, id string) bool {
+	if ok, _ := path.Match(w.Check, id); !ok {
+		return false
+	}
+	if len(w.Hosts) == 0 {
+		return true
+	}
+
+	host := target
+	if h, _, err := net.SplitHostPort(target); err == nil {
+		host = h
+	}
+	for _, pattern := range w.Hosts {
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, host); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// waive marks a failed result of target as waived when an active waiver covers it.
+// When only expired waivers cover it the result stays failed and records the expired waiver.
+func waive(waivers []Waiver, target string, r *Result) {
+	if r.Result != "[FAIL]" {
+		return
+	}
+
+	now := time.Now()
+	for _, waiver := range waivers {
+		if !waiver.Matches(target, r.ID) {
+			continue
+		}
+		applied := &AppliedWaiver{ID: waiver.ID, Justification: waiver.Justification, Owner: waiver.Owner, Expires: waiver.Expires}
+		if waiver.Expired(now) {
+			applied.Expired = true
+			if r.Waiver == nil {
+				r.Waiver = applied
+			}
+			continue
+		}
+		r.Result = Waived
+		r.Waiver = applied
+		return
+	}
+}
@@ -0,0 +1,143 @@
+// targets/crypto.go
+package targets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// encryptedPrefix marks a secret encrypted with AES-256-GCM, followed by base64(nonce | ciphertext)
+const encryptedPrefix = "enc:v1:"
+
+// keyring holds the key encrypting the secrets of the targets. The key file is read, or created,
+// only when a secret is first encrypted or decrypted.
+type keyring struct {
+	mu      sync.Mutex
+	keyFile string
+	aead    cipher.AEAD
+}
+
+// newKeyring returns the keyring of the base64 key, or of keyFile when key is empty
+func newKeyring(key, keyFile string) (*keyring, error) {
+	k := &keyring{keyFile: keyFile}
+	if key == "" {
+		return k, nil
+	}
+	raw, err := decodeKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TARGETS_KEY: %v", err)
+	}
+	if k.aead, err = newAEAD(raw); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// encrypt returns the encrypted form of the secret value, bound to name; empty values stay empty
+func (k *keyring) encrypt(value, name string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	aead, err := k.cipher(true)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(name))
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt returns the secret value encrypted by encrypt for name. Values written by hand in plain text
+// are returned as they are, and encrypted the next time the targets are saved.
+func (k *keyring) decrypt(value, name string) (string, error) {
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		return value, nil
+	}
+	aead, err := k.cipher(false)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted %s", name)
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt %s: wrong key?", name)
+	}
+	return string(plain), nil
+}
+
+// cipher returns the cipher of the key, reading the key file and creating it if create is set
+func (k *keyring) cipher(create bool) (cipher.AEAD, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.aead != nil {
+		return k.aead, nil
+	}
+
+	content, err := os.ReadFile(k.keyFile)
+	switch {
+	case err == nil:
+		raw, err := decodeKey(strings.TrimSpace(string(content)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid key file %s: %v", k.keyFile, err)
+		}
+		k.aead, err = newAEAD(raw)
+		return k.aead, err
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("failed to read key file: %v", err)
+	case !create:
+		return nil, fmt.Errorf("key file %s not found: set TARGETS_KEY or TARGETS_KEY_FILE to the key the targets were saved with", k.keyFile)
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	// O_EXCL keeps a key created meanwhile by another process
+	file, err := os.OpenFile(k.keyFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key file: %v", err)
+	}
+	if _, err := file.WriteString(base64.StdEncoding.EncodeToString(raw) + "\n"); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write key file: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write key file: %v", err)
+	}
+	k.aead, err = newAEAD(raw)
+	return k.aead, err
+}
+
+// decodeKey decodes a base64 AES-256 key
+func decodeKey(key string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, errors.New("the key must be base64 encoded")
+	}
+	if len(raw) != 32 {
+		return nil, fmt.Errorf("the key must be 32 bytes, got %d", len(raw))
+	}
+	return raw, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
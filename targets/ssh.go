@@ -0,0 +1,102 @@
+// targets/ssh.go
+package targets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gunh0/openstack-security-hub/util"
+	"golang.org/x/crypto/ssh"
+)
+
+// Connect returns an SSH client for the target with the given name, connected through its jump hosts.
+// Other names are connected to as host or host:port with SSH_USER and SSH_PASSWORD, as before targets
+// existed. ctx bounds the connection, and the client is closed once ctx is done.
+func (s *Store) Connect(ctx context.Context, name string) (*ssh.Client, error) {
+	list, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	index := map[string]Target{}
+	for _, t := range list {
+		index[t.Name] = t
+	}
+
+	t, ok := index[name]
+	if !ok {
+		return util.GetSSHClientForHostContext(ctx, name)
+	}
+	return dial(ctx, t, index)
+}
+
+// dial connects to t, first connecting to its jump host
+func dial(ctx context.Context, t Target, index map[string]Target) (*ssh.Client, error) {
+	config, err := t.clientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var via *ssh.Client
+	if t.JumpHost != "" {
+		jump, ok := index[t.JumpHost]
+		if !ok {
+			return nil, fmt.Errorf("target %s: unknown jump host %q", t.Name, t.JumpHost)
+		}
+		if via, err = dial(ctx, jump, index); err != nil {
+			return nil, fmt.Errorf("jump host %s: %w", jump.Name, err)
+		}
+	}
+	return util.DialSSH(ctx, t.Address(), config, via)
+}
+
+// clientConfig returns the SSH client configuration of the target
+func (t Target) clientConfig() (*ssh.ClientConfig, error) {
+	callback, err := util.HostKeyCallback(t.HostKey, t.KnownHosts)
+	if err != nil {
+		return nil, fmt.Errorf("target %s: %v", t.Name, err)
+	}
+	config := &ssh.ClientConfig{
+		User:            t.User,
+		HostKeyCallback: callback,
+	}
+
+	switch t.Auth.Method {
+	case AuthEnv, "":
+		if config.User == "" {
+			config.User = os.Getenv("SSH_USER")
+		}
+		config.Auth = []ssh.AuthMethod{ssh.Password(os.Getenv("SSH_PASSWORD"))}
+	case AuthPassword:
+		config.Auth = []ssh.AuthMethod{ssh.Password(t.Auth.Password)}
+	case AuthPrivateKey, AuthKeyFile:
+		key := []byte(t.Auth.PrivateKey)
+		if t.Auth.Method == AuthKeyFile {
+			if key, err = os.ReadFile(t.Auth.KeyFile); err != nil {
+				return nil, fmt.Errorf("target %s: failed to read key file: %v", t.Name, err)
+			}
+		}
+		signer, err := parsePrivateKey(key, t.Auth.Passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("target %s: invalid private key: %v", t.Name, err)
+		}
+		config.Auth = []ssh.AuthMethod{ssh.PublicKeys(signer)}
+	default:
+		return nil, fmt.Errorf("target %s: unknown auth method %q", t.Name, t.Auth.Method)
+	}
+	return config, nil
+}
+
+// parsePrivateKey parses a PEM private key, decrypting it with passphrase when it is protected
+func parsePrivateKey(key []byte, passphrase string) (ssh.Signer, error) {
+	if passphrase != "" {
+		return ssh.ParsePrivateKeyWithPassphrase(key, []byte(passphrase))
+	}
+	signer, err := ssh.ParsePrivateKey(key)
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) {
+		return nil, errors.New("the key is protected by a passphrase, none was given")
+	}
+	return signer, err
+}
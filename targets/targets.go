@@ -0,0 +1,412 @@
+// targets/targets.go
+package targets
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrNotFound is returned for an unknown target name
+	ErrNotFound = errors.New("target not found")
+
+	// ErrExists is returned when creating a target whose name is taken
+	ErrExists = errors.New("target already exists")
+
+	// ErrInUse is returned when deleting a target that is the jump host of another target
+	ErrInUse = errors.New("target is the jump host of another target")
+
+	// ErrInvalid wraps the validation errors of the targets being saved
+	ErrInvalid = errors.New("invalid target")
+)
+
+// Authentication methods of a target
+const (
+	// AuthEnv uses SSH_USER and SSH_PASSWORD from the environment, as unnamed targets do
+	AuthEnv = "env"
+
+	// AuthPassword uses the stored password
+	AuthPassword = "password"
+
+	// AuthPrivateKey uses the stored PEM private key, decrypted with the passphrase if any
+	AuthPrivateKey = "private_key"
+
+	// AuthKeyFile uses a private key file on the machine running the scans, decrypted with the passphrase if any
+	AuthKeyFile = "key_file"
+)
+
+// Target is a named host to scan over SSH
+type Target struct {
+	Name string `yaml:"name" json:"name"`
+	Host string `yaml:"host" json:"host"`
+
+	// Port defaults to 22
+	Port int    `yaml:"port,omitempty" json:"port,omitempty"`
+	User string `yaml:"user,omitempty" json:"user,omitempty"`
+	Auth Auth   `yaml:"auth" json:"auth"`
+
+	// Role is the part the host plays in the cloud, e.g. controller, compute or storage
+	Role string   `yaml:"role,omitempty" json:"role,omitempty"`
+	Tags []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+
+	// JumpHost is the name of the target to connect through
+	JumpHost string `yaml:"jump_host,omitempty" json:"jump_host,omitempty"`
+
+	// HostKey pins the host key by its SHA256 fingerprint, e.g. SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8.
+	// Without it the host key must be in the KnownHosts file, or in SSH_KNOWN_HOSTS (default ~/.ssh/known_hosts).
+	HostKey    string `yaml:"host_key,omitempty" json:"host_key,omitempty"`
+	KnownHosts string `yaml:"known_hosts,omitempty" json:"known_hosts,omitempty"`
+
+	CreatedAt string `yaml:"created_at" json:"created_at"`
+	UpdatedAt string `yaml:"updated_at" json:"updated_at"`
+}
+
+// Auth selects how to authenticate to a target. Password, PrivateKey and Passphrase are secrets:
+// they are encrypted in the target file and never returned by the API.
+type Auth struct {
+	// Method is env (default), password, private_key or key_file
+	Method     string `yaml:"method" json:"method"`
+	Password   string `yaml:"password,omitempty" json:"password,omitempty"`
+	PrivateKey string `yaml:"private_key,omitempty" json:"private_key,omitempty"`
+	KeyFile    string `yaml:"key_file,omitempty" json:"key_file,omitempty"`
+	Passphrase string `yaml:"passphrase,omitempty" json:"passphrase,omitempty"`
+}
+
+// TargetFile is the YAML document holding the targets
+type TargetFile struct {
+	Targets []Target `yaml:"targets"`
+}
+
+// namePattern keeps target names apart from host:port addresses
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Address returns the host:port of the target
+func (t Target) Address() string {
+	port := t.Port
+	if port == 0 {
+		port = 22
+	}
+	return net.JoinHostPort(t.Host, strconv.Itoa(port))
+}
+
+// Redacted returns the target with its secrets replaced by a placeholder
+func (t Target) Redacted() Target {
+	for _, secret := range t.Auth.secrets() {
+		if *secret.value != "" {
+			*secret.value = "********"
+		}
+	}
+	return t
+}
+
+// Validate checks the name, address, authentication and jump host of the target
+func (t Target) Validate() error {
+	if !namePattern.MatchString(t.Name) {
+		return fmt.Errorf("name %q must start with a letter or digit and use letters, digits, '.', '_' and '-'", t.Name)
+	}
+	if t.Host == "" {
+		return fmt.Errorf("target %s: host is required", t.Name)
+	}
+	if t.Port < 0 || t.Port > 65535 {
+		return fmt.Errorf("target %s: invalid port %d", t.Name, t.Port)
+	}
+	if t.JumpHost == t.Name {
+		return fmt.Errorf("target %s: a target cannot be its own jump host", t.Name)
+	}
+	if t.HostKey != "" && !strings.HasPrefix(t.HostKey, "SHA256:") {
+		return fmt.Errorf("target %s: host_key must be a SHA256 fingerprint (SHA256:...), as printed by ssh-keygen -l", t.Name)
+	}
+	if t.HostKey != "" && t.KnownHosts != "" {
+		return fmt.Errorf("target %s: set host_key or known_hosts, not both", t.Name)
+	}
+
+	auth := t.Auth
+	if auth.Method != AuthEnv && t.User == "" {
+		return fmt.Errorf("target %s: user is required with %s authentication", t.Name, auth.Method)
+	}
+	switch auth.Method {
+	case AuthEnv:
+	case AuthPassword:
+		if auth.Password == "" {
+			return fmt.Errorf("target %s: password is required", t.Name)
+		}
+	case AuthPrivateKey:
+		if auth.PrivateKey == "" {
+			return fmt.Errorf("target %s: private_key is required", t.Name)
+		}
+	case AuthKeyFile:
+		if auth.KeyFile == "" {
+			return fmt.Errorf("target %s: key_file is required", t.Name)
+		}
+	default:
+		return fmt.Errorf("target %s: unknown auth method %q (use env, password, private_key or key_file)", t.Name, auth.Method)
+	}
+	return nil
+}
+
+// Store keeps the targets in a YAML file with their secrets encrypted
+type Store struct {
+	mu   sync.Mutex
+	file string
+	keys *keyring
+}
+
+// Open returns the store of the target file, encrypting secrets with the key in keyFile.
+// key, when set, is the base64 encryption key and takes precedence over keyFile.
+func Open(file, key, keyFile string) (*Store, error) {
+	keys, err := newKeyring(key, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{file: file, keys: keys}, nil
+}
+
+// FilePath returns TARGETS_FILE, or targets.yaml in the working directory
+func FilePath() string {
+	if file := os.Getenv("TARGETS_FILE"); file != "" {
+		return file
+	}
+	return "targets.yaml"
+}
+
+// KeyFilePath returns TARGETS_KEY_FILE, or targets.key in the working directory. The key file is
+// created when the first secret is saved unless TARGETS_KEY holds the key.
+func KeyFilePath() string {
+	if file := os.Getenv("TARGETS_KEY_FILE"); file != "" {
+		return file
+	}
+	return "targets.key"
+}
+
+// OpenFromEnv opens the store of FilePath with the key TARGETS_KEY, or the key in KeyFilePath
+func OpenFromEnv() (*Store, error) {
+	return Open(FilePath(), os.Getenv("TARGETS_KEY"), KeyFilePath())
+}
+
+// List returns every target sorted by name, with its secrets
+func (s *Store) List() ([]Target, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Get returns the target with the given name, with its secrets
+func (s *Store) Get(name string) (Target, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.load()
+	if err != nil {
+		return Target{}, err
+	}
+	for _, t := range list {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return Target{}, ErrNotFound
+}
+
+// Create adds a new target
+func (s *Store) Create(t Target) (Target, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.load()
+	if err != nil {
+		return Target{}, err
+	}
+	for _, existing := range list {
+		if existing.Name == t.Name {
+			return Target{}, ErrExists
+		}
+	}
+
+	t = normalize(t)
+	t.CreatedAt = now()
+	t.UpdatedAt = t.CreatedAt
+	return t, s.save(append(list, t))
+}
+
+// Put creates or replaces the target named t.Name. Secrets left empty keep their stored value
+// when the authentication method is unchanged.
+func (s *Store) Put(t Target) (Target, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.load()
+	if err != nil {
+		return Target{}, err
+	}
+
+	t = normalize(t)
+	t.CreatedAt = now()
+	t.UpdatedAt = t.CreatedAt
+	replaced := false
+	for i, existing := range list {
+		if existing.Name != t.Name {
+			continue
+		}
+		t.CreatedAt = existing.CreatedAt
+		if existing.Auth.Method == t.Auth.Method {
+			t.Auth.keepSecrets(existing.Auth)
+		}
+		list[i] = t
+		replaced = true
+	}
+	if !replaced {
+		list = append(list, t)
+	}
+	return t, s.save(list)
+}
+
+// Delete removes the target with the given name
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.load()
+	if err != nil {
+		return err
+	}
+	kept := list[:0]
+	found := false
+	for _, t := range list {
+		if t.JumpHost == name {
+			return fmt.Errorf("%w: %s", ErrInUse, t.Name)
+		}
+		if t.Name == name {
+			found = true
+			continue
+		}
+		kept = append(kept, t)
+	}
+	if !found {
+		return ErrNotFound
+	}
+	return s.save(kept)
+}
+
+// load reads and decrypts the targets; a missing file has no targets
+func (s *Store) load() ([]Target, error) {
+	content, err := os.ReadFile(s.file)
+	if os.IsNotExist(err) {
+		return []Target{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read targets: %v", err)
+	}
+
+	var targetFile TargetFile
+	if err := yaml.Unmarshal(content, &targetFile); err != nil {
+		return nil, fmt.Errorf("invalid targets %s: %v", s.file, err)
+	}
+	for i := range targetFile.Targets {
+		t := &targetFile.Targets[i]
+		for _, secret := range t.Auth.secrets() {
+			if *secret.value, err = s.keys.decrypt(*secret.value, t.Name+"/"+secret.name); err != nil {
+				return nil, fmt.Errorf("invalid targets %s: target %s: %v", s.file, t.Name, err)
+			}
+		}
+	}
+	sort.Slice(targetFile.Targets, func(i, j int) bool {
+		return targetFile.Targets[i].Name < targetFile.Targets[j].Name
+	})
+	return targetFile.Targets, nil
+}
+
+// save validates the targets and their jump hosts, encrypts their secrets and writes the target file
+func (s *Store) save(list []Target) error {
+	if err := validateAll(list); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	encrypted := make([]Target, len(list))
+	for i, t := range list {
+		t.Tags = append([]string(nil), t.Tags...)
+		for _, secret := range t.Auth.secrets() {
+			value, err := s.keys.encrypt(*secret.value, t.Name+"/"+secret.name)
+			if err != nil {
+				return err
+			}
+			*secret.value = value
+		}
+		encrypted[i] = t
+	}
+
+	content, err := yaml.Marshal(TargetFile{Targets: encrypted})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.file, content, 0600)
+}
+
+// validateAll validates each target and checks that jump hosts exist and do not loop
+func validateAll(list []Target) error {
+	index := map[string]Target{}
+	for _, t := range list {
+		if err := t.Validate(); err != nil {
+			return err
+		}
+		index[t.Name] = t
+	}
+	for _, t := range list {
+		seen := map[string]bool{t.Name: true}
+		for jump := t.JumpHost; jump != ""; jump = index[jump].JumpHost {
+			if _, ok := index[jump]; !ok {
+				return fmt.Errorf("target %s: unknown jump host %q", t.Name, jump)
+			}
+			if seen[jump] {
+				return fmt.Errorf("target %s: jump hosts loop through %s", t.Name, jump)
+			}
+			seen[jump] = true
+		}
+	}
+	return nil
+}
+
+// normalize applies the default authentication method
+func normalize(t Target) Target {
+	if t.Auth.Method == "" {
+		t.Auth.Method = AuthEnv
+	}
+	return t
+}
+
+type secret struct {
+	name  string
+	value *string
+}
+
+// secrets returns the secret fields of a, named as in the target file
+func (a *Auth) secrets() []secret {
+	return []secret{
+		{"password", &a.Password},
+		{"private_key", &a.PrivateKey},
+		{"passphrase", &a.Passphrase},
+	}
+}
+
+// keepSecrets fills the empty secrets of a with those of stored
+func (a *Auth) keepSecrets(stored Auth) {
+	previous := stored.secrets()
+	for i, secret := range a.secrets() {
+		if *secret.value == "" {
+			*secret.value = *previous[i].value
+		}
+	}
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
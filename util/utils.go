@@ -3,27 +3,166 @@ package util
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/gunh0/openstack-security-hub/checklist"
+	"github.com/gunh0/openstack-security-hub/openstack"
 	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
 )
 
 // GetSSHClient returns a new SSH client using environment variables for configuration
 func GetSSHClient() (*ssh.Client, error) {
-	config := &ssh.ClientConfig{
+	return GetSSHClientForHost(os.Getenv("SSH_HOST"))
+}
+
+// GetSSHClientForHost returns a new SSH client for host using the credentials from the environment
+func GetSSHClientForHost(host string) (*ssh.Client, error) {
+	config, err := sshConfig()
+	if err != nil {
+		return nil, err
+	}
+	return ssh.Dial("tcp", host, config)
+}
+
+// GetSSHClientContext returns a new SSH client for SSH_HOST that is closed once ctx is done
+func GetSSHClientContext(ctx context.Context) (*ssh.Client, error) {
+	return GetSSHClientForHostContext(ctx, os.Getenv("SSH_HOST"))
+}
+
+// GetSSHClientForHostContext returns a new SSH client for host. ctx bounds the connection, and the client
+// is closed once ctx is done, which ends the commands still running in its sessions.
+func GetSSHClientForHostContext(ctx context.Context, host string) (*ssh.Client, error) {
+	config, err := sshConfig()
+	if err != nil {
+		return nil, err
+	}
+	return DialSSH(ctx, host, config, nil)
+}
+
+// DialSSH connects to addr with config, through the jump host client via when it is not nil.
+// ctx bounds the connection, and the client is closed once ctx is done. Closing the client closes via.
+func DialSSH(ctx context.Context, addr string, config *ssh.ClientConfig, via *ssh.Client) (*ssh.Client, error) {
+	var (
+		conn net.Conn
+		err  error
+	)
+	if via != nil {
+		conn, err = via.DialContext(ctx, "tcp", addr)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		if via != nil {
+			via.Close()
+			err = fmt.Errorf("through jump host %s: %v", via.RemoteAddr(), err)
+		}
+		return nil, err
+	}
+	stop := context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		stop()
+		conn.Close()
+		if via != nil {
+			via.Close()
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+
+	client := ssh.NewClient(c, chans, reqs)
+	if via != nil {
+		go func() {
+			client.Wait()
+			via.Close()
+		}()
+	}
+	return client, nil
+}
+
+// sshConfig returns the SSH client configuration from SSH_USER and SSH_PASSWORD, accepting the host
+// keys of KnownHostsFile
+func sshConfig() (*ssh.ClientConfig, error) {
+	callback, err := HostKeyCallback("", "")
+	if err != nil {
+		return nil, err
+	}
+	return &ssh.ClientConfig{
 		User: os.Getenv("SSH_USER"),
 		Auth: []ssh.AuthMethod{
 			ssh.Password(os.Getenv("SSH_PASSWORD")),
 		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		HostKeyCallback: callback,
+	}, nil
+}
+
+// KnownHostsFile returns SSH_KNOWN_HOSTS, or ~/.ssh/known_hosts
+func KnownHostsFile() string {
+	if file := os.Getenv("SSH_KNOWN_HOSTS"); file != "" {
+		return file
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".ssh", "known_hosts")
+	}
+	return filepath.Join(home, ".ssh", "known_hosts")
+}
+
+// HostKeyCallback returns the check of the host key of a connection. A host key pinned by its SHA256
+// fingerprint (as printed by ssh-keygen -l) must match; otherwise the key must be listed for the host
+// in the known_hosts file, or in KnownHostsFile when file is empty.
+func HostKeyCallback(fingerprint, file string) (ssh.HostKeyCallback, error) {
+	if fingerprint != "" {
+		return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			if got := ssh.FingerprintSHA256(key); got != fingerprint {
+				return fmt.Errorf("host key of %s is %s, expected %s", hostname, got, fingerprint)
+			}
+			return nil
+		}, nil
 	}
 
-	return ssh.Dial("tcp", os.Getenv("SSH_HOST"), config)
+	if file == "" {
+		file = KnownHostsFile()
+	}
+	known, err := knownhosts.New(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read known hosts: %v (set SSH_KNOWN_HOSTS or pin the host key of the target)", err)
+	}
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := known(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if errors.As(err, &keyErr) {
+			if len(keyErr.Want) == 0 {
+				return fmt.Errorf("host key %s of %s is not in %s", ssh.FingerprintSHA256(key), hostname, file)
+			}
+			return fmt.Errorf("host key %s of %s does not match %s", ssh.FingerprintSHA256(key), hostname, file)
+		}
+		return err
+	}, nil
+}
+
+// GetOpenStackClient returns an authenticated OpenStack API client configured from clouds.yaml or OS_* variables
+func GetOpenStackClient() (*openstack.Client, error) {
+	config, err := openstack.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return openstack.NewClient(config, nil)
 }
 
 // PrettyPrintResult prints a formatted check result with clear visual separation
@@ -32,6 +171,13 @@ func PrettyPrintResult(result checklist.CheckResult) {
 	fmt.Printf("Description: %s\n", result.Description)
 	fmt.Printf("Result: %s\n", result.Result)
 	fmt.Printf("Details: %s\n", result.Details)
+	for _, evidence := range result.Evidence {
+		if evidence.Line > 0 {
+			fmt.Printf("Evidence: %s:%d: %s\n", evidence.Location, evidence.Line, evidence.Snippet)
+		} else {
+			fmt.Printf("Evidence: %s: %s\n", evidence.Location, evidence.Snippet)
+		}
+	}
 	fmt.Printf("Timestamp: %s\n", result.Timestamp)
 	fmt.Println(strings.Repeat("-", 100))
 }
@@ -79,7 +225,7 @@ func (c *SSHClient) Close() error {
 }
 
 // ExecuteScriptAndGetResult executes a shell script via SSH and returns the parsed CheckResult.
-// It captures and displays the full script output, then extracts and parses the JSON result
+// It logs the full script output to stderr, then extracts and parses the JSON result
 // from the last line of output.
 func ExecuteScriptAndGetResult(client *ssh.Client, scriptPath string, description string) checklist.CheckResult {
 	// Validate SSH client initialization
@@ -90,12 +236,12 @@ func ExecuteScriptAndGetResult(client *ssh.Client, scriptPath string, descriptio
 			Details:     "SSH client is nil",
 		}
 	}
-	fmt.Printf("[SSH] Connected to: %v\n", client.RemoteAddr())
+	fmt.Fprintf(os.Stderr, "[SSH] Connected to: %v\n", client.RemoteAddr())
 
 	// Create new SSH session
 	session, err := client.NewSession()
 	if err != nil {
-		fmt.Printf("[ERROR] Failed to create SSH session: %v\n", err)
+		fmt.Fprintf(os.Stderr, "[ERROR] Failed to create SSH session: %v\n", err)
 		return checklist.CheckResult{
 			Description: description,
 			Result:      "[ERROR]",
@@ -107,7 +253,7 @@ func ExecuteScriptAndGetResult(client *ssh.Client, scriptPath string, descriptio
 	// Get and validate script path
 	pwd, err := os.Getwd()
 	if err != nil {
-		fmt.Printf("[ERROR] Failed to get working directory: %v\n", err)
+		fmt.Fprintf(os.Stderr, "[ERROR] Failed to get working directory: %v\n", err)
 		return checklist.CheckResult{
 			Description: description,
 			Result:      "[ERROR]",
@@ -116,11 +262,11 @@ func ExecuteScriptAndGetResult(client *ssh.Client, scriptPath string, descriptio
 	}
 
 	fullScriptPath := filepath.Join(pwd, scriptPath)
-	fmt.Printf("[INFO] Executing script: %s\n", fullScriptPath)
+	fmt.Fprintf(os.Stderr, "[INFO] Executing script: %s\n", fullScriptPath)
 
 	// Check script existence
 	if _, err := os.Stat(fullScriptPath); os.IsNotExist(err) {
-		fmt.Printf("[ERROR] Script not found: %s\n", fullScriptPath)
+		fmt.Fprintf(os.Stderr, "[ERROR] Script not found: %s\n", fullScriptPath)
 		return checklist.CheckResult{
 			Description: description,
 			Result:      "[ERROR]",
@@ -131,7 +277,7 @@ func ExecuteScriptAndGetResult(client *ssh.Client, scriptPath string, descriptio
 	// Read script content
 	scriptContent, err := os.ReadFile(fullScriptPath)
 	if err != nil {
-		fmt.Printf("[ERROR] Failed to read script: %v\n", err)
+		fmt.Fprintf(os.Stderr, "[ERROR] Failed to read script: %v\n", err)
 		return checklist.CheckResult{
 			Description: description,
 			Result:      "[ERROR]",
@@ -142,7 +288,7 @@ func ExecuteScriptAndGetResult(client *ssh.Client, scriptPath string, descriptio
 	// Execute script and capture output directly
 	output, err := session.CombinedOutput(string(scriptContent))
 	if err != nil {
-		fmt.Printf("[ERROR] Script execution failed: %v\n", err)
+		fmt.Fprintf(os.Stderr, "[ERROR] Script execution failed: %v\n", err)
 		return checklist.CheckResult{
 			Description: description,
 			Result:      "[ERROR]",
@@ -150,8 +296,8 @@ func ExecuteScriptAndGetResult(client *ssh.Client, scriptPath string, descriptio
 		}
 	}
 
-	// Display full script output
-	fmt.Printf("[SCRIPT OUTPUT]\n%s", string(output))
+	// Log full script output to stderr so stdout only carries the report
+	fmt.Fprintf(os.Stderr, "[SCRIPT OUTPUT]\n%s", string(output))
 
 	// Extract JSON from last line and parse result
 	lines := strings.Split(strings.TrimSpace(string(output)), "\n")